@@ -3,9 +3,11 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func getEnvAsInt(name string, defaultVal int) int {
@@ -34,6 +36,16 @@ func getEnvAsBool(name string, defaultVal bool) bool {
 	return defaultVal
 }
 
+func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(name); exists {
+		durationValue, err := time.ParseDuration(value)
+		if err == nil {
+			return durationValue
+		}
+	}
+	return defaultVal
+}
+
 func parseLevel(s string) slog.Level {
 	var level slog.Level
 	err := level.UnmarshalText([]byte(s))
@@ -43,6 +55,34 @@ func parseLevel(s string) slog.Level {
 	return level
 }
 
+// parseList splits a comma separated list, blank entries are dropped.
+func parseList(s string) []string {
+	var list []string
+	for _, str := range strings.Split(s, ",") {
+		if str = strings.TrimSpace(str); str != "" {
+			list = append(list, str)
+		}
+	}
+	return list
+}
+
+// parsePrefixes parses addresses and CIDR ranges, an address is a range of its own.
+func parsePrefixes(list []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, str := range list {
+		if addr, err := netip.ParseAddr(str); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address or CIDR range: %s", str)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func newOriginParser(allowList *[]string, defaultVal string) func(s string) error {
 	return func(s string) error {
 		value := defaultVal
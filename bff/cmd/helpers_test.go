@@ -1,9 +1,11 @@
 package main
 
 import (
+	"net/netip"
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"testing"
 )
 
 var _ = Describe("newOriginParser helper function", func() {
@@ -60,6 +62,36 @@ var _ = Describe("newOriginParser helper function", func() {
 
 })
 
+var _ = Describe("parseList helper function", func() {
+	It("should split a comma separated list and trim its entries", func() {
+		Expect(parseList("alice, bob")).To(Equal([]string{"alice", "bob"}))
+	})
+
+	It("should drop blank entries", func() {
+		Expect(parseList(" ,alice,,")).To(Equal([]string{"alice"}))
+		Expect(parseList("")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("parsePrefixes helper function", func() {
+	It("should parse addresses and CIDR ranges", func() {
+		prefixes, err := parsePrefixes([]string{"10.128.0.1", "10.129.0.0/16", "fd00::1"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.128.0.1/32"),
+			netip.MustParsePrefix("10.129.0.0/16"),
+			netip.MustParsePrefix("fd00::1/128"),
+		}))
+	})
+
+	It("should reject anything else", func() {
+		_, err := parsePrefixes([]string{"router.local"})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 func TestMainHelpers(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Main helpers suite")
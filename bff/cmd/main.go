@@ -23,10 +23,33 @@ func main() {
 	flag.TextVar(&cfg.LogLevel, "log-level", parseLevel(getEnvAsString("LOG_LEVEL", "DEBUG")), "Sets server log level, possible values: error, warn, info, debug")
 	flag.Func("allowed-origins", "Sets allowed origins for CORS purposes, accepts a comma separated list of origins or * to allow all, default none", newOriginParser(&cfg.AllowedOrigins, getEnvAsString("ALLOWED_ORIGINS", "")))
 	flag.BoolVar(&cfg.MockLSClient, "mock-ls-client", false, "Use mock Llama Stack client")
+	var trustedProxies string
+	flag.StringVar(&trustedProxies, "trusted-proxies", getEnvAsString("TRUSTED_PROXIES", ""), "Comma separated list of addresses or CIDR ranges of the proxies whose X-Forwarded-For header is trusted")
 
 	// Llama Stack configuration
 	flag.StringVar(&cfg.LlamaStackURL, "llama-stack-url", getEnvAsString("LLAMA_STACK_URL", ""), "Llama Stack server URL for proxying requests")
 
+	// Chat configuration
+	flag.StringVar(&cfg.RAGPromptTemplate, "rag-prompt-template", getEnvAsString("RAG_PROMPT_TEMPLATE", config.DefaultRAGPromptTemplate), "Go template used to inject retrieved context into chat prompts, receives .Context and .Query")
+
+	flag.StringVar(&cfg.ModelProfilesPath, "model-profiles-path", getEnvAsString("MODEL_PROFILES_PATH", ""), "JSON file with sampling parameter defaults and allowed ranges per model")
+
+	// Upload configuration
+	flag.Int64Var(&cfg.MaxUploadSize, "max-upload-size", int64(getEnvAsInt("MAX_UPLOAD_SIZE", config.DefaultMaxUploadSize)), "Maximum size in bytes of multipart document uploads")
+
+	// Storage configuration
+	flag.StringVar(&cfg.ConversationStorePath, "conversation-store-path", getEnvAsString("CONVERSATION_STORE_PATH", ""), "JSON file chat conversations are persisted to, conversations are kept in memory when empty")
+	flag.StringVar(&cfg.PromptTemplateStorePath, "prompt-template-store-path", getEnvAsString("PROMPT_TEMPLATE_STORE_PATH", ""), "JSON file prompt templates are persisted to, templates are kept in memory when empty")
+
+	flag.StringVar(&cfg.VectorDBOwnerStorePath, "vector-db-owner-store-path", getEnvAsString("VECTOR_DB_OWNER_STORE_PATH", ""), "JSON file the owners of vector databases are persisted to, owners are kept in memory when empty")
+
+	// Share link configuration
+	flag.StringVar(&cfg.ShareLinkSecret, "share-link-secret", getEnvAsString("SHARE_LINK_SECRET", ""), "Secret signing chatbot share links, a random one is generated when empty")
+	flag.DurationVar(&cfg.ShareLinkMaxTTL, "share-link-max-ttl", getEnvAsDuration("SHARE_LINK_MAX_TTL", 30*24*time.Hour), "Maximum lifetime of a chatbot share link")
+	flag.StringVar(&cfg.ShareLinkStorePath, "share-link-store-path", getEnvAsString("SHARE_LINK_STORE_PATH", ""), "JSON file chatbot share links are persisted to, share links are kept in memory when empty")
+	flag.IntVar(&cfg.ShareLinkChatsPerMinute, "share-link-chats-per-minute", getEnvAsInt("SHARE_LINK_CHATS_PER_MINUTE", config.DefaultShareLinkChatsPerMinute), "Maximum number of chats each chatbot share link can start per minute")
+	flag.IntVar(&cfg.ShareLinkResolvesPerMinute, "share-link-resolves-per-minute", getEnvAsInt("SHARE_LINK_RESOLVES_PER_MINUTE", config.DefaultShareLinkResolvesPerMinute), "Maximum number of times each chatbot share link can be opened per minute")
+
 	// OAuth configuration
 	flag.BoolVar(&cfg.OAuthEnabled, "oauth-enabled", getEnvAsBool("OAUTH_ENABLED", false), "Enable OAuth authentication")
 	flag.StringVar(&cfg.OAuthClientID, "oauth-client-id", getEnvAsString("OAUTH_CLIENT_ID", ""), "OAuth client ID")
@@ -35,9 +58,14 @@ func main() {
 	flag.StringVar(&cfg.OAuthServerURL, "oauth-server-url", getEnvAsString("OAUTH_SERVER_URL", ""), "OAuth server URL")
 	flag.StringVar(&cfg.OpenShiftApiServerUrl, "openshift-api-server-url", getEnvAsString("OPENSHIFT_API_SERVER_URL", "https://kubernetes.default.svc.cluster.local"), "OpenShift API server URL for token validation")
 	flag.StringVar(&cfg.OAuthUserInfoEndpoint, "oauth-user-info-endpoint", getEnvAsString("OAUTH_USER_INFO_ENDPOINT", ""), "OAuth user info endpoint URL for token validation (optional, defaults to OpenShift API server + /apis/user.openshift.io/v1/users/~)")
+	var adminUsers string
+	flag.StringVar(&adminUsers, "admin-users", getEnvAsString("ADMIN_USERS", ""), "Comma separated list of users allowed to register and unregister models, to register tool groups and to read the usage of every user when OAuth is enabled")
+	flag.StringVar(&cfg.OAuthUsernameClaim, "oauth-username-claim", getEnvAsString("OAUTH_USERNAME_CLAIM", ""), "Dot separated path of the username in the user info response, e.g. email (optional, defaults to metadata.name, preferred_username or sub)")
 
 	flag.Parse()
 
+	cfg.AdminUsers = parseList(adminUsers)
+
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: cfg.LogLevel,
 	}))
@@ -45,6 +73,22 @@ func main() {
 	// Only use for logging errors about logging configuration.
 	slog.SetDefault(logger)
 
+	prefixes, err := parsePrefixes(parseList(trustedProxies))
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	cfg.TrustedProxies = prefixes
+
+	if cfg.ModelProfilesPath != "" {
+		profiles, err := config.LoadModelProfiles(cfg.ModelProfilesPath)
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		cfg.ModelProfiles = profiles
+	}
+
 	app, err := api.NewApp(cfg, slog.New(logger.Handler()))
 	if err != nil {
 		logger.Error(err.Error())
@@ -9,6 +9,7 @@ require (
 	github.com/onsi/gomega v1.36.2
 	github.com/rs/cors v1.11.1
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/net v0.38.0
 )
 
 require (
@@ -19,7 +20,6 @@ require (
 	github.com/google/pprof v0.0.0-20241210010833-40e02aabc2ad // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
-	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	golang.org/x/tools v0.28.0 // indirect
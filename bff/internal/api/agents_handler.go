@@ -0,0 +1,260 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type AgentEnvelope Envelope[models.Agent, None]
+type AgentSessionEnvelope Envelope[models.AgentSession, None]
+
+// AgentCreateRequest represents the request body for creating an agent
+type AgentCreateRequest struct {
+	Model         string                      `json:"model"`
+	Instructions  string                      `json:"instructions"`
+	Toolgroups    []llamastack.AgentToolGroup `json:"toolgroups,omitempty"`
+	InputShields  []string                    `json:"input_shields,omitempty"`
+	OutputShields []string                    `json:"output_shields,omitempty"`
+	MaxInferIters *int                        `json:"max_infer_iters,omitempty"`
+}
+
+// AgentSessionCreateRequest represents the request body for opening an agent session
+type AgentSessionCreateRequest struct {
+	SessionName string `json:"session_name"`
+}
+
+// AgentTurnCreateRequest represents the request body for creating an agent turn
+type AgentTurnCreateRequest struct {
+	Messages   []llamastack.Message        `json:"messages"`
+	Toolgroups []llamastack.AgentToolGroup `json:"toolgroups,omitempty"`
+}
+
+// Turns may only add user input or the results of client side tools.
+var agentTurnMessageRoles = []string{
+	llamastack.UserRole,
+	llamastack.ToolRole,
+}
+
+func (app *App) CreateAgentHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var createRequest AgentCreateRequest
+	if err := app.ReadJSON(w, r, &createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	validationErrors := map[string]string{}
+	if model := findModel(modelList, createRequest.Model); model == nil || model.ModelType != llamastack.LLMModelType {
+		validationErrors["model"] = fmt.Sprintf("model %q is not an available %s model", createRequest.Model, llamastack.LLMModelType)
+	}
+	if strings.TrimSpace(createRequest.Instructions) == "" {
+		validationErrors["instructions"] = "must not be empty"
+	}
+	for i, toolgroup := range createRequest.Toolgroups {
+		if toolgroup.Name == "" {
+			validationErrors[fmt.Sprintf("toolgroups[%d].name", i)] = "must not be empty"
+		}
+	}
+	if createRequest.MaxInferIters != nil && *createRequest.MaxInferIters <= 0 {
+		validationErrors["max_infer_iters"] = "must be greater than zero"
+	}
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	agentID, err := app.repositories.LlamaStackClient.CreateAgent(client, llamastack.AgentConfig{
+		Model:         createRequest.Model,
+		Instructions:  createRequest.Instructions,
+		Toolgroups:    createRequest.Toolgroups,
+		InputShields:  createRequest.InputShields,
+		OutputShields: createRequest.OutputShields,
+		MaxInferIters: createRequest.MaxInferIters,
+		// Session history is only retrievable from persisted sessions.
+		EnableSessionPersistence: true,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, AgentEnvelope{Data: models.Agent{AgentID: agentID}}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) CreateAgentSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var createRequest AgentSessionCreateRequest
+	if err := app.ReadJSON(w, r, &createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if strings.TrimSpace(createRequest.SessionName) == "" {
+		app.badRequestResponse(w, r, errors.New("session_name is required"))
+		return
+	}
+
+	sessionID, err := app.repositories.LlamaStackClient.CreateAgentSession(client, ps.ByName("agent_id"), createRequest.SessionName)
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	session := models.AgentSession{
+		SessionID:   sessionID,
+		SessionName: createRequest.SessionName,
+		Turns:       []models.AgentTurn{},
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, AgentSessionEnvelope{Data: session}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) CreateAgentTurnHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var turnRequest AgentTurnCreateRequest
+	if err := app.ReadJSON(w, r, &turnRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	validationErrors := map[string]string{}
+	if len(turnRequest.Messages) == 0 {
+		validationErrors["messages"] = "must contain at least one message"
+	}
+	for i, message := range turnRequest.Messages {
+		if !slices.Contains(agentTurnMessageRoles, message.Role) {
+			validationErrors[fmt.Sprintf("messages[%d].role", i)] = "must be one of " + strings.Join(agentTurnMessageRoles, ", ")
+		}
+		if strings.TrimSpace(message.Content) == "" {
+			validationErrors[fmt.Sprintf("messages[%d].content", i)] = "must not be empty"
+		}
+	}
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	// The agent names the model the turn is metered against.
+	agent, err := app.repositories.LlamaStackClient.GetAgent(client, ps.ByName("agent_id"))
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	r, done := app.startGeneration(w, r)
+	defer done()
+
+	started := time.Now()
+	stream, err := app.repositories.LlamaStackClient.StreamAgentTurn(r.Context(), client, ps.ByName("agent_id"), ps.ByName("session_id"), llamastack.AgentTurnCreateRequest{
+		Messages:   turnRequest.Messages,
+		Toolgroups: turnRequest.Toolgroups,
+	})
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	relayEventStream(app, r, newSSEWriter(w), app.meterAgentTurn(r, agent, turnRequest.Messages, started, stream))
+}
+
+func (app *App) GetAgentSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	session, err := app.repositories.LlamaStackClient.GetAgentSession(client, ps.ByName("agent_id"), ps.ByName("session_id"))
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, AgentSessionEnvelope{Data: convertAgentSession(session)}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func convertAgentSession(session *llamastack.Session) models.AgentSession {
+	turns := []models.AgentTurn{}
+	for _, turn := range session.Turns {
+		inputMessages := []models.AgentMessage{}
+		for _, message := range turn.InputMessages {
+			inputMessages = append(inputMessages, convertAgentMessage(message))
+		}
+
+		agentTurn := models.AgentTurn{
+			TurnID:        turn.TurnID,
+			InputMessages: inputMessages,
+			Steps:         turn.Steps,
+			StartedAt:     turn.StartedAt,
+			CompletedAt:   turn.CompletedAt,
+		}
+		if turn.OutputMessage != nil {
+			outputMessage := convertAgentMessage(*turn.OutputMessage)
+			agentTurn.OutputMessage = &outputMessage
+		}
+
+		turns = append(turns, agentTurn)
+	}
+
+	return models.AgentSession{
+		SessionID:   session.SessionID,
+		SessionName: session.SessionName,
+		StartedAt:   session.StartedAt,
+		Turns:       turns,
+	}
+}
+
+func convertAgentMessage(message llamastack.TurnMessage) models.AgentMessage {
+	return models.AgentMessage{
+		Role:       message.Role,
+		Content:    string(message.Content),
+		StopReason: message.StopReason,
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentTurnRoundTrip(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.CreateAgentHandler(rr, newTestRequest(t, http.MethodPost, AgentListPath, AgentCreateRequest{
+		Model:        "default-model-id-1",
+		Instructions: "You are a helpful assistant",
+		InputShields: []string{"llama-guard"},
+	}), nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var agent AgentEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&agent))
+
+	agentParams := httprouter.Params{{Key: "agent_id", Value: agent.Data.AgentID}}
+
+	rr = httptest.NewRecorder()
+	app.CreateAgentSessionHandler(rr, newTestRequest(t, http.MethodPost, AgentSessionListPath, AgentSessionCreateRequest{
+		SessionName: "playground",
+	}), agentParams)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var session AgentSessionEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&session))
+
+	sessionParams := append(agentParams, httprouter.Param{Key: "session_id", Value: session.Data.SessionID})
+
+	rr = httptest.NewRecorder()
+	app.CreateAgentTurnHandler(rr, newTestRequest(t, http.MethodPost, AgentTurnListPath, AgentTurnCreateRequest{
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "hello agent"}},
+	}), sessionParams)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var stepTypes []string
+	events := parseTestSSEEvents(rr.Body.String())
+	for _, event := range events {
+		var chunk llamastack.AgentTurnResponseStreamChunk
+		assert.NoError(t, json.Unmarshal([]byte(event.data), &chunk))
+		if chunk.Event.Payload.EventType == llamastack.StepStartEventType {
+			stepTypes = append(stepTypes, chunk.Event.Payload.StepType)
+		}
+	}
+	assert.Equal(t, []string{llamastack.ShieldCallStepType, llamastack.InferenceStepType}, stepTypes)
+
+	rr = httptest.NewRecorder()
+	app.GetAgentSessionHandler(rr, newTestRequest(t, http.MethodGet, AgentSessionPath, nil), sessionParams)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var history AgentSessionEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&history))
+	assert.Len(t, history.Data.Turns, 1)
+	assert.Equal(t, "hello agent", history.Data.Turns[0].InputMessages[0].Content)
+	assert.Len(t, history.Data.Turns[0].Steps, 2)
+}
+
+func TestAgentSessionNotFound(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.GetAgentSessionHandler(rr, newTestRequest(t, http.MethodGet, AgentSessionPath, nil), httprouter.Params{
+		{Key: "agent_id", Value: "missing"},
+		{Key: "session_id", Value: "missing"},
+	})
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
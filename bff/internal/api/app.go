@@ -5,7 +5,9 @@ import (
 	"log/slog"
 	"net/http"
 	"path"
+	"text/template"
 
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/auth"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/mocks"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
 
@@ -25,24 +27,78 @@ const (
 
 	ConfigPath = ApiPathPrefix + "/config"
 
-	ModelListPath    = ApiPathPrefix + "/models"
+	ModelListPath = ApiPathPrefix + "/models"
+	// ModelPath serves the model itself and its sub resources
+	ModelPath             = ModelListPath + "/*model_path"
+	ModelParametersSuffix = "/parameters"
+
 	VectorDBListPath = ApiPathPrefix + "/vector-dbs"
+	VectorDBPath     = VectorDBListPath + "/:vector_db_id"
 
 	// making it simpler than /tool-runtime/rag-tool/insert
-	UploadPath = ApiPathPrefix + "/upload"
+	UploadPath        = ApiPathPrefix + "/upload"
+	UploadPreviewPath = UploadPath + "/preview"
+	// making it simpler than /tool-runtime/rag-tool/query
+	QueryPath = ApiPathPrefix + "/query"
+
+	ChatCompletionsPath = ApiPathPrefix + "/chat/completions"
+	EmbeddingsPath      = ApiPathPrefix + "/embeddings"
+	ComparePath         = ApiPathPrefix + "/compare"
+	// Streamed generations are identified by the GenerationIDHeader of their response.
+	GenerationPath = ApiPathPrefix + "/generations/:generation_id"
+
+	ConversationListPath     = ApiPathPrefix + "/conversations"
+	ConversationPath         = ConversationListPath + "/:conversation_id"
+	ConversationMessagesPath = ConversationPath + "/messages"
+	ConversationExportPath   = ConversationPath + "/export"
+	// Exports transcripts supplied in the request body, they are not stored.
+	TranscriptExportPath = ApiPathPrefix + "/transcripts/export"
+
+	AgentListPath        = ApiPathPrefix + "/agents"
+	AgentSessionListPath = AgentListPath + "/:agent_id/sessions"
+	AgentSessionPath     = AgentSessionListPath + "/:session_id"
+	AgentTurnListPath    = AgentSessionPath + "/turns"
+
+	ToolGroupListPath = ApiPathPrefix + "/toolgroups"
+	ToolListPath      = ApiPathPrefix + "/tools"
+
+	ShieldListPath = ApiPathPrefix + "/shields"
+
+	ProviderListPath = ApiPathPrefix + "/providers"
+
+	UsagePath = ApiPathPrefix + "/usage"
+
+	ShareLinkListPath = ApiPathPrefix + "/share-links"
+	ShareLinkPath     = ShareLinkListPath + "/:share_link_id"
+	// Public routes, the signed share token is the credential. Chats are limited per link.
+	SharedChatbotPath         = ApiPathPrefix + "/shared/:share_token"
+	SharedChatCompletionsPath = SharedChatbotPath + "/chat/completions"
+
+	PromptTemplateListPath     = ApiPathPrefix + "/prompts"
+	PromptTemplatePath         = PromptTemplateListPath + "/:prompt_id"
+	PromptTemplateVersionsPath = PromptTemplatePath + "/versions"
+	PromptTemplateRenderPath   = PromptTemplatePath + "/render"
 )
 
 type App struct {
-	config       config.EnvConfig
-	logger       *slog.Logger
-	repositories *repositories.Repositories
+	config            config.EnvConfig
+	logger            *slog.Logger
+	repositories      *repositories.Repositories
+	ragPromptTemplate *template.Template
+	// proxyTokenCache remembers the users of tokens sent to the unprotected proxy, which does
+	// not validate them itself.
+	proxyTokenCache *auth.TokenCache
 }
 
 func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 	var lsClient repositories.LlamaStackClientInterface
 	var err error
 
-	logger.Info("Initializing app with config", slog.Any("config", cfg))
+	loggedConfig := cfg
+	if loggedConfig.ShareLinkSecret != "" {
+		loggedConfig.ShareLinkSecret = "[REDACTED]"
+	}
+	logger.Info("Initializing app with config", slog.Any("config", loggedConfig))
 
 	// Validate OAuth configuration
 	if cfg.OAuthEnabled {
@@ -58,6 +114,9 @@ func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 		if cfg.OAuthRedirectURI == "" {
 			return nil, fmt.Errorf("OAUTH_REDIRECT_URI is required when OAuth is enabled")
 		}
+		if len(cfg.AdminUsers) == 0 {
+			logger.Warn("No admin users configured, models and tool groups cannot be changed")
+		}
 		logger.Info("OAuth configuration validated",
 			slog.String("oauth_server_url", cfg.OAuthServerURL),
 			slog.String("openshift_api_server_url", cfg.OpenShiftApiServerUrl))
@@ -73,10 +132,71 @@ func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 		return nil, fmt.Errorf("failed to create llama stack client: %w", err)
 	}
 
+	ragPromptTemplate, err := parseRAGPromptTemplate(cfg.RAGPromptTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RAG prompt template: %w", err)
+	}
+
+	repos := repositories.NewRepositories(lsClient)
+
+	if cfg.ConversationStorePath != "" {
+		conversationStore, err := repositories.NewFileConversationStore(cfg.ConversationStorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open conversation store: %w", err)
+		}
+		repos.Conversations = repositories.NewConversationRepository(conversationStore)
+	} else {
+		logger.Warn("No conversation store path configured, conversations are kept in memory only")
+	}
+
+	if cfg.PromptTemplateStorePath != "" {
+		promptTemplateStore, err := repositories.NewFilePromptTemplateStore(cfg.PromptTemplateStorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open prompt template store: %w", err)
+		}
+		repos.PromptTemplates = repositories.NewPromptTemplateRepository(promptTemplateStore)
+	} else {
+		logger.Warn("No prompt template store path configured, prompt templates are kept in memory only")
+	}
+
+	if cfg.VectorDBOwnerStorePath != "" {
+		vectorDBOwnerStore, err := repositories.NewFileVectorDBOwnerStore(cfg.VectorDBOwnerStorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open vector database owner store: %w", err)
+		}
+		repos.VectorDBOwners = repositories.NewVectorDBOwnerRepository(vectorDBOwnerStore)
+	} else if cfg.OAuthEnabled {
+		logger.Warn("No vector database owner store path configured, vector databases cannot be unregistered after a restart")
+	}
+
+	shareLinkStore := repositories.NewMemoryShareLinkStore()
+	if cfg.ShareLinkStorePath != "" {
+		shareLinkStore, err = repositories.NewFileShareLinkStore(cfg.ShareLinkStorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open share link store: %w", err)
+		}
+	} else {
+		logger.Warn("No share link store path configured, share links are kept in memory only")
+	}
+	if cfg.ShareLinkSecret == "" {
+		logger.Warn("No share link secret configured, existing share tokens are invalidated when the BFF restarts")
+	}
+	shareLinkChatsPerMinute := cfg.ShareLinkChatsPerMinute
+	if shareLinkChatsPerMinute <= 0 {
+		shareLinkChatsPerMinute = config.DefaultShareLinkChatsPerMinute
+	}
+	shareLinkResolvesPerMinute := cfg.ShareLinkResolvesPerMinute
+	if shareLinkResolvesPerMinute <= 0 {
+		shareLinkResolvesPerMinute = config.DefaultShareLinkResolvesPerMinute
+	}
+	repos.ShareLinks = repositories.NewShareLinkRepository(shareLinkStore, []byte(cfg.ShareLinkSecret), shareLinkChatsPerMinute, shareLinkResolvesPerMinute)
+
 	app := &App{
-		config:       cfg,
-		logger:       logger,
-		repositories: repositories.NewRepositories(lsClient),
+		config:            cfg,
+		logger:            logger,
+		repositories:      repos,
+		ragPromptTemplate: ragPromptTemplate,
+		proxyTokenCache:   auth.NewTokenCache(proxyTokenCacheTTL, maxProxyTokenCacheEntries),
 	}
 	return app, nil
 }
@@ -98,11 +218,82 @@ func (app *App) Routes() http.Handler {
 	apiRouter.GET(ConfigPath, app.HandleConfig)
 
 	apiRouter.GET(ModelListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllModelsHandler)))
+	apiRouter.GET(ModelPath, app.RequireAuthRoute(app.AttachRESTClient(app.ModelHandler)))
+	// POST to register a model, DELETE to unregister it (/v1/models). Models are shared by all
+	// users, only admins can change them.
+	apiRouter.POST(ModelListPath, app.RequireAuthRoute(app.RequireAdminRoute(app.AttachRESTClient(app.RegisterModelHandler))))
+	apiRouter.DELETE(ModelPath, app.RequireAuthRoute(app.RequireAdminRoute(app.AttachRESTClient(app.UnregisterModelHandler))))
 	apiRouter.GET(VectorDBListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllVectorDBsHandler)))
 
 	// POST to register the vectorDB (/v1/vector-dbs)
 	apiRouter.POST(VectorDBListPath, app.RequireAuthRoute(app.AttachRESTClient(app.RegisterVectorDBHandler)))
+	apiRouter.GET(VectorDBPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetVectorDBHandler)))
+	// DELETE to unregister the vectorDB, only its owner may when OAuth is enabled
+	apiRouter.DELETE(VectorDBPath, app.RequireAuthRoute(app.AttachRESTClient(app.UnregisterVectorDBHandler)))
+	// GET the providers, ?api=vector_io for the ones vector databases can be registered with (/v1/providers)
+	apiRouter.GET(ProviderListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllProvidersHandler)))
 	apiRouter.POST(UploadPath, app.RequireAuthRoute(app.AttachRESTClient(app.UploadHandler)))
+	// POST to see the chunks an upload would store, Llama Stack is not called
+	apiRouter.POST(UploadPreviewPath, app.RequireAuthRoute(app.UploadPreviewHandler))
+	apiRouter.POST(QueryPath, app.RequireAuthRoute(app.AttachRESTClient(app.QueryHandler)))
+
+	// POST to stream a chat completion back as server-sent events (/v1/inference/chat-completion)
+	apiRouter.POST(ChatCompletionsPath, app.RequireAuthRoute(app.AttachRESTClient(app.ChatCompletionHandler)))
+
+	// POST to stream the chat completions of several models side by side
+	apiRouter.POST(ComparePath, app.RequireAuthRoute(app.AttachRESTClient(app.CompareHandler)))
+
+	// DELETE to cancel a chat completion, comparison or agent turn that is still streaming
+	apiRouter.DELETE(GenerationPath, app.RequireAuthRoute(app.CancelGenerationHandler))
+
+	// POST to embed texts with an embedding model (/v1/inference/embeddings)
+	apiRouter.POST(EmbeddingsPath, app.RequireAuthRoute(app.AttachRESTClient(app.EmbeddingsHandler)))
+
+	// Conversations are stored by the BFF and scoped to the authenticated user
+	apiRouter.GET(ConversationListPath, app.RequireAuthRoute(app.GetAllConversationsHandler))
+	apiRouter.POST(ConversationListPath, app.RequireAuthRoute(app.CreateConversationHandler))
+	apiRouter.GET(ConversationPath, app.RequireAuthRoute(app.GetConversationHandler))
+	apiRouter.PATCH(ConversationPath, app.RequireAuthRoute(app.UpdateConversationHandler))
+	apiRouter.DELETE(ConversationPath, app.RequireAuthRoute(app.DeleteConversationHandler))
+	apiRouter.POST(ConversationMessagesPath, app.RequireAuthRoute(app.AppendConversationMessagesHandler))
+	apiRouter.GET(ConversationExportPath, app.RequireAuthRoute(app.ExportConversationHandler))
+	apiRouter.POST(TranscriptExportPath, app.RequireAuthRoute(app.ExportTranscriptHandler))
+
+	// Agents (/v1/agents), turns are streamed back as server-sent events
+	apiRouter.POST(AgentListPath, app.RequireAuthRoute(app.AttachRESTClient(app.CreateAgentHandler)))
+	apiRouter.POST(AgentSessionListPath, app.RequireAuthRoute(app.AttachRESTClient(app.CreateAgentSessionHandler)))
+	apiRouter.GET(AgentSessionPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAgentSessionHandler)))
+	apiRouter.POST(AgentTurnListPath, app.RequireAuthRoute(app.AttachRESTClient(app.CreateAgentTurnHandler)))
+
+	// Tool discovery (/v1/toolgroups, /v1/tools)
+	apiRouter.GET(ToolGroupListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllToolGroupsHandler)))
+	// Tool groups are shared by all users and Llama Stack connects to their MCP endpoints, only
+	// admins can register them.
+	apiRouter.POST(ToolGroupListPath, app.RequireAuthRoute(app.RequireAdminRoute(app.AttachRESTClient(app.RegisterToolGroupHandler))))
+	apiRouter.GET(ToolListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllToolsHandler)))
+
+	apiRouter.GET(ShieldListPath, app.RequireAuthRoute(app.AttachRESTClient(app.GetAllShieldsHandler)))
+
+	// Admins get the usage of every user, other users only their own
+	apiRouter.GET(UsagePath, app.RequireAuthRoute(app.GetUsageHandler))
+
+	// Prompt templates are stored by the BFF and shared by all users, only their creator can
+	// PATCH or DELETE them
+	apiRouter.GET(PromptTemplateListPath, app.RequireAuthRoute(app.GetAllPromptTemplatesHandler))
+	apiRouter.POST(PromptTemplateListPath, app.RequireAuthRoute(app.CreatePromptTemplateHandler))
+	apiRouter.GET(PromptTemplatePath, app.RequireAuthRoute(app.GetPromptTemplateHandler))
+	apiRouter.PATCH(PromptTemplatePath, app.RequireAuthRoute(app.UpdatePromptTemplateHandler))
+	apiRouter.DELETE(PromptTemplatePath, app.RequireAuthRoute(app.DeletePromptTemplateHandler))
+	apiRouter.GET(PromptTemplateVersionsPath, app.RequireAuthRoute(app.GetPromptTemplateVersionsHandler))
+	apiRouter.POST(PromptTemplateRenderPath, app.RequireAuthRoute(app.RenderPromptTemplateHandler))
+
+	// Share links snapshot a chatbot configuration, the shared routes are public
+	apiRouter.GET(ShareLinkListPath, app.RequireAuthRoute(app.GetAllShareLinksHandler))
+	apiRouter.POST(ShareLinkListPath, app.RequireAuthRoute(app.AttachRESTClient(app.CreateShareLinkHandler)))
+	apiRouter.GET(ShareLinkPath, app.RequireAuthRoute(app.GetShareLinkHandler))
+	apiRouter.DELETE(ShareLinkPath, app.RequireAuthRoute(app.RevokeShareLinkHandler))
+	apiRouter.GET(SharedChatbotPath, app.GetSharedChatbotHandler)
+	apiRouter.POST(SharedChatCompletionsPath, app.AttachRESTClient(app.SharedChatCompletionHandler))
 
 	// App Router
 	appMux := http.NewServeMux()
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/chunking"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+// Context strategies decide what happens to the oldest turns of a conversation that no longer
+// fits the context window of the model. System messages at the start of the conversation and the
+// last message are always kept.
+const (
+	// TruncateContextStrategy drops the oldest turns, it is the default.
+	TruncateContextStrategy = "truncate"
+	// SummarizeContextStrategy replaces the oldest turns with a summary written by the model,
+	// falling back to dropping them when the summary cannot be generated.
+	SummarizeContextStrategy = "summarize"
+	// NoneContextStrategy sends the conversation as is.
+	NoneContextStrategy = "none"
+)
+
+var contextStrategies = []string{TruncateContextStrategy, SummarizeContextStrategy, NoneContextStrategy}
+
+// MessagesTrimmedHeader reports how many messages were dropped or summarized to fit the context
+// window of the model, it is only sent when messages were trimmed.
+const MessagesTrimmedHeader = "X-Messages-Trimmed"
+
+const (
+	// Token counts are estimated, the same way as for chunks.
+	charsPerToken = chunking.CharsPerToken
+	// Accounts for the role markers the chat template wraps every message in.
+	messageTokenOverhead = 4
+	// Kept free for the answer when the request does not set max_tokens.
+	defaultCompletionTokenReserve = 1024
+	summaryMaxTokens              = 256
+)
+
+const summarizePrompt = "Summarize the conversation below in a few sentences. Keep names, facts and decisions the rest of the conversation may refer to. Answer with the summary only."
+
+const summaryMessagePrefix = "Summary of the earlier conversation:\n"
+
+// estimateTokens estimates the number of tokens text is encoded to.
+func estimateTokens(text string) int {
+	return chunking.EstimateTokens(text)
+}
+
+func estimateMessagesTokens(messages []llamastack.Message) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += messageTokenOverhead + estimateTokens(message.Content)
+	}
+	return tokens
+}
+
+// modelContextLength returns the context length of model in tokens, the model profile takes
+// precedence over the model metadata. Zero means the context length is unknown.
+func (app *App) modelContextLength(model *llamastack.Model) int {
+	if contextLength := app.config.ModelProfiles.ProfileFor(model.Identifier).ContextLength; contextLength > 0 {
+		return contextLength
+	}
+
+	return int(metadataInt(model, "context_length"))
+}
+
+// promptTokenBudget returns how many tokens of the context window the messages may take, the
+// rest is kept for the answer.
+func promptTokenBudget(contextLength int, samplingParams models.SamplingParams) int {
+	reserve := min(defaultCompletionTokenReserve, contextLength/4)
+	if samplingParams.MaxTokens != nil {
+		reserve = *samplingParams.MaxTokens
+	}
+	return contextLength - reserve
+}
+
+// leadingSystemMessages returns the number of system messages messages starts with, not counting
+// the last message.
+func leadingSystemMessages(messages []llamastack.Message) int {
+	n := 0
+	for n < len(messages)-1 && messages[n].Role == llamastack.SystemRole {
+		n++
+	}
+	return n
+}
+
+// trimMessages drops the oldest messages until the estimated size of messages is within budget.
+// Whole turns, starting at a user message, are dropped first, the leading system messages and the
+// last message are never dropped. It returns the messages kept and the messages dropped, ok is
+// false when messages do not fit even after trimming.
+func trimMessages(messages []llamastack.Message, budget int) (kept []llamastack.Message, dropped []llamastack.Message, ok bool) {
+	if estimateMessagesTokens(messages) <= budget {
+		return messages, nil, true
+	}
+
+	systemEnd := leadingSystemMessages(messages)
+	system := messages[:systemEnd]
+	history := messages[systemEnd:]
+
+	tokens := estimateMessagesTokens(messages)
+	drop := 0
+	dropTo := func(end int) {
+		tokens -= estimateMessagesTokens(history[drop:end])
+		drop = end
+	}
+
+	// Drop whole turns while a later turn is left.
+	for tokens > budget {
+		next := drop + 1
+		for next < len(history) && history[next].Role != llamastack.UserRole {
+			next++
+		}
+		if next >= len(history) {
+			break
+		}
+		dropTo(next)
+	}
+
+	// The last turn alone is too large, only its last message has to stay.
+	for tokens > budget && drop < len(history)-1 {
+		dropTo(drop + 1)
+	}
+
+	kept = append(append([]llamastack.Message{}, system...), history[drop:]...)
+	return kept, history[:drop], tokens <= budget
+}
+
+// fitContextWindow applies strategy to messages so they fit the context window of model. It
+// returns the messages to send and the number of messages trimmed, messages are returned as is
+// when the context length of the model is unknown. ok is false when the messages cannot be made
+// to fit.
+func (app *App) fitContextWindow(r *http.Request, client integrations.HTTPClientInterface, model *llamastack.Model, strategy string, samplingParams models.SamplingParams, messages []llamastack.Message) ([]llamastack.Message, int, bool) {
+	contextLength := app.modelContextLength(model)
+	if strategy == NoneContextStrategy || contextLength == 0 {
+		return messages, 0, true
+	}
+
+	budget := promptTokenBudget(contextLength, samplingParams)
+	if strategy != SummarizeContextStrategy {
+		kept, dropped, ok := trimMessages(messages, budget)
+		return kept, len(dropped), ok
+	}
+
+	if estimateMessagesTokens(messages) <= budget {
+		return messages, 0, true
+	}
+
+	// Room is made for the summary before it is known how long it turns out.
+	summaryTokens := messageTokenOverhead + estimateTokens(summaryMessagePrefix) + summaryMaxTokens
+	kept, dropped, ok := trimMessages(messages, budget-summaryTokens)
+	if !ok || len(dropped) == 0 {
+		return kept, len(dropped), ok
+	}
+
+	summary, err := app.summarizeMessages(r, client, model.Identifier, contextLength, dropped)
+	if err != nil {
+		app.LogError(r, fmt.Errorf("failed to summarize trimmed messages, dropping them instead: %w", err))
+		return kept, len(dropped), true
+	}
+
+	systemEnd := leadingSystemMessages(kept)
+	summarized := make([]llamastack.Message, 0, len(kept)+1)
+	summarized = append(summarized, kept[:systemEnd]...)
+	summarized = append(summarized, llamastack.Message{Role: llamastack.SystemRole, Content: summaryMessagePrefix + summary})
+	summarized = append(summarized, kept[systemEnd:]...)
+
+	return summarized, len(dropped), true
+}
+
+// summarizeMessages asks modelID for a summary of messages. When the transcript does not fit the
+// context window its oldest part is left out.
+func (app *App) summarizeMessages(r *http.Request, client integrations.HTTPClientInterface, modelID string, contextLength int, messages []llamastack.Message) (string, error) {
+	var transcript strings.Builder
+	for _, message := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", message.Role, message.Content)
+	}
+
+	text := transcript.String()
+	maxRunes := (contextLength - summaryMaxTokens - estimateMessagesTokens([]llamastack.Message{{Content: summarizePrompt}}) - messageTokenOverhead) * charsPerToken
+	if maxRunes <= 0 {
+		return "", fmt.Errorf("context length %d of model %s leaves no room for a summary", contextLength, modelID)
+	}
+	if runes := []rune(text); len(runes) > maxRunes {
+		text = string(runes[len(runes)-maxRunes:])
+	}
+
+	maxTokens := summaryMaxTokens
+	started := time.Now()
+	stream, err := app.repositories.LlamaStackClient.StreamChatCompletion(r.Context(), client, llamastack.ChatCompletionRequest{
+		ModelID: modelID,
+		Messages: []llamastack.Message{
+			{Role: llamastack.SystemRole, Content: summarizePrompt},
+			{Role: llamastack.UserRole, Content: text},
+		},
+		SamplingParams: convertSamplingParams(models.SamplingParams{MaxTokens: &maxTokens}),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	chunks, err := collectEventStream(app.meterChatCompletion(r, modelID, started, stream))
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(chatCompletionText(chunks))
+	if summary == "" {
+		return "", fmt.Errorf("model %s returned an empty summary", modelID)
+	}
+	return summary, nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+// longTestConversation returns a system prompt followed by turns user and assistant messages of
+// about 100 tokens each, and a last short user message.
+func longTestConversation(turns int) []llamastack.Message {
+	filler := strings.Repeat("word ", 80)
+
+	messages := []llamastack.Message{{Role: llamastack.SystemRole, Content: "You are a helpful assistant."}}
+	for i := 0; i < turns; i++ {
+		messages = append(messages,
+			llamastack.Message{Role: llamastack.UserRole, Content: filler},
+			llamastack.Message{Role: llamastack.AssistantRole, Content: filler},
+		)
+	}
+	return append(messages, llamastack.Message{Role: llamastack.UserRole, Content: "and now?"})
+}
+
+func TestTrimMessages(t *testing.T) {
+	messages := longTestConversation(5)
+
+	kept, dropped, ok := trimMessages(messages, estimateMessagesTokens(messages))
+	assert.True(t, ok)
+	assert.Empty(t, dropped)
+	assert.Equal(t, messages, kept)
+
+	kept, dropped, ok = trimMessages(messages, 500)
+	assert.True(t, ok)
+	assert.LessOrEqual(t, estimateMessagesTokens(kept), 500)
+	// Whole turns are dropped, the system prompt and the last message are kept.
+	assert.Len(t, dropped, 6)
+	assert.Equal(t, messages[0], kept[0])
+	assert.Equal(t, llamastack.UserRole, kept[1].Role)
+	assert.Equal(t, messages[len(messages)-1], kept[len(kept)-1])
+
+	_, _, ok = trimMessages(messages, 10)
+	assert.False(t, ok)
+}
+
+func TestMetadataInt(t *testing.T) {
+	model := &llamastack.Model{Metadata: map[string]any{
+		"context_length":      8192.0,
+		"embedding_dimension": "384",
+		"description":         "not a number",
+	}}
+
+	assert.Equal(t, int64(8192), metadataInt(model, "context_length"))
+	assert.Equal(t, int64(384), metadataInt(model, "embedding_dimension"))
+	assert.Zero(t, metadataInt(model, "description"))
+	assert.Zero(t, metadataInt(model, "missing"))
+	assert.Zero(t, metadataInt(&llamastack.Model{}, "context_length"))
+}
+
+func TestChatCompletionHandlerTrimsHistory(t *testing.T) {
+	app := newTestApp()
+	app.config.ModelProfiles = config.ModelProfiles{
+		"default-model-id-1": {ContextLength: 800},
+	}
+
+	// The summary takes room too, so summarizing leaves out one more turn.
+	for strategy, trimmed := range map[string]string{TruncateContextStrategy: "16", SummarizeContextStrategy: "18"} {
+		t.Run(strategy, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+				ModelID:         "default-model-id-1",
+				Messages:        longTestConversation(10),
+				ContextStrategy: strategy,
+			})
+			app.ChatCompletionHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, trimmed, rr.Header().Get(MessagesTrimmedHeader))
+		})
+	}
+
+	// Short conversations are left alone.
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:  "default-model-id-1",
+		Messages: longTestConversation(1),
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get(MessagesTrimmedHeader))
+}
+
+func TestChatCompletionHandlerContextTooSmall(t *testing.T) {
+	app := newTestApp()
+	app.config.ModelProfiles = config.ModelProfiles{
+		"default-model-id-1": {ContextLength: 800},
+	}
+
+	messages := longTestConversation(1)
+	messages[len(messages)-1].Content = strings.Repeat("word ", 1000)
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:  "default-model-id-1",
+		Messages: messages,
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Contains(t, envelope.Error.Message, "context window")
+}
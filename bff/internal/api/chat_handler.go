@@ -0,0 +1,382 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+// ChatCompletionRequest represents the request body for a BFF chat completion
+type ChatCompletionRequest struct {
+	ModelID  string               `json:"model_id"`
+	Messages []llamastack.Message `json:"messages"`
+	// When set, context retrieved from these vector databases is injected into the last user
+	// message and the chunks used are sent as citations once the answer is complete.
+	VectorDBIDs []string `json:"vector_db_ids,omitempty"`
+	// Input shields check the messages before inference, output shields check the answer.
+	// With output shields the answer is only sent once it passed all of them.
+	InputShields  []string `json:"input_shields,omitempty"`
+	OutputShields []string `json:"output_shields,omitempty"`
+	// When set, the rendered library template is sent as the system message.
+	PromptTemplate *PromptTemplateSelection `json:"prompt_template,omitempty"`
+	// Merged over the defaults of the model profile, values must be within its ranges.
+	SamplingParams *models.SamplingParams `json:"sampling_params,omitempty"`
+	// How a conversation exceeding the context window is trimmed, one of contextStrategies.
+	// Defaults to TruncateContextStrategy.
+	ContextStrategy string `json:"context_strategy,omitempty"`
+	// When set the answer is constrained to JSON matching the schema, the parsed answer or the
+	// schema violations are sent once it is complete.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// chatRequestResources holds the Llama Stack resources a chat request is validated against,
+// lists other than the models are only fetched when the request refers to them.
+type chatRequestResources struct {
+	models    *llamastack.ModelList
+	vectorDBs *llamastack.VectorDBList
+	shields   *llamastack.ShieldList
+	// The selected prompt template version, nil when missing.
+	promptTemplateVersion *models.PromptTemplateVersion
+}
+
+var chatMessageRoles = []string{
+	llamastack.SystemRole,
+	llamastack.UserRole,
+	llamastack.AssistantRole,
+	llamastack.ToolRole,
+}
+
+func (app *App) ChatCompletionHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var chatRequest ChatCompletionRequest
+	if err := app.ReadJSON(w, r, &chatRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	app.serveChatCompletion(w, r, client, chatRequest)
+}
+
+// serveChatCompletion validates chatRequest and streams its completion, it backs both the
+// authenticated chat route and the chat route of share links.
+func (app *App) serveChatCompletion(w http.ResponseWriter, r *http.Request, client integrations.HTTPClientInterface, chatRequest ChatCompletionRequest) {
+	resources, err := app.loadChatRequestResources(client, chatRequest)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := app.validateChatCompletionRequest(chatRequest, resources); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	if len(chatRequest.InputShields) > 0 {
+		violation, err := app.runShields(r, client, chatRequest.InputShields, chatRequest.Messages, InputShieldStage)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		if violation != nil {
+			app.safetyViolationResponse(w, r, *violation)
+			return
+		}
+	}
+
+	messages := chatRequest.Messages
+	if chatRequest.PromptTemplate != nil {
+		messages = withPromptTemplate(messages, *chatRequest.PromptTemplate, *resources.promptTemplateVersion)
+	}
+
+	var citations []models.RAGChunk
+	if len(chatRequest.VectorDBIDs) > 0 {
+		messages, citations, err = app.augmentWithRetrievedContext(client, messages, chatRequest.VectorDBIDs)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	samplingParams := app.config.ModelProfiles.ProfileFor(chatRequest.ModelID).Defaults
+	if chatRequest.SamplingParams != nil {
+		samplingParams = samplingParams.Merge(*chatRequest.SamplingParams)
+	}
+
+	r, done := app.startGeneration(w, r)
+	defer done()
+
+	messages, trimmed, ok := app.fitContextWindow(r, client, findModel(resources.models, chatRequest.ModelID), chatRequest.ContextStrategy, samplingParams, messages)
+	if !ok {
+		app.failedValidationResponse(w, r, map[string]string{
+			"messages": fmt.Sprintf("do not fit the context window of model %q even after trimming the oldest messages", chatRequest.ModelID),
+		})
+		return
+	}
+	if trimmed > 0 {
+		w.Header().Set(MessagesTrimmedHeader, strconv.Itoa(trimmed))
+	}
+
+	started := time.Now()
+	stream, err := app.repositories.LlamaStackClient.StreamChatCompletion(r.Context(), client, llamastack.ChatCompletionRequest{
+		ModelID:        chatRequest.ModelID,
+		Messages:       messages,
+		SamplingParams: convertSamplingParams(samplingParams),
+		ResponseFormat: convertResponseFormat(chatRequest.ResponseFormat),
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	stream = app.meterChatCompletion(r, chatRequest.ModelID, started, stream)
+
+	var answer *answerRecordingStream
+	if chatRequest.ResponseFormat != nil {
+		answer = &answerRecordingStream{ChatCompletionStream: stream}
+		stream = answer
+	}
+
+	var sse *sseWriter
+	if len(chatRequest.OutputShields) > 0 {
+		sse = app.writeShieldedChatCompletion(w, r, client, chatRequest, stream)
+		if sse == nil {
+			return
+		}
+	} else {
+		sse = newSSEWriter(w)
+		if !relayEventStream(app, r, sse, stream) {
+			return
+		}
+	}
+
+	if answer != nil {
+		if err := sse.WriteEvent(StructuredOutputEventName, checkStructuredOutput(chatRequest.ResponseFormat, answer.answer.String())); err != nil {
+			app.LogError(r, fmt.Errorf("failed to write structured output: %w", err))
+		}
+	}
+
+	if citations != nil {
+		if err := sse.WriteEvent(CitationsEventName, CitationsEvent{Citations: citations}); err != nil {
+			app.LogError(r, fmt.Errorf("failed to write citations: %w", err))
+		}
+	}
+}
+
+// writeShieldedChatCompletion buffers the whole answer and runs the output shields on it before
+// anything is sent, so a blocked answer can still be reported through the error envelope. The
+// SSE writer is returned once the answer is written, nil if a response other than the stream
+// was sent.
+func (app *App) writeShieldedChatCompletion(w http.ResponseWriter, r *http.Request, client integrations.HTTPClientInterface, chatRequest ChatCompletionRequest, stream repositories.ChatCompletionStream) *sseWriter {
+	chunks, err := collectEventStream(stream)
+	if err != nil {
+		switch {
+		case repositories.IsGenerationCancelled(r.Context()):
+			app.writeGenerationCancelledEvent(r, newSSEWriter(w))
+		case r.Context().Err() == nil:
+			app.serverErrorResponse(w, r, err)
+		}
+		return nil
+	}
+
+	answer := llamastack.Message{
+		Role:       llamastack.AssistantRole,
+		Content:    chatCompletionText(chunks),
+		StopReason: "end_of_turn",
+	}
+	shieldMessages := append(append([]llamastack.Message{}, chatRequest.Messages...), answer)
+
+	violation, err := app.runShields(r, client, chatRequest.OutputShields, shieldMessages, OutputShieldStage)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return nil
+	}
+	if violation != nil {
+		app.safetyViolationResponse(w, r, *violation)
+		return nil
+	}
+
+	sse := newSSEWriter(w)
+	for _, chunk := range chunks {
+		if err := sse.WriteData(chunk); err != nil {
+			app.LogError(r, fmt.Errorf("failed to write chat completion chunk: %w", err))
+			return nil
+		}
+	}
+
+	return sse
+}
+
+// chatCompletionText concatenates the text deltas of a chat completion.
+func chatCompletionText(chunks []llamastack.ChatCompletionResponseStreamChunk) string {
+	var sb strings.Builder
+	for _, chunk := range chunks {
+		if chunk.Event.Delta.Type == "text" {
+			sb.WriteString(chunk.Event.Delta.Text)
+		}
+	}
+	return sb.String()
+}
+
+func (app *App) loadChatRequestResources(client integrations.HTTPClientInterface, request ChatCompletionRequest) (chatRequestResources, error) {
+	var resources chatRequestResources
+	var err error
+
+	resources.models, err = app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		return resources, err
+	}
+
+	if len(request.VectorDBIDs) > 0 {
+		resources.vectorDBs, err = app.repositories.LlamaStackClient.GetAllVectorDBs(client)
+		if err != nil {
+			return resources, err
+		}
+	}
+
+	if len(request.InputShields) > 0 || len(request.OutputShields) > 0 {
+		resources.shields, err = app.repositories.LlamaStackClient.GetAllShields(client)
+		if err != nil {
+			return resources, err
+		}
+	}
+
+	if request.PromptTemplate != nil && request.PromptTemplate.ID != "" {
+		version, err := app.repositories.PromptTemplates.GetPromptTemplateVersion(request.PromptTemplate.ID, request.PromptTemplate.Version)
+		switch {
+		case err == nil:
+			resources.promptTemplateVersion = &version
+		case !errors.Is(err, repositories.ErrPromptTemplateNotFound) && !errors.Is(err, repositories.ErrPromptTemplateVersionNotFound):
+			return resources, err
+		}
+	}
+
+	return resources, nil
+}
+
+// validateChatCompletionRequest returns the field errors of request, keyed by JSON path.
+func (app *App) validateChatCompletionRequest(request ChatCompletionRequest, resources chatRequestResources) map[string]string {
+	validationErrors := map[string]string{}
+
+	validateChatModelID(validationErrors, request.ModelID, resources.models)
+
+	validateChatMessages(validationErrors, request.Messages)
+
+	if len(request.VectorDBIDs) > 0 {
+		validateVectorDBIDs(validationErrors, request.VectorDBIDs, resources.vectorDBs)
+
+		if len(request.Messages) > 0 && request.Messages[len(request.Messages)-1].Role != llamastack.UserRole {
+			validationErrors["messages"] = "last message must be a user message when vector_db_ids are set"
+		}
+	}
+
+	validateShieldIDs(validationErrors, "input_shields", request.InputShields, resources.shields)
+	validateShieldIDs(validationErrors, "output_shields", request.OutputShields, resources.shields)
+
+	if request.SamplingParams != nil {
+		validateSamplingParams(validationErrors, *request.SamplingParams, app.config.ModelProfiles.ProfileFor(request.ModelID).Ranges)
+	}
+
+	if request.PromptTemplate != nil {
+		validatePromptTemplateSelection(validationErrors, request, resources.promptTemplateVersion)
+	}
+
+	if request.ResponseFormat != nil {
+		validateResponseFormat(validationErrors, request.ResponseFormat)
+	}
+
+	if request.ContextStrategy != "" && !slices.Contains(contextStrategies, request.ContextStrategy) {
+		validationErrors["context_strategy"] = "must be one of " + strings.Join(contextStrategies, ", ")
+	}
+
+	return validationErrors
+}
+
+func validateChatMessages(validationErrors map[string]string, messages []llamastack.Message) {
+	if len(messages) == 0 {
+		validationErrors["messages"] = "must contain at least one message"
+	}
+
+	for i, message := range messages {
+		if !slices.Contains(chatMessageRoles, message.Role) {
+			validationErrors[fmt.Sprintf("messages[%d].role", i)] = "must be one of " + strings.Join(chatMessageRoles, ", ")
+		}
+		if strings.TrimSpace(message.Content) == "" {
+			validationErrors[fmt.Sprintf("messages[%d].content", i)] = "must not be empty"
+		}
+	}
+}
+
+// validateChatModelID adds a field error unless modelID names an existing LLM.
+func validateChatModelID(validationErrors map[string]string, modelID string, modelList *llamastack.ModelList) {
+	if modelID == "" {
+		validationErrors["model_id"] = "must be provided"
+		return
+	}
+
+	model := findModel(modelList, modelID)
+	switch {
+	case model == nil:
+		validationErrors["model_id"] = fmt.Sprintf("model %q does not exist", modelID)
+	case model.ModelType != llamastack.LLMModelType:
+		validationErrors["model_id"] = fmt.Sprintf("model %q is not an %s model", modelID, llamastack.LLMModelType)
+	}
+}
+
+func validateVectorDBIDs(validationErrors map[string]string, vectorDBIDs []string, vectorDBList *llamastack.VectorDBList) {
+	for i, vectorDBID := range vectorDBIDs {
+		if findVectorDB(vectorDBList, vectorDBID) == nil {
+			validationErrors[fmt.Sprintf("vector_db_ids[%d]", i)] = fmt.Sprintf("vector database %q does not exist", vectorDBID)
+		}
+	}
+}
+
+func findModel(modelList *llamastack.ModelList, modelID string) *llamastack.Model {
+	for i := range modelList.Data {
+		if modelList.Data[i].Identifier == modelID {
+			return &modelList.Data[i]
+		}
+	}
+	return nil
+}
+
+// metadataInt returns the integer stored under key in the metadata of model, zero when it is
+// missing or not a number.
+func metadataInt(model *llamastack.Model, key string) int64 {
+	switch value := model.Metadata[key].(type) {
+	case float64:
+		return int64(value)
+	case string:
+		// Some providers report numbers as strings.
+		number, _ := strconv.ParseInt(value, 10, 64)
+		return number
+	}
+	return 0
+}
+
+func findVectorDB(vectorDBList *llamastack.VectorDBList, vectorDBID string) *llamastack.VectorDB {
+	if vectorDBList == nil {
+		return nil
+	}
+	for i := range vectorDBList.Data {
+		if vectorDBList.Data[i].Identifier == vectorDBID {
+			return &vectorDBList.Data[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/mocks"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRequest(t *testing.T, method string, path string, body any) *http.Request {
+	js, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(js))
+	assert.NoError(t, err)
+
+	// The mock client never touches the REST client, it only has to be present.
+	client, err := integrations.NewHTTPClient(slog.Default(), "")
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(req.Context(), constants.LlamaStackHttpClientKey, client)
+	return req.WithContext(ctx)
+}
+
+func newTestApp() App {
+	mockLSClient, _ := mocks.NewLlamastackClientMock()
+
+	return App{
+		config:       config.EnvConfig{Port: 4000},
+		logger:       slog.Default(),
+		repositories: repositories.NewRepositories(mockLSClient),
+	}
+}
+
+type testSSEEvent struct {
+	name string
+	data string
+}
+
+func parseTestSSEEvents(body string) []testSSEEvent {
+	var events []testSSEEvent
+	for _, raw := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var event testSSEEvent
+		for _, line := range strings.Split(raw, "\n") {
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event.name = name
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				event.data = data
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestChatCompletionHandlerStreamsChunks(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+	})
+
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+
+	body, err := io.ReadAll(rr.Result().Body)
+	assert.NoError(t, err)
+
+	var chunks []llamastack.ChatCompletionResponseStreamChunk
+	var text strings.Builder
+	for _, event := range parseTestSSEEvents(string(body)) {
+		var chunk llamastack.ChatCompletionResponseStreamChunk
+		assert.NoError(t, json.Unmarshal([]byte(event.data), &chunk))
+		chunks = append(chunks, chunk)
+		text.WriteString(chunk.Event.Delta.Text)
+	}
+
+	assert.Equal(t, llamastack.StartEventType, chunks[0].Event.EventType)
+	assert.Equal(t, llamastack.CompleteEventType, chunks[len(chunks)-1].Event.EventType)
+	assert.Equal(t, "This is a mock response from default-model-id-1 to: hello there", text.String())
+}
+
+func TestChatCompletionHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-2",
+		Messages: []llamastack.Message{
+			{Role: "robot", Content: ""},
+		},
+	})
+
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "model_id")
+	assert.Contains(t, fieldErrors, "messages[0].role")
+	assert.Contains(t, fieldErrors, "messages[0].content")
+}
+
+func TestChatCompletionHandlerWithRetrieval(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.UploadHandler(rr, newTestRequest(t, http.MethodPost, UploadPath, UploadRequest{
+		Documents: []llamastack.Document{
+			{DocumentID: "doc-1", Content: "The playground runs on OpenShift", Metadata: map[string]any{"source": "docs"}},
+		},
+		VectorDBID:     "rag-chat-db",
+		EmbeddingModel: "default-model-id-2",
+	}), nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.ChatCompletionHandler(rr, newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:     "default-model-id-1",
+		Messages:    []llamastack.Message{{Role: llamastack.UserRole, Content: "where does the playground run"}},
+		VectorDBIDs: []string{"rag-chat-db"},
+	}), nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	events := parseTestSSEEvents(rr.Body.String())
+	last := events[len(events)-1]
+	assert.Equal(t, CitationsEventName, last.name)
+
+	var citations CitationsEvent
+	assert.NoError(t, json.Unmarshal([]byte(last.data), &citations))
+	assert.Len(t, citations.Citations, 1)
+	assert.Equal(t, "doc-1", citations.Citations[0].DocumentID)
+
+	// The mock echoes the prompt it received, which has to contain the retrieved context.
+	assert.Contains(t, rr.Body.String(), "OpenShift")
+}
+
+func TestParseRAGPromptTemplate(t *testing.T) {
+	_, err := parseRAGPromptTemplate("")
+	assert.NoError(t, err)
+
+	_, err = parseRAGPromptTemplate("{{.Context}} {{.Unknown}}")
+	assert.Error(t, err)
+}
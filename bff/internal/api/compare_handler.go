@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+const (
+	CompareChunkEventName   = "chunk"
+	CompareErrorEventName   = "model_error"
+	CompareSummaryEventName = "summary"
+
+	CompareCompletedStatus = "completed"
+	CompareFailedStatus    = "failed"
+
+	maxCompareModels = 4
+)
+
+// CompareRequest represents the request body for comparing the answers of several models to
+// the same conversation.
+type CompareRequest struct {
+	ModelIDs       []string               `json:"model_ids"`
+	Messages       []llamastack.Message   `json:"messages"`
+	SamplingParams *models.SamplingParams `json:"sampling_params,omitempty"`
+}
+
+// CompareChunkEvent carries a chat completion chunk of one of the compared models.
+type CompareChunkEvent struct {
+	ModelID string                                       `json:"model_id"`
+	Chunk   llamastack.ChatCompletionResponseStreamChunk `json:"chunk"`
+}
+
+// CompareErrorEvent reports that one of the compared models failed, the others carry on.
+type CompareErrorEvent struct {
+	ModelID string `json:"model_id"`
+	Error   string `json:"error"`
+}
+
+// CompareSummaryEvent is sent once every model finished.
+type CompareSummaryEvent struct {
+	Results []CompareModelResult `json:"results"`
+}
+
+type CompareModelResult struct {
+	ModelID string `json:"model_id"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	// Milliseconds from the start of the request, time to first token is omitted when the
+	// model never produced one.
+	LatencyMs          int64  `json:"latency_ms"`
+	TimeToFirstTokenMs *int64 `json:"time_to_first_token_ms,omitempty"`
+	PromptTokens       int64  `json:"prompt_tokens"`
+	CompletionTokens   int64  `json:"completion_tokens"`
+	TotalTokens        int64  `json:"total_tokens"`
+}
+
+// compareEvent is handed from the per model goroutines to the goroutine writing the response.
+type compareEvent struct {
+	index int
+	chunk *llamastack.ChatCompletionResponseStreamChunk
+	err   error
+	done  bool
+}
+
+func (app *App) CompareHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var compareRequest CompareRequest
+	if err := app.ReadJSON(w, r, &compareRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := app.validateCompareRequest(compareRequest, modelList); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	r, done := app.startGeneration(w, r)
+	defer done()
+
+	// Cancelled when the client goes away, the generation is cancelled or the response cannot
+	// be written anymore, which stops every upstream generation.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	started := time.Now()
+	events := make(chan compareEvent)
+	var wg sync.WaitGroup
+
+	for i, modelID := range compareRequest.ModelIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.streamComparedModel(ctx, r, client, compareRequest, i, modelID, started, events)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	results := make([]CompareModelResult, len(compareRequest.ModelIDs))
+	for i, modelID := range compareRequest.ModelIDs {
+		results[i] = CompareModelResult{ModelID: modelID, Status: CompareCompletedStatus}
+	}
+
+	sse := newSSEWriter(w)
+	writeFailed := false
+
+	for event := range events {
+		if writeFailed {
+			// Keep draining so the model goroutines can observe the cancellation and exit.
+			continue
+		}
+
+		result := &results[event.index]
+		var writeErr error
+
+		switch {
+		case event.err != nil:
+			result.Status = CompareFailedStatus
+			result.Error = "the model could not complete the response"
+			result.LatencyMs = time.Since(started).Milliseconds()
+			app.LogError(r, fmt.Errorf("compared model %s failed: %w", result.ModelID, event.err))
+			writeErr = sse.WriteEvent(CompareErrorEventName, CompareErrorEvent{ModelID: result.ModelID, Error: result.Error})
+		case event.done:
+			result.LatencyMs = time.Since(started).Milliseconds()
+		default:
+			if result.TimeToFirstTokenMs == nil && event.chunk.Event.Delta.Text != "" {
+				ttft := time.Since(started).Milliseconds()
+				result.TimeToFirstTokenMs = &ttft
+			}
+			if len(event.chunk.Metrics) > 0 {
+				result.PromptTokens, result.CompletionTokens = tokensFromMetrics(event.chunk.Metrics)
+				result.TotalTokens = result.PromptTokens + result.CompletionTokens
+			}
+			writeErr = sse.WriteEvent(CompareChunkEventName, CompareChunkEvent{ModelID: result.ModelID, Chunk: *event.chunk})
+		}
+
+		if writeErr != nil {
+			if r.Context().Err() == nil {
+				app.LogError(r, fmt.Errorf("failed to write compare event: %w", writeErr))
+			}
+			writeFailed = true
+			cancel()
+		}
+	}
+
+	if !writeFailed && repositories.IsGenerationCancelled(r.Context()) {
+		app.writeGenerationCancelledEvent(r, sse)
+		return
+	}
+
+	if writeFailed || r.Context().Err() != nil {
+		helper.GetContextLoggerFromReq(r).Debug("Client disconnected from model comparison")
+		return
+	}
+
+	if err := sse.WriteEvent(CompareSummaryEventName, CompareSummaryEvent{Results: results}); err != nil {
+		app.LogError(r, fmt.Errorf("failed to write compare summary: %w", err))
+	}
+}
+
+// streamComparedModel streams the completion of a single model into events until it finishes,
+// fails or ctx is cancelled.
+func (app *App) streamComparedModel(ctx context.Context, r *http.Request, client integrations.HTTPClientInterface, compareRequest CompareRequest, index int, modelID string, started time.Time, events chan<- compareEvent) {
+	send := func(event compareEvent) bool {
+		event.index = index
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	samplingParams := app.config.ModelProfiles.ProfileFor(modelID).Defaults
+	if compareRequest.SamplingParams != nil {
+		samplingParams = samplingParams.Merge(*compareRequest.SamplingParams)
+	}
+
+	stream, err := app.repositories.LlamaStackClient.StreamChatCompletion(ctx, client, llamastack.ChatCompletionRequest{
+		ModelID:        modelID,
+		Messages:       compareRequest.Messages,
+		SamplingParams: convertSamplingParams(samplingParams),
+	})
+	if err != nil {
+		send(compareEvent{err: err})
+		return
+	}
+
+	stream = app.meterChatCompletion(r, modelID, started, stream)
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			send(compareEvent{done: true})
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				send(compareEvent{err: err})
+			}
+			return
+		}
+		if !send(compareEvent{chunk: chunk}) {
+			return
+		}
+	}
+}
+
+func (app *App) validateCompareRequest(request CompareRequest, modelList *llamastack.ModelList) map[string]string {
+	validationErrors := map[string]string{}
+
+	switch {
+	case len(request.ModelIDs) < 2:
+		validationErrors["model_ids"] = "must contain at least two models"
+	case len(request.ModelIDs) > maxCompareModels:
+		validationErrors["model_ids"] = fmt.Sprintf("must contain at most %d models", maxCompareModels)
+	}
+
+	seen := map[string]bool{}
+	for i, modelID := range request.ModelIDs {
+		field := fmt.Sprintf("model_ids[%d]", i)
+
+		modelErrors := map[string]string{}
+		validateChatModelID(modelErrors, modelID, modelList)
+		if message, ok := modelErrors["model_id"]; ok {
+			validationErrors[field] = message
+		} else if seen[modelID] {
+			validationErrors[field] = fmt.Sprintf("model %q is listed more than once", modelID)
+		}
+		seen[modelID] = true
+
+		if request.SamplingParams != nil {
+			validateSamplingParams(validationErrors, *request.SamplingParams, app.config.ModelProfiles.ProfileFor(modelID).Ranges)
+		}
+	}
+
+	validateChatMessages(validationErrors, request.Messages)
+
+	return validationErrors
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ComparePath, CompareRequest{
+		ModelIDs: []string{"default-model-id-1", "default-model-id-3"},
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "hello there"}},
+	})
+	app.CompareHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body, err := io.ReadAll(rr.Result().Body)
+	assert.NoError(t, err)
+
+	answers := map[string]string{}
+	var summary CompareSummaryEvent
+	events := parseTestSSEEvents(string(body))
+	for _, event := range events {
+		switch event.name {
+		case CompareChunkEventName:
+			var chunk CompareChunkEvent
+			assert.NoError(t, json.Unmarshal([]byte(event.data), &chunk))
+			answers[chunk.ModelID] += chunk.Chunk.Event.Delta.Text
+		case CompareSummaryEventName:
+			assert.NoError(t, json.Unmarshal([]byte(event.data), &summary))
+		}
+	}
+
+	assert.Equal(t, CompareSummaryEventName, events[len(events)-1].name)
+	assert.Equal(t, "This is a mock response from default-model-id-1 to: hello there", answers["default-model-id-1"])
+	assert.Equal(t, "This is a mock response from default-model-id-3 to: hello there", answers["default-model-id-3"])
+
+	assert.Len(t, summary.Results, 2)
+	for _, result := range summary.Results {
+		assert.Equal(t, CompareCompletedStatus, result.Status)
+		assert.NotNil(t, result.TimeToFirstTokenMs)
+		assert.Greater(t, result.CompletionTokens, int64(0))
+	}
+}
+
+func TestCompareHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ComparePath, CompareRequest{
+		ModelIDs: []string{"default-model-id-1", "default-model-id-2"},
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "hello there"}},
+	})
+	app.CompareHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "model_ids[1]")
+}
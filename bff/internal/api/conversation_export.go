@@ -0,0 +1,317 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+const (
+	MarkdownExportFormat = "markdown"
+	JSONExportFormat     = "json"
+	JSONLExportFormat    = "jsonl"
+)
+
+// ExportTranscriptRequest represents the request body for exporting a transcript that is not
+// stored by the BFF.
+type ExportTranscriptRequest struct {
+	Title    string                       `json:"title,omitempty"`
+	ModelID  string                       `json:"model_id,omitempty"`
+	Messages []ConversationMessageRequest `json:"messages"`
+}
+
+// TranscriptExport is the document written by the json format.
+type TranscriptExport struct {
+	Title      string                    `json:"title"`
+	ModelID    string                    `json:"model_id,omitempty"`
+	ExportedAt time.Time                 `json:"exported_at"`
+	Messages   []TranscriptExportMessage `json:"messages"`
+}
+
+type TranscriptExportMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// exportFormat renders a transcript into a downloadable document.
+type exportFormat struct {
+	name        string
+	contentType string
+	// Media types of the Accept header selecting the format, the first is also the content type.
+	mediaTypes []string
+	extension  string
+	render     func(transcript models.Conversation, exportedAt time.Time) ([]byte, error)
+}
+
+// exportFormats are listed in order of preference for clients accepting anything.
+var exportFormats = []exportFormat{
+	{
+		name:        JSONExportFormat,
+		contentType: "application/json",
+		mediaTypes:  []string{"application/json"},
+		extension:   "json",
+		render:      renderJSONExport,
+	},
+	{
+		name:        MarkdownExportFormat,
+		contentType: "text/markdown; charset=utf-8",
+		mediaTypes:  []string{"text/markdown", "text/x-markdown"},
+		extension:   "md",
+		render:      renderMarkdownExport,
+	},
+	{
+		name:        JSONLExportFormat,
+		contentType: "application/jsonl",
+		mediaTypes:  []string{"application/jsonl", "application/x-ndjson", "application/jsonlines"},
+		extension:   "jsonl",
+		render:      renderJSONLExport,
+	},
+}
+
+func (app *App) ExportConversationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	conversation, err := app.repositories.Conversations.GetConversation(requestUserID(r), ps.ByName("conversation_id"))
+	if err != nil {
+		app.conversationErrorResponse(w, r, err)
+		return
+	}
+
+	app.writeTranscriptExport(w, r, conversation)
+}
+
+func (app *App) ExportTranscriptHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var exportRequest ExportTranscriptRequest
+	if err := app.ReadJSON(w, r, &exportRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	validationErrors := validateConversationMessages(exportRequest.Messages)
+	if len(exportRequest.Messages) == 0 {
+		validationErrors["messages"] = "must contain at least one message"
+	}
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	messages := convertConversationMessages(exportRequest.Messages)
+
+	title := strings.TrimSpace(exportRequest.Title)
+	if title == "" {
+		title = deriveConversationTitle(messages)
+	}
+
+	app.writeTranscriptExport(w, r, models.Conversation{
+		Title:    title,
+		ModelID:  exportRequest.ModelID,
+		Messages: messages,
+	})
+}
+
+// writeTranscriptExport renders transcript in the format picked by the format query parameter,
+// or by the Accept header when the parameter is absent.
+func (app *App) writeTranscriptExport(w http.ResponseWriter, r *http.Request, transcript models.Conversation) {
+	format, ok := exportFormatByName(r.URL.Query().Get("format"))
+	if !ok {
+		app.failedValidationResponse(w, r, map[string]string{
+			"format": fmt.Sprintf("must be one of %s, %s, %s", JSONExportFormat, MarkdownExportFormat, JSONLExportFormat),
+		})
+		return
+	}
+
+	if format == nil {
+		format = negotiateExportFormat(r.Header.Get("Accept"))
+		if format == nil {
+			var supported []string
+			for _, f := range exportFormats {
+				supported = append(supported, f.mediaTypes[0])
+			}
+			app.notAcceptableResponse(w, r, supported)
+			return
+		}
+	}
+
+	body, err := format.render(transcript, time.Now().UTC())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	filename := exportFilename(transcript.Title) + "." + format.extension
+
+	err = app.WriteBody(w, http.StatusOK, format.contentType, body, AttachmentHeaders(filename))
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// exportFormatByName returns nil and true when name is empty, so the format is negotiated.
+func exportFormatByName(name string) (*exportFormat, bool) {
+	if name == "" {
+		return nil, true
+	}
+	for i := range exportFormats {
+		if exportFormats[i].name == strings.ToLower(name) {
+			return &exportFormats[i], true
+		}
+	}
+	return nil, false
+}
+
+// negotiateExportFormat picks the format of the highest quality media type of accept, nil when
+// none is supported. An empty header accepts anything.
+func negotiateExportFormat(accept string) *exportFormat {
+	if strings.TrimSpace(accept) == "" {
+		return &exportFormats[0]
+	}
+
+	type acceptedType struct {
+		mediaType string
+		quality   float64
+	}
+
+	var accepted []acceptedType
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, acceptedType{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+
+	for _, a := range accepted {
+		for i, format := range exportFormats {
+			for _, mediaType := range format.mediaTypes {
+				if a.mediaType == mediaType || a.mediaType == "*/*" ||
+					(strings.HasSuffix(a.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*"))) {
+					return &exportFormats[i]
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func renderJSONExport(transcript models.Conversation, exportedAt time.Time) ([]byte, error) {
+	export := TranscriptExport{
+		Title:      transcript.Title,
+		ModelID:    transcript.ModelID,
+		ExportedAt: exportedAt,
+		Messages:   []TranscriptExportMessage{},
+	}
+
+	for _, message := range transcript.Messages {
+		exportMessage := TranscriptExportMessage{
+			Role:    message.Role,
+			Content: message.Content,
+		}
+		// Transcripts supplied in the request have no timestamps.
+		if !message.CreatedAt.IsZero() {
+			createdAt := message.CreatedAt
+			exportMessage.CreatedAt = &createdAt
+		}
+		export.Messages = append(export.Messages, exportMessage)
+	}
+
+	js, err := json.MarshalIndent(export, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(js, '\n'), nil
+}
+
+func renderMarkdownExport(transcript models.Conversation, exportedAt time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n\n", transcript.Title)
+	if transcript.ModelID != "" {
+		fmt.Fprintf(&buf, "- Model: `%s`\n", transcript.ModelID)
+	}
+	fmt.Fprintf(&buf, "- Exported: %s\n", exportedAt.Format(time.RFC3339))
+
+	for _, message := range transcript.Messages {
+		fmt.Fprintf(&buf, "\n## %s\n\n%s\n", markdownRoleHeading(message.Role), strings.TrimSpace(message.Content))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderJSONLExport writes the transcript as a single line in the chat format used by
+// fine-tuning datasets, so exports can be concatenated into a dataset.
+func renderJSONLExport(transcript models.Conversation, _ time.Time) ([]byte, error) {
+	type chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+
+	line := struct {
+		Messages []chatMessage `json:"messages"`
+	}{Messages: []chatMessage{}}
+
+	for _, message := range transcript.Messages {
+		line.Messages = append(line.Messages, chatMessage{Role: message.Role, Content: message.Content})
+	}
+
+	js, err := json.Marshal(line)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(js, '\n'), nil
+}
+
+func markdownRoleHeading(role string) string {
+	if role == "" {
+		return "Message"
+	}
+	runes := []rune(role)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// exportFilename turns a title into a file name without the extension.
+func exportFilename(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+	}
+
+	name := strings.TrimSuffix(sb.String(), "-")
+	if name == "" {
+		return "conversation"
+	}
+	return name
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTranscriptRequest() ExportTranscriptRequest {
+	return ExportTranscriptRequest{
+		Title:   "Release planning",
+		ModelID: "default-model-id-1",
+		Messages: []ConversationMessageRequest{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "user", Content: "When do we ship?"},
+			{Role: "assistant", Content: "Next Tuesday."},
+		},
+	}
+}
+
+func TestExportTranscriptHandlerFormats(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, TranscriptExportPath+"?format=markdown", newTestTranscriptRequest())
+	app.ExportTranscriptHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=release-planning.md`, rr.Header().Get("Content-Disposition"))
+	assert.Contains(t, rr.Body.String(), "# Release planning")
+	assert.Contains(t, rr.Body.String(), "## Assistant\n\nNext Tuesday.")
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodPost, TranscriptExportPath, newTestTranscriptRequest())
+	req.Header.Set("Accept", "text/html;q=0.9, application/jsonl")
+	app.ExportTranscriptHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/jsonl", rr.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	assert.Len(t, lines, 1)
+
+	var line struct {
+		Messages []map[string]string `json:"messages"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Len(t, line.Messages, 3)
+	assert.Equal(t, "assistant", line.Messages[2]["role"])
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodPost, TranscriptExportPath, newTestTranscriptRequest())
+	req.Header.Set("Accept", "text/html")
+	app.ExportTranscriptHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+}
+
+func TestExportConversationHandler(t *testing.T) {
+	app := newTestApp()
+
+	conversation, err := app.repositories.Conversations.CreateConversation(AnonymousUserID, models.Conversation{
+		Title:    "Stored",
+		Messages: []models.ConversationMessage{{Role: "user", Content: "hi"}},
+	})
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, ConversationExportPath, nil)
+	app.ExportConversationHandler(rr, req, httprouter.Params{{Key: "conversation_id", Value: conversation.ID}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var export TranscriptExport
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&export))
+	assert.Equal(t, "Stored", export.Title)
+	assert.NotNil(t, export.Messages[0].CreatedAt)
+}
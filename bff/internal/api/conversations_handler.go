@@ -0,0 +1,225 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+type ConversationEnvelope Envelope[models.Conversation, None]
+type ConversationListEnvelope Envelope[models.ConversationList, None]
+
+const (
+	defaultConversationTitle = "New conversation"
+	maxDerivedTitleLength    = 60
+)
+
+type ConversationMessageRequest struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+// CreateConversationRequest represents the request body for creating a conversation
+type CreateConversationRequest struct {
+	Title       string                       `json:"title"`
+	ModelID     string                       `json:"model_id,omitempty"`
+	VectorDBIDs []string                     `json:"vector_db_ids,omitempty"`
+	Messages    []ConversationMessageRequest `json:"messages,omitempty"`
+}
+
+// AppendMessagesRequest represents the request body for adding messages to a conversation
+type AppendMessagesRequest struct {
+	Messages []ConversationMessageRequest `json:"messages"`
+}
+
+func (app *App) GetAllConversationsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	conversationList, err := app.repositories.Conversations.ListConversations(requestUserID(r))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, ConversationListEnvelope{Data: conversationList}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) CreateConversationHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var createRequest CreateConversationRequest
+	if err := app.ReadJSON(w, r, &createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := validateConversationMessages(createRequest.Messages); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	messages := convertConversationMessages(createRequest.Messages)
+
+	title := strings.TrimSpace(createRequest.Title)
+	if title == "" {
+		title = deriveConversationTitle(messages)
+	}
+
+	conversation, err := app.repositories.Conversations.CreateConversation(requestUserID(r), models.Conversation{
+		Title:       title,
+		ModelID:     createRequest.ModelID,
+		VectorDBIDs: createRequest.VectorDBIDs,
+		Messages:    messages,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, ConversationEnvelope{Data: conversation}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) GetConversationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	conversation, err := app.repositories.Conversations.GetConversation(requestUserID(r), ps.ByName("conversation_id"))
+	if err != nil {
+		app.conversationErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, ConversationEnvelope{Data: conversation}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) UpdateConversationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var update models.ConversationUpdate
+	if err := app.ReadJSON(w, r, &update); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if update.Title != nil && strings.TrimSpace(*update.Title) == "" {
+		app.failedValidationResponse(w, r, map[string]string{"title": "must not be empty"})
+		return
+	}
+
+	conversation, err := app.repositories.Conversations.UpdateConversation(requestUserID(r), ps.ByName("conversation_id"), update)
+	if err != nil {
+		app.conversationErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, ConversationEnvelope{Data: conversation}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) DeleteConversationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	err := app.repositories.Conversations.DeleteConversation(requestUserID(r), ps.ByName("conversation_id"))
+	if err != nil {
+		app.conversationErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *App) AppendConversationMessagesHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var appendRequest AppendMessagesRequest
+	if err := app.ReadJSON(w, r, &appendRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if len(appendRequest.Messages) == 0 {
+		app.badRequestResponse(w, r, errors.New("messages are required"))
+		return
+	}
+
+	if validationErrors := validateConversationMessages(appendRequest.Messages); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	conversation, err := app.repositories.Conversations.AppendMessages(requestUserID(r), ps.ByName("conversation_id"), convertConversationMessages(appendRequest.Messages))
+	if err != nil {
+		app.conversationErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, ConversationEnvelope{Data: conversation}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) conversationErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, repositories.ErrConversationNotFound) {
+		app.notFoundResponse(w, r)
+		return
+	}
+	app.serverErrorResponse(w, r, err)
+}
+
+func validateConversationMessages(messages []ConversationMessageRequest) map[string]string {
+	validationErrors := map[string]string{}
+
+	for i, message := range messages {
+		if !slices.Contains(chatMessageRoles, message.Role) {
+			validationErrors[fmt.Sprintf("messages[%d].role", i)] = "must be one of " + strings.Join(chatMessageRoles, ", ")
+		}
+	}
+
+	return validationErrors
+}
+
+func convertConversationMessages(messages []ConversationMessageRequest) []models.ConversationMessage {
+	result := make([]models.ConversationMessage, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, models.ConversationMessage{
+			Role:       message.Role,
+			Content:    message.Content,
+			StopReason: message.StopReason,
+		})
+	}
+	return result
+}
+
+// deriveConversationTitle names a conversation after its first user message.
+func deriveConversationTitle(messages []models.ConversationMessage) string {
+	for _, message := range messages {
+		if message.Role != llamastack.UserRole {
+			continue
+		}
+
+		title := strings.Join(strings.Fields(message.Content), " ")
+		if title == "" {
+			continue
+		}
+
+		runes := []rune(title)
+		if len(runes) > maxDerivedTitleLength {
+			return string(runes[:maxDerivedTitleLength]) + "…"
+		}
+		return title
+	}
+
+	return defaultConversationTitle
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConversationRequest(t *testing.T, method string, path string, body any, userID string) *http.Request {
+	req := newTestRequest(t, method, path, body)
+	return req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, userID))
+}
+
+func createTestConversation(t *testing.T, app App, userID string, request CreateConversationRequest) ConversationEnvelope {
+	rr := httptest.NewRecorder()
+	app.CreateConversationHandler(rr, newTestConversationRequest(t, http.MethodPost, ConversationListPath, request, userID), nil)
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var envelope ConversationEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	return envelope
+}
+
+func TestConversationHandlers(t *testing.T) {
+	app := newTestApp()
+
+	created := createTestConversation(t, app, "alice", CreateConversationRequest{
+		ModelID: "default-model-id-1",
+		Messages: []ConversationMessageRequest{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "user", Content: "  How do   I deploy\nthe operator?"},
+		},
+	})
+	// The title is derived from the first user message.
+	assert.Equal(t, "How do I deploy the operator?", created.Data.Title)
+	assert.Len(t, created.Data.Messages, 2)
+	params := httprouter.Params{{Key: "conversation_id", Value: created.Data.ID}}
+
+	rr := httptest.NewRecorder()
+	app.AppendConversationMessagesHandler(rr, newTestConversationRequest(t, http.MethodPost, ConversationMessagesPath, AppendMessagesRequest{
+		Messages: []ConversationMessageRequest{{Role: "assistant", Content: "Run make deploy.", StopReason: "end_of_turn"}},
+	}, "alice"), params)
+
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	title := "Deployment"
+	rr = httptest.NewRecorder()
+	app.UpdateConversationHandler(rr, newTestConversationRequest(t, http.MethodPatch, ConversationPath, map[string]any{"title": title}, "alice"), params)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	app.GetConversationHandler(rr, newTestConversationRequest(t, http.MethodGet, ConversationPath, nil, "alice"), params)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope ConversationEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, title, envelope.Data.Title)
+	assert.Len(t, envelope.Data.Messages, 3)
+	assert.Equal(t, "end_of_turn", envelope.Data.Messages[2].StopReason)
+
+	rr = httptest.NewRecorder()
+	app.GetAllConversationsHandler(rr, newTestConversationRequest(t, http.MethodGet, ConversationListPath, nil, "alice"), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listEnvelope ConversationListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&listEnvelope))
+	assert.Len(t, listEnvelope.Data.Items, 1)
+	assert.Equal(t, 3, listEnvelope.Data.Items[0].MessageCount)
+
+	rr = httptest.NewRecorder()
+	app.DeleteConversationHandler(rr, newTestConversationRequest(t, http.MethodDelete, ConversationPath, nil, "alice"), params)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.GetConversationHandler(rr, newTestConversationRequest(t, http.MethodGet, ConversationPath, nil, "alice"), params)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestConversationHandlersOtherUser(t *testing.T) {
+	app := newTestApp()
+
+	created := createTestConversation(t, app, "alice", CreateConversationRequest{Title: "Private"})
+	params := httprouter.Params{{Key: "conversation_id", Value: created.Data.ID}}
+
+	// Conversations of other users are not found rather than forbidden.
+	rr := httptest.NewRecorder()
+	app.GetConversationHandler(rr, newTestConversationRequest(t, http.MethodGet, ConversationPath, nil, "bob"), params)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.AppendConversationMessagesHandler(rr, newTestConversationRequest(t, http.MethodPost, ConversationMessagesPath, AppendMessagesRequest{
+		Messages: []ConversationMessageRequest{{Role: "user", Content: "hi"}},
+	}, "bob"), params)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.DeleteConversationHandler(rr, newTestConversationRequest(t, http.MethodDelete, ConversationPath, nil, "bob"), params)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.GetAllConversationsHandler(rr, newTestConversationRequest(t, http.MethodGet, ConversationListPath, nil, "bob"), nil)
+
+	var listEnvelope ConversationListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&listEnvelope))
+	assert.Empty(t, listEnvelope.Data.Items)
+}
+
+func TestConversationHandlersValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.CreateConversationHandler(rr, newTestConversationRequest(t, http.MethodPost, ConversationListPath, CreateConversationRequest{
+		Messages: []ConversationMessageRequest{{Role: "user", Content: "hi"}, {Role: "robot", Content: "beep"}},
+	}, "alice"), nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "messages[1].role")
+
+	created := createTestConversation(t, app, "alice", CreateConversationRequest{})
+	assert.Equal(t, defaultConversationTitle, created.Data.Title)
+	params := httprouter.Params{{Key: "conversation_id", Value: created.Data.ID}}
+
+	rr = httptest.NewRecorder()
+	app.AppendConversationMessagesHandler(rr, newTestConversationRequest(t, http.MethodPost, ConversationMessagesPath, AppendMessagesRequest{}, "alice"), params)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.UpdateConversationHandler(rr, newTestConversationRequest(t, http.MethodPatch, ConversationPath, map[string]any{"title": " "}, "alice"), params)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/chunking"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type EmbeddingsEnvelope Envelope[models.EmbeddingsResult, None]
+
+// maxEmbeddingTexts bounds a single request, the endpoint is meant for inspecting vectors rather
+// than for bulk ingestion.
+const maxEmbeddingTexts = 128
+
+// EmbeddingsRequest represents the request body for embedding texts
+type EmbeddingsRequest struct {
+	ModelID string   `json:"model_id"`
+	Texts   []string `json:"texts"`
+}
+
+func (app *App) EmbeddingsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var embeddingsRequest EmbeddingsRequest
+	if err := app.ReadJSON(w, r, &embeddingsRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := validateEmbeddingsRequest(embeddingsRequest, modelList); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	started := time.Now()
+	response, err := app.repositories.LlamaStackClient.Embeddings(client, llamastack.EmbeddingsRequest{
+		ModelID:  embeddingsRequest.ModelID,
+		Contents: embeddingsRequest.Texts,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// Llama Stack reports no usage for embeddings, the input is all there is to count.
+	var promptTokens int64
+	for _, text := range embeddingsRequest.Texts {
+		promptTokens += int64(chunking.EstimateTokens(text))
+	}
+	app.recordUsage(r, models.UsageRecord{
+		UserID:       requestUserID(r),
+		ModelID:      embeddingsRequest.ModelID,
+		Source:       models.EmbeddingsUsageSource,
+		PromptTokens: promptTokens,
+		LatencyMs:    time.Since(started).Milliseconds(),
+		Estimated:    true,
+	})
+
+	if len(response.Embeddings) != len(embeddingsRequest.Texts) {
+		app.serverErrorResponse(w, r, fmt.Errorf("expected %d embeddings, got %d", len(embeddingsRequest.Texts), len(response.Embeddings)))
+		return
+	}
+
+	result := EmbeddingsEnvelope{
+		Data: convertEmbeddings(embeddingsRequest.ModelID, response),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func validateEmbeddingsRequest(request EmbeddingsRequest, modelList *llamastack.ModelList) map[string]string {
+	validationErrors := map[string]string{}
+
+	if request.ModelID == "" {
+		validationErrors["model_id"] = "must be provided"
+	} else {
+		model := findModel(modelList, request.ModelID)
+		switch {
+		case model == nil:
+			validationErrors["model_id"] = fmt.Sprintf("model %q does not exist", request.ModelID)
+		case model.ModelType != llamastack.EmbeddingModelType:
+			validationErrors["model_id"] = fmt.Sprintf("model %q is not an %s model", request.ModelID, llamastack.EmbeddingModelType)
+		}
+	}
+
+	switch {
+	case len(request.Texts) == 0:
+		validationErrors["texts"] = "must contain at least one text"
+	case len(request.Texts) > maxEmbeddingTexts:
+		validationErrors["texts"] = fmt.Sprintf("must contain at most %d texts", maxEmbeddingTexts)
+	}
+
+	for i, text := range request.Texts {
+		if strings.TrimSpace(text) == "" {
+			validationErrors[fmt.Sprintf("texts[%d]", i)] = "must not be empty"
+		}
+	}
+
+	return validationErrors
+}
+
+func convertEmbeddings(modelID string, response *llamastack.EmbeddingsResponse) models.EmbeddingsResult {
+	result := models.EmbeddingsResult{
+		ModelID: modelID,
+		Items:   []models.Embedding{},
+	}
+
+	for i, vector := range response.Embeddings {
+		result.Items = append(result.Items, models.Embedding{Index: i, Vector: vector})
+	}
+	if len(response.Embeddings) > 0 {
+		result.Dimensions = len(response.Embeddings[0])
+	}
+
+	return result
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddingsHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, EmbeddingsPath, EmbeddingsRequest{
+		ModelID: "default-model-id-2",
+		Texts:   []string{"vector databases", "vector databases", "something else"},
+	})
+	app.EmbeddingsHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope EmbeddingsEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, mocks.MockEmbeddingDimension, envelope.Data.Dimensions)
+	assert.Len(t, envelope.Data.Items, 3)
+	assert.Equal(t, envelope.Data.Items[0].Vector, envelope.Data.Items[1].Vector)
+	assert.NotEqual(t, envelope.Data.Items[0].Vector, envelope.Data.Items[2].Vector)
+
+	var norm float64
+	for _, value := range envelope.Data.Items[0].Vector {
+		norm += value * value
+	}
+	assert.InDelta(t, 1.0, math.Sqrt(norm), 1e-9)
+}
+
+func TestEmbeddingsHandlerRejectsLLM(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, EmbeddingsPath, EmbeddingsRequest{
+		ModelID: "default-model-id-1",
+		Texts:   []string{"hello"},
+	})
+	app.EmbeddingsHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
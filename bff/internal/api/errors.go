@@ -2,12 +2,17 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
 )
 
 type HTTPError struct {
@@ -24,6 +29,16 @@ type ErrorEnvelope struct {
 	Error *integrations.HTTPError `json:"error"`
 }
 
+// SafetyViolationErrorEnvelope extends the error envelope with the violation reported by a shield.
+type SafetyViolationErrorEnvelope struct {
+	Error SafetyViolationError `json:"error"`
+}
+
+type SafetyViolationError struct {
+	integrations.ErrorResponse
+	Violation models.SafetyViolation `json:"violation"`
+}
+
 func (app *App) LogError(r *http.Request, err error) {
 	var (
 		method = r.Method
@@ -111,8 +126,19 @@ func (app *App) methodNotAllowedResponse(w http.ResponseWriter, r *http.Request)
 	app.errorResponse(w, r, httpError)
 }
 
-// TODO remove nolint comment below when we use this method
-func (app *App) failedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) { //nolint:unused
+func (app *App) notAcceptableResponse(w http.ResponseWriter, r *http.Request, supported []string) {
+
+	httpError := &integrations.HTTPError{
+		StatusCode: http.StatusNotAcceptable,
+		ErrorResponse: integrations.ErrorResponse{
+			Code:    strconv.Itoa(http.StatusNotAcceptable),
+			Message: fmt.Sprintf("none of the accepted media types is supported, supported types are %s", strings.Join(supported, ", ")),
+		},
+	}
+	app.errorResponse(w, r, httpError)
+}
+
+func (app *App) failedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) {
 
 	message, err := json.Marshal(errors)
 	if err != nil {
@@ -127,3 +153,59 @@ func (app *App) failedValidationResponse(w http.ResponseWriter, r *http.Request,
 	}
 	app.errorResponse(w, r, httpError)
 }
+
+// rateLimitedResponse asks the client to retry after retryAfter, rounded up to whole seconds.
+func (app *App) rateLimitedResponse(w http.ResponseWriter, r *http.Request, message string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+	httpError := &integrations.HTTPError{
+		StatusCode: http.StatusTooManyRequests,
+		ErrorResponse: integrations.ErrorResponse{
+			Code:    strconv.Itoa(http.StatusTooManyRequests),
+			Message: message,
+		},
+	}
+	app.errorResponse(w, r, httpError)
+}
+
+// upstreamErrorResponse maps a Llama Stack 404 to notFoundResponse and passes on the errors
+// Llama Stack reports for invalid requests, like duplicate ids or unknown providers. Any other
+// failure is reported as a server error.
+func (app *App) upstreamErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	var httpError *integrations.HTTPError
+	if errors.As(err, &httpError) {
+		switch httpError.StatusCode {
+		case http.StatusNotFound:
+			app.notFoundResponse(w, r)
+			return
+		case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+			app.errorResponse(w, r, httpError)
+			return
+		}
+	}
+	app.serverErrorResponse(w, r, err)
+}
+
+func (app *App) safetyViolationResponse(w http.ResponseWriter, r *http.Request, violation models.SafetyViolation) {
+	message := violation.Message
+	if message == "" {
+		message = fmt.Sprintf("the %s was blocked by shield %s", violation.Stage, violation.ShieldID)
+	}
+
+	env := SafetyViolationErrorEnvelope{
+		Error: SafetyViolationError{
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusBadRequest),
+				Message: message,
+			},
+			Violation: violation,
+		},
+	}
+
+	err := app.WriteJSON(w, http.StatusBadRequest, env, nil)
+
+	if err != nil {
+		app.LogError(r, err)
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
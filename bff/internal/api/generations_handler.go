@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+// GenerationIDHeader is sent with every streamed generation, its value is the trace id of the
+// request and cancels the generation through GenerationPath.
+const GenerationIDHeader = "X-Generation-Id"
+
+// GenerationCancelledEventName names the last event of a generation cancelled by its owner.
+const GenerationCancelledEventName = "cancelled"
+
+type GenerationCancelledEvent struct {
+	GenerationID string `json:"generation_id"`
+}
+
+// startGeneration registers the generation streamed by r and sends its id to the client, it
+// returns r with a context that is cancelled when the owner cancels the generation. done must be
+// called once the generation is over, before that the response headers must not be written.
+func (app *App) startGeneration(w http.ResponseWriter, r *http.Request) (*http.Request, func()) {
+	ctx := r.Context()
+
+	generationID, _ := ctx.Value(constants.TraceIdKey).(string)
+	if generationID == "" {
+		// Only happens when EnableTelemetry did not run, e.g. in tests.
+		generationID = uuid.NewString()
+		ctx = context.WithValue(ctx, constants.TraceIdKey, generationID)
+	}
+
+	ctx, done := app.repositories.Generations.Start(ctx, generationID, requestUserID(r))
+	w.Header().Set(GenerationIDHeader, generationID)
+
+	return r.WithContext(ctx), done
+}
+
+// writeGenerationCancelledEvent tells the client its generation stopped because it was cancelled
+// rather than because it failed.
+func (app *App) writeGenerationCancelledEvent(r *http.Request, sse *sseWriter) {
+	generationID, _ := r.Context().Value(constants.TraceIdKey).(string)
+
+	if err := sse.WriteEvent(GenerationCancelledEventName, GenerationCancelledEvent{GenerationID: generationID}); err != nil {
+		app.LogError(r, fmt.Errorf("failed to write cancelled event: %w", err))
+	}
+}
+
+func (app *App) CancelGenerationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	err := app.repositories.Generations.Cancel(requestUserID(r), ps.ByName("generation_id"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrGenerationNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+// cancellingRecorder sends cancel with the generation id of the response as soon as the first
+// event is written, while the generation is still streaming.
+type cancellingRecorder struct {
+	*httptest.ResponseRecorder
+	cancel func(generationID string)
+	once   bool
+}
+
+func (c *cancellingRecorder) Write(b []byte) (int, error) {
+	n, err := c.ResponseRecorder.Write(b)
+	if !c.once {
+		c.once = true
+		c.cancel(c.Header().Get(GenerationIDHeader))
+	}
+	return n, err
+}
+
+func newCancelGenerationRequest(t *testing.T, generationID string, userID string) (*http.Request, httprouter.Params) {
+	req, err := http.NewRequest(http.MethodDelete, strings.Replace(GenerationPath, ":generation_id", generationID, 1), nil)
+	assert.NoError(t, err)
+	if userID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, userID))
+	}
+	return req, httprouter.Params{{Key: "generation_id", Value: generationID}}
+}
+
+func TestCancelGeneration(t *testing.T) {
+	app := newTestApp()
+
+	var cancelStatuses []int
+	rr := &cancellingRecorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		cancel: func(generationID string) {
+			// Another user cannot see the generation, its owner can cancel it.
+			for _, userID := range []string{"someone-else", ""} {
+				cancelRR := httptest.NewRecorder()
+				req, ps := newCancelGenerationRequest(t, generationID, userID)
+				app.CancelGenerationHandler(cancelRR, req, ps)
+				cancelStatuses = append(cancelStatuses, cancelRR.Code)
+			}
+		},
+	}
+
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:  "default-model-id-1",
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "tell me a very long story"}},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []int{http.StatusNotFound, http.StatusNoContent}, cancelStatuses)
+
+	generationID := rr.Header().Get(GenerationIDHeader)
+	assert.NotEmpty(t, generationID)
+
+	events := parseTestSSEEvents(rr.Body.String())
+	assert.Len(t, events, 2)
+	last := events[len(events)-1]
+	assert.Equal(t, GenerationCancelledEventName, last.name)
+
+	var cancelled GenerationCancelledEvent
+	assert.NoError(t, json.Unmarshal([]byte(last.data), &cancelled))
+	assert.Equal(t, generationID, cancelled.GenerationID)
+
+	// Finished generations are unregistered.
+	cancelRR := httptest.NewRecorder()
+	cancelReq, ps := newCancelGenerationRequest(t, generationID, "")
+	app.CancelGenerationHandler(cancelRR, cancelReq, ps)
+	assert.Equal(t, http.StatusNotFound, cancelRR.Code)
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
 )
 
 type Envelope[D any, M any] struct {
@@ -26,13 +29,20 @@ func (app *App) WriteJSON(w http.ResponseWriter, status int, data any, headers h
 
 	js = append(js, '\n')
 
+	return app.WriteBody(w, status, "application/json", js, headers)
+}
+
+// WriteBody writes an already rendered body, it backs WriteJSON and the responses that are not
+// JSON envelopes such as exports.
+func (app *App) WriteBody(w http.ResponseWriter, status int, contentType string, body []byte, headers http.Header) error {
+
 	for key, value := range headers {
 		w.Header()[key] = value
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	_, err = w.Write(js)
+	_, err := w.Write(body)
 
 	if err != nil {
 		return err
@@ -41,6 +51,22 @@ func (app *App) WriteJSON(w http.ResponseWriter, status int, data any, headers h
 	return nil
 }
 
+// AttachmentHeaders offers a response as a file download named filename.
+func AttachmentHeaders(filename string) http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	return headers
+}
+
+// maxUploadSize bounds the bodies of uploads and of requests sent through the Llama Stack proxy,
+// it is the configured MaxUploadSize or DefaultMaxUploadSize when that is not set.
+func (app *App) maxUploadSize() int64 {
+	if app.config.MaxUploadSize <= 0 {
+		return config.DefaultMaxUploadSize
+	}
+	return app.config.MaxUploadSize
+}
+
 func (app *App) ReadJSON(w http.ResponseWriter, r *http.Request, dst any) error {
 
 	maxBytes := 1_048_576
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSchemaRefDepth bounds how many $ref can be followed without descending into the value, it
+// stops schemas referencing themselves from recursing forever.
+const maxSchemaRefDepth = 32
+
+// maxSchemaValidationSteps bounds how many subschemas a value is checked against, schemas such as
+// anyOf entries referencing the root would otherwise take exponential time.
+const maxSchemaValidationSteps = 100_000
+
+var jsonSchemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// SchemaValidationError is a single violation of a JSON schema, Path locates the offending value
+// starting at $ for the root.
+type SchemaValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// jsonSchema validates JSON values against the subset of JSON Schema structured output is
+// described with: type, enum, const, properties, required, additionalProperties, items, the length,
+// size and range bounds, pattern, allOf, anyOf, oneOf, not and local $ref. Other keywords, such
+// as format, are accepted and ignored. It is not safe for concurrent use.
+type jsonSchema struct {
+	root     any
+	patterns map[string]*regexp.Regexp
+	// checkedRefs lists the references whose targets were checked, they can point outside of
+	// the subschemas check walks.
+	checkedRefs map[string]bool
+	// steps counts the subschemas visited by the running validation.
+	steps int
+}
+
+// parseJSONSchema decodes raw and checks the keywords it validates with are well formed.
+func parseJSONSchema(raw json.RawMessage) (*jsonSchema, error) {
+	var root any
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	if _, ok := root.(map[string]any); !ok {
+		return nil, errors.New("schema must be a JSON object")
+	}
+
+	s := &jsonSchema{root: root, patterns: map[string]*regexp.Regexp{}, checkedRefs: map[string]bool{}}
+	if err := s.check(root, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// check walks every subschema of node, location is the JSON pointer of node used in errors.
+func (s *jsonSchema) check(node any, location string) error {
+	switch node := node.(type) {
+	case bool:
+		return nil
+	case map[string]any:
+		if err := s.checkKeywords(node, location); err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(node) {
+			switch key {
+			case "properties", "$defs", "definitions":
+				children, ok := node[key].(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s/%s must be an object", location, key)
+				}
+				for _, name := range sortedKeys(children) {
+					if err := s.check(children[name], location+"/"+key+"/"+name); err != nil {
+						return err
+					}
+				}
+			case "items", "additionalProperties", "not":
+				if err := s.check(node[key], location+"/"+key); err != nil {
+					return err
+				}
+			case "allOf", "anyOf", "oneOf":
+				children, ok := node[key].([]any)
+				if !ok || len(children) == 0 {
+					return fmt.Errorf("%s/%s must be a non-empty array", location, key)
+				}
+				for i, child := range children {
+					if err := s.check(child, fmt.Sprintf("%s/%s/%d", location, key, i)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s must be a schema object or a boolean", location)
+	}
+}
+
+func (s *jsonSchema) checkKeywords(node map[string]any, location string) error {
+	if value, ok := node["type"]; ok {
+		types, ok := schemaTypes(value)
+		if !ok {
+			return fmt.Errorf("%s/type must be a type name or an array of type names", location)
+		}
+		for _, t := range types {
+			if !slices.Contains(jsonSchemaTypes, t) {
+				return fmt.Errorf("%s/type %q is not one of %s", location, t, strings.Join(jsonSchemaTypes, ", "))
+			}
+		}
+	}
+
+	if value, ok := node["required"]; ok {
+		required, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s/required must be an array of property names", location)
+		}
+		for _, name := range required {
+			if _, ok := name.(string); !ok {
+				return fmt.Errorf("%s/required must be an array of property names", location)
+			}
+		}
+	}
+
+	if value, ok := node["enum"]; ok {
+		if _, ok := value.([]any); !ok {
+			return fmt.Errorf("%s/enum must be an array", location)
+		}
+	}
+
+	for _, keyword := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems"} {
+		if value, ok := node[keyword]; ok {
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("%s/%s must be a number", location, keyword)
+			}
+		}
+	}
+
+	if value, ok := node["pattern"]; ok {
+		pattern, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s/pattern must be a string", location)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s/pattern is not a supported regular expression: %w", location, err)
+		}
+		s.patterns[pattern] = re
+	}
+
+	if value, ok := node["$ref"]; ok {
+		ref, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s/$ref must be a string", location)
+		}
+		target, err := s.resolve(ref)
+		if err != nil {
+			return fmt.Errorf("%s/$ref: %w", location, err)
+		}
+		if !s.checkedRefs[ref] {
+			s.checkedRefs[ref] = true
+			if err := s.check(target, ref); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the subschema the local reference ref points to.
+func (s *jsonSchema) resolve(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("only references within the schema are supported, got %q", ref)
+	}
+
+	node := s.root
+	if pointer == "" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch current := node.(type) {
+		case map[string]any:
+			node, ok = current[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			ok = err == nil && i >= 0 && i < len(current)
+			if ok {
+				node = current[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, fmt.Errorf("reference %q does not exist", ref)
+		}
+	}
+	return node, nil
+}
+
+// Validate returns every violation of the schema by value, a value decoded with encoding/json.
+func (s *jsonSchema) Validate(value any) []SchemaValidationError {
+	var errs []SchemaValidationError
+	s.steps = 0
+	s.validate(s.root, value, "$", 0, &errs)
+	if s.steps > maxSchemaValidationSteps {
+		return []SchemaValidationError{{Path: "$", Message: "the schema is too complex to validate the value against"}}
+	}
+	return errs
+}
+
+func (s *jsonSchema) validate(node any, value any, path string, refDepth int, errs *[]SchemaValidationError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, SchemaValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	s.steps++
+	if s.steps > maxSchemaValidationSteps {
+		// Validate reports the exhausted budget.
+		return
+	}
+
+	schema, ok := node.(map[string]any)
+	if !ok {
+		if node == false {
+			fail("no value is allowed here")
+		}
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if refDepth >= maxSchemaRefDepth {
+			fail("schema references are nested too deeply")
+			return
+		}
+		// parseJSONSchema made sure every reference resolves.
+		target, _ := s.resolve(ref)
+		s.validate(target, value, path, refDepth+1, errs)
+	}
+
+	if types, ok := schemaTypes(schema["type"]); ok && !slices.ContainsFunc(types, func(t string) bool { return hasJSONType(value, t) }) {
+		fail("must be of type %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+		// The remaining keywords are about values of the expected type.
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+		fail("must be one of %s", compactJSON(enum))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		fail("must be %s", compactJSON(constant))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		s.validateObject(schema, value, path, errs)
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(value)) < minItems {
+			fail("must contain at least %g items", minItems)
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(value)) > maxItems {
+			fail("must contain at most %g items", maxItems)
+		}
+		if items, ok := schema["items"]; ok {
+			for i, item := range value {
+				s.validate(items, item, fmt.Sprintf("%s[%d]", path, i), 0, errs)
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			fail("must be at least %g characters long", minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			fail("must be at most %g characters long", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !s.patterns[pattern].MatchString(value) {
+			fail("must match the pattern %s", pattern)
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+			fail("must be greater than or equal to %g", minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+			fail("must be less than or equal to %g", maximum)
+		}
+		if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+			fail("must be greater than %g", minimum)
+		}
+		if maximum, ok := schema["exclusiveMaximum"].(float64); ok && value >= maximum {
+			fail("must be less than %g", maximum)
+		}
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, sub := range allOf {
+			s.validate(sub, value, path, refDepth, errs)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok && s.countMatches(anyOf, value, path, refDepth) == 0 {
+		fail("must match at least one of the anyOf schemas")
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		if matches := s.countMatches(oneOf, value, path, refDepth); matches != 1 {
+			fail("must match exactly one of the oneOf schemas, matched %d", matches)
+		}
+	}
+	if not, ok := schema["not"]; ok && s.countMatches([]any{not}, value, path, refDepth) == 1 {
+		fail("must not match the not schema")
+	}
+}
+
+func (s *jsonSchema) validateObject(schema map[string]any, value map[string]any, path string, errs *[]SchemaValidationError) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if _, ok := value[name.(string)]; !ok {
+				*errs = append(*errs, SchemaValidationError{Path: propertyPath(path, name.(string)), Message: "is required"})
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+
+	for _, name := range sortedKeys(value) {
+		if property, ok := properties[name]; ok {
+			s.validate(property, value[name], propertyPath(path, name), 0, errs)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if additional == false {
+			*errs = append(*errs, SchemaValidationError{Path: propertyPath(path, name), Message: "is not an allowed property"})
+			continue
+		}
+		s.validate(additional, value[name], propertyPath(path, name), 0, errs)
+	}
+}
+
+// countMatches returns how many of schemas value is valid against.
+func (s *jsonSchema) countMatches(schemas []any, value any, path string, refDepth int) int {
+	matches := 0
+	for _, sub := range schemas {
+		var errs []SchemaValidationError
+		s.validate(sub, value, path, refDepth, &errs)
+		if len(errs) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func schemaTypes(value any) ([]string, bool) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, true
+	case []any:
+		types := make([]string, 0, len(value))
+		for _, t := range value {
+			name, ok := t.(string)
+			if !ok {
+				return nil, false
+			}
+			types = append(types, name)
+		}
+		return types, len(types) > 0
+	}
+	return nil, false
+}
+
+func hasJSONType(value any, t string) bool {
+	switch t {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return jsonTypeName(value) == t
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func propertyPath(path string, name string) string {
+	if name != "" && strings.IndexFunc(name, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) == -1 {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+func compactJSON(value any) string {
+	js, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(js)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,341 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonSchemaConformanceSuite follows the layout of the JSON-Schema-Test-Suite, the cases are
+// taken from its draft 2020-12 tests for the keywords jsonSchema supports.
+const jsonSchemaConformanceSuite = `[
+	{
+		"description": "type",
+		"schema": {"type": "integer"},
+		"tests": [
+			{"description": "an integer is an integer", "data": 1, "valid": true},
+			{"description": "a float with zero fractional part is an integer", "data": 1.0, "valid": true},
+			{"description": "a float is not an integer", "data": 1.1, "valid": false},
+			{"description": "a string is not an integer", "data": "foo", "valid": false},
+			{"description": "a string is still not an integer, even if it looks like one", "data": "1", "valid": false},
+			{"description": "null is not an integer", "data": null, "valid": false}
+		]
+	},
+	{
+		"description": "multiple types can be specified in an array",
+		"schema": {"type": ["integer", "string"]},
+		"tests": [
+			{"description": "an integer is valid", "data": 1, "valid": true},
+			{"description": "a string is valid", "data": "foo", "valid": true},
+			{"description": "a float is invalid", "data": 1.1, "valid": false},
+			{"description": "an object is invalid", "data": {}, "valid": false},
+			{"description": "an array is invalid", "data": [], "valid": false},
+			{"description": "a boolean is invalid", "data": true, "valid": false}
+		]
+	},
+	{
+		"description": "simple enum validation",
+		"schema": {"enum": [1, 2, 3]},
+		"tests": [
+			{"description": "one of the enum is valid", "data": 1, "valid": true},
+			{"description": "something else is invalid", "data": 4, "valid": false}
+		]
+	},
+	{
+		"description": "enum with false does not match 0",
+		"schema": {"enum": [false]},
+		"tests": [
+			{"description": "false is valid", "data": false, "valid": true},
+			{"description": "integer zero is invalid", "data": 0, "valid": false}
+		]
+	},
+	{
+		"description": "const with object",
+		"schema": {"const": {"foo": "bar", "baz": "bax"}},
+		"tests": [
+			{"description": "same object is valid", "data": {"foo": "bar", "baz": "bax"}, "valid": true},
+			{"description": "same object with different property order is valid", "data": {"baz": "bax", "foo": "bar"}, "valid": true},
+			{"description": "another object is invalid", "data": {"foo": "bar"}, "valid": false},
+			{"description": "another type is invalid", "data": [1, 2], "valid": false}
+		]
+	},
+	{
+		"description": "object properties validation",
+		"schema": {"properties": {"foo": {"type": "integer"}, "bar": {"type": "string"}}},
+		"tests": [
+			{"description": "both properties present and valid is valid", "data": {"foo": 1, "bar": "baz"}, "valid": true},
+			{"description": "one property invalid is invalid", "data": {"foo": 1, "bar": {}}, "valid": false},
+			{"description": "both properties invalid is invalid", "data": {"foo": [], "bar": {}}, "valid": false},
+			{"description": "doesn't invalidate other properties", "data": {"quux": []}, "valid": true},
+			{"description": "ignores arrays", "data": [], "valid": true},
+			{"description": "ignores other non-objects", "data": 12, "valid": true}
+		]
+	},
+	{
+		"description": "required validation",
+		"schema": {"properties": {"foo": {}, "bar": {}}, "required": ["foo"]},
+		"tests": [
+			{"description": "present required property is valid", "data": {"foo": 1}, "valid": true},
+			{"description": "non-present required property is invalid", "data": {"bar": 1}, "valid": false},
+			{"description": "ignores arrays", "data": [], "valid": true},
+			{"description": "ignores strings", "data": "", "valid": true}
+		]
+	},
+	{
+		"description": "additionalProperties being false does not allow other properties",
+		"schema": {"properties": {"foo": {}, "bar": {}}, "additionalProperties": false},
+		"tests": [
+			{"description": "no additional properties is valid", "data": {"foo": 1}, "valid": true},
+			{"description": "an additional property is invalid", "data": {"foo": 1, "bar": 2, "quux": "boom"}, "valid": false},
+			{"description": "ignores arrays", "data": [1, 2, 3], "valid": true}
+		]
+	},
+	{
+		"description": "additionalProperties allows a schema which should validate",
+		"schema": {"properties": {"foo": {}, "bar": {}}, "additionalProperties": {"type": "boolean"}},
+		"tests": [
+			{"description": "no additional properties is valid", "data": {"foo": 1}, "valid": true},
+			{"description": "an additional valid property is valid", "data": {"foo": 1, "bar": 2, "quux": true}, "valid": true},
+			{"description": "an additional invalid property is invalid", "data": {"foo": 1, "bar": 2, "quux": 12}, "valid": false}
+		]
+	},
+	{
+		"description": "a schema given for items",
+		"schema": {"items": {"type": "integer"}},
+		"tests": [
+			{"description": "valid items", "data": [1, 2, 3], "valid": true},
+			{"description": "wrong type of items", "data": [1, "x"], "valid": false},
+			{"description": "ignores non-arrays", "data": {"foo": "bar"}, "valid": true}
+		]
+	},
+	{
+		"description": "items with boolean schema (false)",
+		"schema": {"items": false},
+		"tests": [
+			{"description": "any non-empty array is invalid", "data": [1, "foo", true], "valid": false},
+			{"description": "empty array is valid", "data": [], "valid": true}
+		]
+	},
+	{
+		"description": "minItems and maxItems validation",
+		"schema": {"minItems": 1, "maxItems": 2},
+		"tests": [
+			{"description": "exact length is valid", "data": [1], "valid": true},
+			{"description": "too short is invalid", "data": [], "valid": false},
+			{"description": "too long is invalid", "data": [1, 2, 3], "valid": false},
+			{"description": "ignores non-arrays", "data": "", "valid": true}
+		]
+	},
+	{
+		"description": "minLength and maxLength validation",
+		"schema": {"minLength": 2, "maxLength": 2},
+		"tests": [
+			{"description": "exact length is valid", "data": "fo", "valid": true},
+			{"description": "too short is invalid", "data": "f", "valid": false},
+			{"description": "too long is invalid", "data": "foo", "valid": false},
+			{"description": "ignores non-strings", "data": 100, "valid": true},
+			{"description": "two graphemes is long enough", "data": "💩💩", "valid": true}
+		]
+	},
+	{
+		"description": "pattern validation",
+		"schema": {"pattern": "^a*$"},
+		"tests": [
+			{"description": "a matching pattern is valid", "data": "aaa", "valid": true},
+			{"description": "a non-matching pattern is invalid", "data": "abc", "valid": false},
+			{"description": "ignores booleans", "data": true, "valid": true}
+		]
+	},
+	{
+		"description": "pattern is not anchored",
+		"schema": {"pattern": "a+"},
+		"tests": [
+			{"description": "matches a substring", "data": "xxaayy", "valid": true}
+		]
+	},
+	{
+		"description": "minimum and maximum validation",
+		"schema": {"minimum": 1.1, "maximum": 3.0},
+		"tests": [
+			{"description": "below the maximum is valid", "data": 2.6, "valid": true},
+			{"description": "boundary point is valid", "data": 3.0, "valid": true},
+			{"description": "above the maximum is invalid", "data": 3.5, "valid": false},
+			{"description": "below the minimum is invalid", "data": 0.6, "valid": false},
+			{"description": "ignores non-numbers", "data": "x", "valid": true}
+		]
+	},
+	{
+		"description": "exclusiveMinimum and exclusiveMaximum validation",
+		"schema": {"exclusiveMinimum": 1.1, "exclusiveMaximum": 3.0},
+		"tests": [
+			{"description": "between the bounds is valid", "data": 2.2, "valid": true},
+			{"description": "boundary point is invalid", "data": 3.0, "valid": false},
+			{"description": "lower boundary point is invalid", "data": 1.1, "valid": false}
+		]
+	},
+	{
+		"description": "allOf",
+		"schema": {"allOf": [{"properties": {"bar": {"type": "integer"}}, "required": ["bar"]}, {"properties": {"foo": {"type": "string"}}, "required": ["foo"]}]},
+		"tests": [
+			{"description": "allOf", "data": {"foo": "baz", "bar": 2}, "valid": true},
+			{"description": "mismatch second", "data": {"foo": "baz"}, "valid": false},
+			{"description": "mismatch first", "data": {"bar": 2}, "valid": false},
+			{"description": "wrong type", "data": {"foo": "baz", "bar": "quux"}, "valid": false}
+		]
+	},
+	{
+		"description": "anyOf",
+		"schema": {"anyOf": [{"type": "integer"}, {"minimum": 2}]},
+		"tests": [
+			{"description": "first anyOf valid", "data": 1, "valid": true},
+			{"description": "second anyOf valid", "data": 2.5, "valid": true},
+			{"description": "both anyOf valid", "data": 3, "valid": true},
+			{"description": "neither anyOf valid", "data": 1.5, "valid": false}
+		]
+	},
+	{
+		"description": "oneOf",
+		"schema": {"oneOf": [{"type": "integer"}, {"minimum": 2}]},
+		"tests": [
+			{"description": "first oneOf valid", "data": 1, "valid": true},
+			{"description": "second oneOf valid", "data": 2.5, "valid": true},
+			{"description": "both oneOf valid", "data": 3, "valid": false},
+			{"description": "neither oneOf valid", "data": 1.5, "valid": false}
+		]
+	},
+	{
+		"description": "not",
+		"schema": {"not": {"type": "integer"}},
+		"tests": [
+			{"description": "allowed", "data": "foo", "valid": true},
+			{"description": "disallowed", "data": 1, "valid": false}
+		]
+	},
+	{
+		"description": "boolean subschemas",
+		"schema": {"properties": {"foo": true, "bar": false}},
+		"tests": [
+			{"description": "a property with a true schema is valid", "data": {"foo": 1}, "valid": true},
+			{"description": "a property with a false schema is invalid", "data": {"bar": 2}, "valid": false}
+		]
+	},
+	{
+		"description": "root pointer ref",
+		"schema": {"properties": {"foo": {"$ref": "#"}}, "additionalProperties": false},
+		"tests": [
+			{"description": "match", "data": {"foo": false}, "valid": true},
+			{"description": "recursive match", "data": {"foo": {"foo": false}}, "valid": true},
+			{"description": "mismatch", "data": {"bar": false}, "valid": false},
+			{"description": "recursive mismatch", "data": {"foo": {"bar": false}}, "valid": false}
+		]
+	},
+	{
+		"description": "relative pointer ref to array",
+		"schema": {"items": {"$ref": "#/$defs/item"}, "$defs": {"item": {"type": "integer"}}},
+		"tests": [
+			{"description": "match array", "data": [1, 2], "valid": true},
+			{"description": "mismatch array", "data": [1, "foo"], "valid": false}
+		]
+	},
+	{
+		"description": "escaped pointer ref",
+		"schema": {
+			"$defs": {"tilde~field": {"type": "integer"}, "slash/field": {"type": "integer"}},
+			"properties": {"tilde": {"$ref": "#/$defs/tilde~0field"}, "slash": {"$ref": "#/$defs/slash~1field"}}
+		},
+		"tests": [
+			{"description": "slash invalid", "data": {"slash": "aoeu"}, "valid": false},
+			{"description": "tilde invalid", "data": {"tilde": "aoeu"}, "valid": false},
+			{"description": "slash valid", "data": {"slash": 123}, "valid": true},
+			{"description": "tilde valid", "data": {"tilde": 123}, "valid": true}
+		]
+	},
+	{
+		"description": "ref applies alongside sibling keywords",
+		"schema": {"$defs": {"reffed": {"type": "array"}}, "properties": {"foo": {"$ref": "#/$defs/reffed", "maxItems": 2}}},
+		"tests": [
+			{"description": "ref valid, maxItems valid", "data": {"foo": []}, "valid": true},
+			{"description": "ref valid, maxItems invalid", "data": {"foo": [1, 2, 3]}, "valid": false},
+			{"description": "ref invalid", "data": {"foo": "string"}, "valid": false}
+		]
+	}
+]`
+
+type jsonSchemaConformanceGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string `json:"description"`
+		Data        any    `json:"data"`
+		Valid       bool   `json:"valid"`
+	} `json:"tests"`
+}
+
+func TestJSONSchemaConformance(t *testing.T) {
+	var groups []jsonSchemaConformanceGroup
+	assert.NoError(t, json.Unmarshal([]byte(jsonSchemaConformanceSuite), &groups))
+
+	for _, group := range groups {
+		t.Run(group.Description, func(t *testing.T) {
+			schema, err := parseJSONSchema(group.Schema)
+			assert.NoError(t, err)
+			if err != nil {
+				return
+			}
+
+			for _, tt := range group.Tests {
+				errs := schema.Validate(tt.Data)
+				assert.Equal(t, tt.Valid, len(errs) == 0, "%s: %v", tt.Description, errs)
+			}
+		})
+	}
+}
+
+func TestParseJSONSchemaChecksReferencedSchemas(t *testing.T) {
+	// The referenced schemas sit where check does not walk on its own.
+	for _, raw := range []string{
+		`{"$ref": "#/x", "x": {"type": "string", "pattern": "(?<=a)b"}}`,
+		`{"$ref": "#/x", "x": {"required": [1]}}`,
+	} {
+		_, err := parseJSONSchema(json.RawMessage(raw))
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestJSONSchemaValidateTooComplex(t *testing.T) {
+	// Every anyOf entry references the root, the number of subschemas grows exponentially.
+	schema, err := parseJSONSchema(json.RawMessage(`{"anyOf": [{"$ref": "#"}, {"$ref": "#"}, {"$ref": "#"}]}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []SchemaValidationError{{Path: "$", Message: "the schema is too complex to validate the value against"}}, schema.Validate("x"))
+}
+
+func FuzzJSONSchemaValidate(f *testing.F) {
+	var groups []jsonSchemaConformanceGroup
+	if err := json.Unmarshal([]byte(jsonSchemaConformanceSuite), &groups); err != nil {
+		f.Fatal(err)
+	}
+	for _, group := range groups {
+		for _, tt := range group.Tests {
+			data, _ := json.Marshal(tt.Data)
+			f.Add(string(group.Schema), string(data))
+		}
+	}
+	f.Add(testInvoiceSchema, `{"vendor": "ACME", "total": 1, "lines": [{"quantity": 2}]}`)
+
+	f.Fuzz(func(t *testing.T, rawSchema string, rawValue string) {
+		schema, err := parseJSONSchema(json.RawMessage(rawSchema))
+		if err != nil {
+			return
+		}
+
+		var value any
+		if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+			return
+		}
+
+		// Any schema that parses validates any value without panicking.
+		schema.Validate(value)
+	})
+}
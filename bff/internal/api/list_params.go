@@ -0,0 +1,182 @@
+package api
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+const (
+	AscendingOrder  = "asc"
+	DescendingOrder = "desc"
+
+	maxListLimit = 1000
+)
+
+// ListParams holds the pagination and sorting query parameters of list endpoints:
+//
+//	limit       the maximum number of items returned, all items when omitted
+//	offset      the number of items skipped, exclusive with page_token
+//	page_token  the next_page_token of the previous page
+//	sort        the field items are sorted by, the upstream order is kept when omitted
+//	order       asc (default) or desc
+type ListParams struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Order  string
+	// filters is the hash of the limit and the filters of the list, see listFiltersHash.
+	filters string
+}
+
+// pageToken is the content of page tokens, the sort, order, limit and filters are kept so the
+// token cannot be used to continue a different listing.
+type pageToken struct {
+	Offset  int    `json:"offset"`
+	Sort    string `json:"sort,omitempty"`
+	Order   string `json:"order,omitempty"`
+	Filters string `json:"filters,omitempty"`
+}
+
+// listSortFields maps the sort query values a list supports to how they compare items.
+type listSortFields[T any] map[string]func(a, b T) int
+
+// parseListParams reads the list parameters from query, errors are keyed by parameter name.
+func parseListParams[T any](query url.Values, sortFields listSortFields[T]) (ListParams, map[string]string) {
+	params := ListParams{Sort: query.Get("sort"), Order: query.Get("order"), filters: listFiltersHash(query)}
+	validationErrors := map[string]string{}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			validationErrors["limit"] = fmt.Sprintf("must be an integer between 1 and %d", maxListLimit)
+		}
+		params.Limit = limit
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			validationErrors["offset"] = "must be a non-negative integer"
+		}
+		params.Offset = offset
+	}
+
+	if params.Sort != "" {
+		if _, ok := sortFields[params.Sort]; !ok {
+			fields := make([]string, 0, len(sortFields))
+			for field := range sortFields {
+				fields = append(fields, field)
+			}
+			slices.Sort(fields)
+			validationErrors["sort"] = "must be one of " + strings.Join(fields, ", ")
+		}
+	}
+
+	switch params.Order {
+	case "":
+		params.Order = AscendingOrder
+	case AscendingOrder, DescendingOrder:
+	default:
+		validationErrors["order"] = fmt.Sprintf("must be %s or %s", AscendingOrder, DescendingOrder)
+	}
+
+	if value := query.Get("page_token"); value != "" {
+		token, err := decodePageToken(value)
+		switch {
+		case query.Has("offset"):
+			validationErrors["page_token"] = "cannot be combined with offset"
+		case err != nil:
+			validationErrors["page_token"] = "is not a valid page token"
+		case token.Sort != params.Sort || token.Order != params.Order:
+			validationErrors["page_token"] = "was issued for a different sort or order"
+		case token.Filters != params.filters:
+			validationErrors["page_token"] = "was issued for a different limit or filters"
+		default:
+			params.Offset = token.Offset
+		}
+	}
+
+	return params, validationErrors
+}
+
+// paginate sorts items and cuts out the page selected by params.
+func paginate[T any](items []T, params ListParams, sortFields listSortFields[T]) ([]T, *models.ListMetadata) {
+	if compare, ok := sortFields[params.Sort]; ok {
+		items = slices.Clone(items)
+		slices.SortStableFunc(items, func(a, b T) int {
+			if params.Order == DescendingOrder {
+				return compare(b, a)
+			}
+			return compare(a, b)
+		})
+	}
+
+	metadata := &models.ListMetadata{TotalCount: len(items)}
+
+	start := min(params.Offset, len(items))
+	end := len(items)
+	if params.Limit > 0 && start+params.Limit < end {
+		end = start + params.Limit
+		metadata.NextPageToken = encodePageToken(pageToken{Offset: end, Sort: params.Sort, Order: params.Order, Filters: params.filters})
+	}
+
+	return items[start:end], metadata
+}
+
+// listFiltersHash hashes the query parameters other than the position and sorting ones, which
+// are the limit and the filters of the list endpoint. It is empty when there are none.
+func listFiltersHash(query url.Values) string {
+	filters := url.Values{}
+	for key, values := range query {
+		switch key {
+		case "offset", "page_token", "sort", "order":
+		default:
+			filters[key] = values
+		}
+	}
+	if len(filters) == 0 {
+		return ""
+	}
+
+	// Encode sorts by key, equal filters give equal hashes.
+	sum := sha256.Sum256([]byte(filters.Encode()))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func encodePageToken(token pageToken) string {
+	// Marshaling a struct of strings and ints cannot fail.
+	js, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+func decodePageToken(value string) (pageToken, error) {
+	var token pageToken
+
+	js, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return token, err
+	}
+	if err := json.Unmarshal(js, &token); err != nil {
+		return token, err
+	}
+	if token.Offset < 0 {
+		return token, fmt.Errorf("negative offset %d", token.Offset)
+	}
+	return token, nil
+}
+
+// compareStrings returns a sort comparison of the string field of items returned by field.
+func compareStrings[T any](field func(T) string) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(field(a), field(b))
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestModelPage(t *testing.T, app App, query url.Values) ModelListEnvelope {
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, ModelListPath+"?"+query.Encode(), nil)
+	app.GetAllModelsHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, query.Encode())
+
+	var envelope ModelListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	return envelope
+}
+
+func TestGetAllModelsHandlerPagination(t *testing.T) {
+	app := newTestApp()
+
+	query := url.Values{"limit": {"2"}, "sort": {"identifier"}, "order": {"desc"}}
+	first := getTestModelPage(t, app, query)
+
+	assert.Len(t, first.Data.Items, 2)
+	assert.Equal(t, "default-model-id-3", first.Data.Items[0].Identifier)
+	assert.Equal(t, "default-model-id-2", first.Data.Items[1].Identifier)
+	assert.Equal(t, 3, first.Metadata.TotalCount)
+	assert.NotEmpty(t, first.Metadata.NextPageToken)
+
+	query.Set("page_token", first.Metadata.NextPageToken)
+	second := getTestModelPage(t, app, query)
+
+	assert.Len(t, second.Data.Items, 1)
+	assert.Equal(t, "default-model-id-1", second.Data.Items[0].Identifier)
+	assert.Empty(t, second.Metadata.NextPageToken)
+
+	// Offsets past the end give an empty page.
+	last := getTestModelPage(t, app, url.Values{"offset": {"5"}})
+	assert.Empty(t, last.Data.Items)
+	assert.Equal(t, 3, last.Metadata.TotalCount)
+}
+
+func TestParseListParamsValidation(t *testing.T) {
+	token := encodePageToken(pageToken{Offset: 2, Sort: "identifier", Order: AscendingOrder})
+
+	tests := []struct {
+		query string
+		field string
+	}{
+		{query: "limit=0", field: "limit"},
+		{query: "limit=abc", field: "limit"},
+		{query: "offset=-1", field: "offset"},
+		{query: "sort=created_at", field: "sort"},
+		{query: "order=up", field: "order"},
+		{query: "page_token=%21%21", field: "page_token"},
+		{query: "page_token=" + token + "&offset=1&sort=identifier", field: "page_token"},
+		{query: "page_token=" + token + "&sort=identifier&order=desc", field: "page_token"},
+	}
+
+	for _, tt := range tests {
+		query, err := url.ParseQuery(tt.query)
+		assert.NoError(t, err)
+
+		_, validationErrors := parseListParams(query, modelSortFields)
+		assert.Contains(t, validationErrors, tt.field, tt.query)
+	}
+
+	params, validationErrors := parseListParams(url.Values{"page_token": {token}, "sort": {"identifier"}}, modelSortFields)
+	assert.Empty(t, validationErrors)
+	assert.Equal(t, ListParams{Offset: 2, Sort: "identifier", Order: AscendingOrder}, params)
+}
+
+func TestPageTokenKeepsFilters(t *testing.T) {
+	app := newTestApp()
+
+	query := url.Values{"limit": {"1"}, "model_type": {"llm"}}
+	first := getTestModelPage(t, app, query)
+	assert.NotEmpty(t, first.Metadata.NextPageToken)
+
+	// The same query continues the listing.
+	query.Set("page_token", first.Metadata.NextPageToken)
+	getTestModelPage(t, app, query)
+
+	for _, changed := range []url.Values{
+		{"limit": {"1"}, "model_type": {"embedding"}},
+		{"limit": {"1"}, "model_type": {"llm"}, "search": {"model"}},
+		{"limit": {"1"}},
+		{"limit": {"2"}, "model_type": {"llm"}},
+	} {
+		changed.Set("page_token", first.Metadata.NextPageToken)
+
+		_, validationErrors := parseListParams(changed, modelSortFields)
+		assert.Equal(t, "was issued for a different limit or filters", validationErrors["page_token"], changed.Encode())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
@@ -39,10 +40,12 @@ func (app *App) EnableCORS(next http.Handler) http.Handler {
 	}
 
 	c := cors.New(cors.Options{
-		AllowedOrigins:     app.config.AllowedOrigins,
-		AllowCredentials:   true,
-		AllowedMethods:     []string{"GET", "PUT", "POST", "PATCH", "DELETE"},
-		AllowedHeaders:     []string{},
+		AllowedOrigins:   app.config.AllowedOrigins,
+		AllowCredentials: true,
+		AllowedMethods:   []string{"GET", "PUT", "POST", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{},
+		// Lets the browser read the streaming headers of chat completions.
+		ExposedHeaders:     []string{GenerationIDHeader, MessagesTrimmedHeader},
 		Debug:              app.config.LogLevel == slog.LevelDebug,
 		OptionsPassthrough: false,
 	})
@@ -82,13 +85,15 @@ func (app *App) RequireAuth(next http.Handler) http.Handler {
 
 		logger := helper.GetContextLoggerFromReq(r)
 		oauthHandler := auth.NewOAuthHandler(app.config, logger)
-		if err := oauthHandler.ValidateToken(r.Context(), token); err != nil {
+		userID, err := oauthHandler.ValidateToken(r.Context(), token)
+		if err != nil {
 			app.forbiddenResponse(w, r, err.Error())
 			return
 		}
 
-		// Store token in context for downstream use
+		// Store token and user in context for downstream use
 		ctx := context.WithValue(r.Context(), constants.AuthTokenKey, token)
+		ctx = context.WithValue(ctx, constants.UserIDKey, userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -107,17 +112,37 @@ func (app *App) RequireAuthRoute(next func(http.ResponseWriter, *http.Request, h
 
 		logger := helper.GetContextLoggerFromReq(r)
 		oauthHandler := auth.NewOAuthHandler(app.config, logger)
-		if err := oauthHandler.ValidateToken(r.Context(), token); err != nil {
+		userID, err := oauthHandler.ValidateToken(r.Context(), token)
+		if err != nil {
 			app.forbiddenResponse(w, r, err.Error())
 			return
 		}
 
-		// Store token in context for downstream use
+		// Store token and user in context for downstream use
 		ctx := context.WithValue(r.Context(), constants.AuthTokenKey, token)
+		ctx = context.WithValue(ctx, constants.UserIDKey, userID)
 		next(w, r.WithContext(ctx), ps)
 	}
 }
 
+// RequireAdminRoute only lets the configured admin users through, it must run after
+// RequireAuthRoute. Without OAuth every request comes from the anonymous user and is let through.
+func (app *App) RequireAdminRoute(next func(http.ResponseWriter, *http.Request, httprouter.Params)) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if !app.isAdmin(r) {
+			app.forbiddenResponse(w, r, "admin access required")
+			return
+		}
+		next(w, r, ps)
+	}
+}
+
+// isAdmin reports whether the user of r is one of the configured admin users, everyone is
+// without OAuth.
+func (app *App) isAdmin(r *http.Request) bool {
+	return !app.config.OAuthEnabled || slices.Contains(app.config.AdminUsers, requestUserID(r))
+}
+
 func (app *App) AttachRESTClient(next func(http.ResponseWriter, *http.Request, httprouter.Params)) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		// Set up a child logger for the rest client that automatically adds the request id to all statements for
@@ -144,3 +169,15 @@ func (app *App) AttachRESTClient(next func(http.ResponseWriter, *http.Request, h
 		next(w, r.WithContext(ctx), ps)
 	}
 }
+
+// AnonymousUserID owns all user scoped data when OAuth is disabled.
+const AnonymousUserID = "anonymous"
+
+// requestUserID returns the user authenticated by RequireAuth or RequireAuthRoute.
+func requestUserID(r *http.Request) string {
+	userID, ok := r.Context().Value(constants.UserIDKey).(string)
+	if !ok || userID == "" {
+		return AnonymousUserID
+	}
+	return userID
+}
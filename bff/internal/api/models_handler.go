@@ -2,7 +2,10 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
@@ -12,13 +15,33 @@ import (
 )
 
 type ModelEnvelope Envelope[models.Model, None]
-type ModelListEnvelope Envelope[models.ModelList, None]
+type ModelListEnvelope Envelope[models.ModelList, *models.ListMetadata]
+type ModelParametersEnvelope Envelope[models.ModelParameters, None]
 
 const (
 	LLMModelType       = "llm"
 	EmbeddingModelType = "embedding"
 )
 
+var modelSortFields = listSortFields[llamastack.Model]{
+	"identifier":  compareStrings(func(m llamastack.Model) string { return m.Identifier }),
+	"provider_id": compareStrings(func(m llamastack.Model) string { return m.ProviderID }),
+	"model_type":  compareStrings(func(m llamastack.Model) string { return string(m.ModelType) }),
+}
+
+// ModelRegistrationRequest represents the request body for registering a model served by a
+// provider of the distribution, e.g. a model newly deployed on a vLLM server.
+type ModelRegistrationRequest struct {
+	ModelID    string `json:"model_id"`
+	ProviderID string `json:"provider_id"`
+	// The id of the model at the provider, defaults to ModelID.
+	ProviderResourceID string `json:"provider_resource_id,omitempty"`
+	// Defaults to LLMModelType.
+	ModelType string `json:"model_type,omitempty"`
+	// Embedding models must set embedding_dimension.
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
 func (app *App) GetAllModelsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
 
@@ -27,36 +50,69 @@ func (app *App) GetAllModelsHandler(w http.ResponseWriter, r *http.Request, _ ht
 		return
 	}
 
+	query := r.URL.Query()
+	listParams, validationErrors := parseListParams(query, modelSortFields)
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
 	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	params := r.URL.Query()
-	modelType := params.Get("model_type")
+	modelList = filterModels(modelList, query.Get("model_type"), query.Get("provider_id"), query.Get("search"))
+	page, metadata := paginate(modelList.Data, listParams, modelSortFields)
 
-	switch modelType {
-	case LLMModelType:
-		llmModels := []llamastack.Model{}
-		for _, model := range modelList.Data {
-			if model.ModelType == llamastack.LLMModelType {
-				llmModels = append(llmModels, model)
-			}
-		}
-		modelList = &llamastack.ModelList{Data: llmModels}
-	case EmbeddingModelType:
-		embeddingModels := []llamastack.Model{}
-		for _, model := range modelList.Data {
-			if model.ModelType == llamastack.EmbeddingModelType {
-				embeddingModels = append(embeddingModels, model)
-			}
-		}
-		modelList = &llamastack.ModelList{Data: embeddingModels}
+	result := ModelListEnvelope{
+		Data:     convertModelList(&llamastack.ModelList{Data: page}),
+		Metadata: metadata,
 	}
 
-	result := ModelListEnvelope{
-		Data: convertModelList(modelList),
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ModelHandler serves the sub resources of a model. Model identifiers usually contain slashes,
+// e.g. meta-llama/Llama-3.2-3B-Instruct, so the route uses a catch-all and the resource is
+// resolved from the end of the path.
+func (app *App) ModelHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	modelPath := strings.TrimPrefix(ps.ByName("model_path"), "/")
+
+	if modelID, ok := strings.CutSuffix(modelPath, ModelParametersSuffix); ok && modelID != "" {
+		app.GetModelParametersHandler(w, r, modelID)
+		return
+	}
+
+	if modelPath != "" {
+		app.GetModelHandler(w, r, modelPath)
+		return
+	}
+
+	app.notFoundResponse(w, r)
+}
+
+func (app *App) GetModelHandler(w http.ResponseWriter, r *http.Request, modelID string) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	model, err := app.repositories.LlamaStackClient.GetModel(client, modelID)
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	result := ModelEnvelope{
+		Data: convertModel(model),
 	}
 
 	err = app.WriteJSON(w, http.StatusOK, result, nil)
@@ -66,12 +122,180 @@ func (app *App) GetAllModelsHandler(w http.ResponseWriter, r *http.Request, _ ht
 	}
 }
 
+func (app *App) GetModelParametersHandler(w http.ResponseWriter, r *http.Request, modelID string) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if findModel(modelList, modelID) == nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	profile := app.config.ModelProfiles.ProfileFor(modelID)
+
+	result := ModelParametersEnvelope{
+		Data: models.ModelParameters{
+			ModelID:  modelID,
+			Defaults: profile.Defaults,
+			Ranges:   profile.Ranges,
+		},
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) RegisterModelHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var requestBody ModelRegistrationRequest
+	if err := app.ReadJSON(w, r, &requestBody); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if requestBody.ModelType == "" {
+		requestBody.ModelType = LLMModelType
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := validateModelRegistration(requestBody, modelList); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	model, err := app.repositories.LlamaStackClient.RegisterModel(client, llamastack.ModelRegistrationRequest{
+		ModelID:         requestBody.ModelID,
+		ProviderModelID: requestBody.ProviderResourceID,
+		ProviderID:      requestBody.ProviderID,
+		ModelType:       llamastack.ModelModelType(requestBody.ModelType),
+		Metadata:        requestBody.Metadata,
+	})
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, ModelEnvelope{Data: convertModel(model)}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UnregisterModelHandler serves DELETE on ModelPath, the whole path is the model identifier.
+func (app *App) UnregisterModelHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	modelID := strings.TrimPrefix(ps.ByName("model_path"), "/")
+	if modelID == "" {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	if err := app.repositories.LlamaStackClient.UnregisterModel(client, modelID); err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateModelRegistration(request ModelRegistrationRequest, modelList *llamastack.ModelList) map[string]string {
+	validationErrors := map[string]string{}
+
+	switch {
+	case strings.TrimSpace(request.ModelID) == "":
+		validationErrors["model_id"] = "must be provided"
+	case strings.HasSuffix(request.ModelID, ModelParametersSuffix):
+		validationErrors["model_id"] = fmt.Sprintf("must not end with %s", ModelParametersSuffix)
+	case findModel(modelList, request.ModelID) != nil:
+		validationErrors["model_id"] = fmt.Sprintf("model %q already exists", request.ModelID)
+	}
+
+	if strings.TrimSpace(request.ProviderID) == "" {
+		validationErrors["provider_id"] = "must be provided"
+	}
+
+	switch request.ModelType {
+	case LLMModelType:
+	case EmbeddingModelType:
+		// Llama Stack needs the dimension to create vector databases for the model.
+		dimension, ok := request.Metadata["embedding_dimension"].(float64)
+		if !ok || dimension <= 0 || dimension != math.Trunc(dimension) {
+			validationErrors["metadata.embedding_dimension"] = "must be a positive integer for embedding models"
+		}
+	default:
+		validationErrors["model_type"] = fmt.Sprintf("must be %s or %s", LLMModelType, EmbeddingModelType)
+	}
+
+	return validationErrors
+}
+
+// filterModels returns the models of modelList matching every filter that is set. modelType
+// only filters when it is a known model type, search matches the identifiers of the model
+// ignoring case.
+func filterModels(modelList *llamastack.ModelList, modelType string, providerID string, search string) *llamastack.ModelList {
+	if modelType != LLMModelType && modelType != EmbeddingModelType {
+		modelType = ""
+	}
+	search = strings.ToLower(strings.TrimSpace(search))
+
+	filtered := []llamastack.Model{}
+	for _, model := range modelList.Data {
+		if modelType != "" && string(model.ModelType) != modelType {
+			continue
+		}
+		if providerID != "" && model.ProviderID != providerID {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(model.Identifier), search) &&
+			!strings.Contains(strings.ToLower(model.ProviderResourceID), search) &&
+			!strings.Contains(strings.ToLower(model.ProviderID), search) {
+			continue
+		}
+		filtered = append(filtered, model)
+	}
+
+	return &llamastack.ModelList{Data: filtered}
+}
+
 func convertModel(model *llamastack.Model) models.Model {
 	return models.Model{
 		Identifier:         model.Identifier,
 		ModelType:          string(model.ModelType),
 		ProviderID:         model.ProviderID,
 		ProviderResourceID: model.ProviderResourceID,
+		Metadata:           model.Metadata,
 	}
 }
 
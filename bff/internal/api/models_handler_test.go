@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestModelProfiles() config.ModelProfiles {
+	temperature := 0.7
+	return config.ModelProfiles{
+		config.DefaultModelProfileKey: {
+			Ranges: models.SamplingParamRanges{
+				MaxTokens: &models.ParameterRange{Min: 1, Max: 4096},
+			},
+		},
+		"default-model-id-1": {
+			Defaults: models.SamplingParams{Temperature: &temperature},
+			Ranges: models.SamplingParamRanges{
+				Temperature: &models.ParameterRange{Min: 0, Max: 1},
+			},
+		},
+	}
+}
+
+func TestGetModelParametersHandler(t *testing.T) {
+	app := newTestApp()
+	app.config.ModelProfiles = newTestModelProfiles()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, ModelListPath+"/default-model-id-1"+ModelParametersSuffix, nil)
+	app.ModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/default-model-id-1" + ModelParametersSuffix}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope ModelParametersEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, 0.7, *envelope.Data.Defaults.Temperature)
+	assert.Equal(t, 1.0, envelope.Data.Ranges.Temperature.Max)
+	assert.Equal(t, 4096.0, envelope.Data.Ranges.MaxTokens.Max)
+	// Parameters no profile restricts keep the builtin range.
+	assert.Equal(t, *config.BuiltinModelProfile.Ranges.TopP, *envelope.Data.Ranges.TopP)
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodGet, ModelListPath+"/missing"+ModelParametersSuffix, nil)
+	app.ModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/missing" + ModelParametersSuffix}})
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestChatCompletionHandlerSamplingParamsValidation(t *testing.T) {
+	app := newTestApp()
+	app.config.ModelProfiles = newTestModelProfiles()
+
+	temperature := 1.5
+	maxTokens := 10000
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+		SamplingParams: &models.SamplingParams{Temperature: &temperature, MaxTokens: &maxTokens},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Equal(t, "must be between 0 and 1", fieldErrors["sampling_params.temperature"])
+	assert.Equal(t, "must be between 1 and 4096", fieldErrors["sampling_params.max_tokens"])
+}
+
+func TestConvertSamplingParams(t *testing.T) {
+	assert.Nil(t, convertSamplingParams(models.SamplingParams{}))
+
+	zero := 0.0
+	params := convertSamplingParams(models.SamplingParams{Temperature: &zero})
+	assert.Equal(t, llamastack.GreedySamplingStrategy, params.Strategy.Type)
+
+	temperature := 0.7
+	params = convertSamplingParams(models.SamplingParams{Temperature: &temperature})
+	assert.Equal(t, llamastack.TopPSamplingStrategy, params.Strategy.Type)
+	assert.Equal(t, temperature, *params.Strategy.Temperature)
+}
+
+func TestGetModelHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, ModelListPath+"/default-model-id-2", nil)
+	app.ModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/default-model-id-2"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope ModelEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "default-model-id-2", envelope.Data.Identifier)
+	assert.Equal(t, EmbeddingModelType, envelope.Data.ModelType)
+	assert.Equal(t, 384.0, envelope.Data.Metadata["embedding_dimension"])
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodGet, ModelListPath+"/meta-llama/missing", nil)
+	app.ModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/meta-llama/missing"}})
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetAllModelsHandlerFilters(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "", expected: []string{"default-model-id-1", "default-model-id-2", "default-model-id-3"}},
+		{query: "?model_type=llm", expected: []string{"default-model-id-1", "default-model-id-3"}},
+		{query: "?provider_id=default-provider-id-1", expected: []string{"default-model-id-1", "default-model-id-3"}},
+		{query: "?model_type=llm&search=RESOURCE-ID-3", expected: []string{"default-model-id-3"}},
+		{query: "?provider_id=default-provider-id-2&model_type=llm", expected: nil},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodGet, ModelListPath+tt.query, nil)
+		app.GetAllModelsHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code, tt.query)
+
+		var envelope ModelListEnvelope
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+		var identifiers []string
+		for _, model := range envelope.Data.Items {
+			identifiers = append(identifiers, model.Identifier)
+		}
+		assert.Equal(t, tt.expected, identifiers, tt.query)
+	}
+}
+
+func TestRegisterAndUnregisterModel(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ModelListPath, ModelRegistrationRequest{
+		ModelID:            "vllm/granite-3.3-8b",
+		ProviderID:         "vllm-inference",
+		ProviderResourceID: "ibm-granite/granite-3.3-8b-instruct",
+		Metadata:           map[string]any{"context_length": 8192},
+	})
+	app.RegisterModelHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var envelope ModelEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "vllm/granite-3.3-8b", envelope.Data.Identifier)
+	assert.Equal(t, LLMModelType, envelope.Data.ModelType)
+	assert.Equal(t, 8192.0, envelope.Data.Metadata["context_length"])
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodGet, ModelListPath+"/vllm/granite-3.3-8b", nil)
+	app.ModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/vllm/granite-3.3-8b"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	for _, expected := range []int{http.StatusNoContent, http.StatusNotFound} {
+		rr = httptest.NewRecorder()
+		req = newTestRequest(t, http.MethodDelete, ModelListPath+"/vllm/granite-3.3-8b", nil)
+		app.UnregisterModelHandler(rr, req, httprouter.Params{{Key: "model_path", Value: "/vllm/granite-3.3-8b"}})
+
+		assert.Equal(t, expected, rr.Code)
+	}
+}
+
+func TestRegisterModelValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ModelListPath, ModelRegistrationRequest{
+		ModelID:   "default-model-id-1",
+		ModelType: EmbeddingModelType,
+	})
+	app.RegisterModelHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Equal(t, map[string]string{
+		"model_id":                     `model "default-model-id-1" already exists`,
+		"provider_id":                  "must be provided",
+		"metadata.embedding_dimension": "must be a positive integer for embedding models",
+	}, fieldErrors)
+}
+
+func TestRegisterModelUpstreamError(t *testing.T) {
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"data": []}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"detail": "Provider vllm not found"}`)
+	}))
+	defer llamaStack.Close()
+
+	lsClient, err := repositories.NewLlamaStackClient()
+	assert.NoError(t, err)
+	app := newTestApp()
+	app.repositories = repositories.NewRepositories(lsClient)
+
+	client, err := integrations.NewHTTPClient(slog.Default(), llamaStack.URL)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ModelListPath, ModelRegistrationRequest{
+		ModelID:    "granite-3.3-8b",
+		ProviderID: "vllm",
+	})
+	app.RegisterModelHandler(rr, req.WithContext(context.WithValue(req.Context(), constants.LlamaStackHttpClientKey, client)), nil)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestModelManagementRequiresAdmin(t *testing.T) {
+	app := newTestApp()
+	app.config.AdminUsers = []string{"alice"}
+
+	tests := []struct {
+		oauthEnabled bool
+		userID       string
+		expected     int
+	}{
+		{oauthEnabled: true, userID: "alice", expected: http.StatusNoContent},
+		{oauthEnabled: true, userID: "bob", expected: http.StatusForbidden},
+		// Without OAuth everyone is the anonymous user.
+		{oauthEnabled: false, userID: "", expected: http.StatusNoContent},
+	}
+
+	handler := app.RequireAdminRoute(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, tt := range tests {
+		app.config.OAuthEnabled = tt.oauthEnabled
+
+		req := newTestRequest(t, http.MethodDelete, ModelListPath+"/granite-3.3-8b", nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, tt.userID))
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, nil)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.userID)
+	}
+}
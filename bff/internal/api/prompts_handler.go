@@ -0,0 +1,252 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+type PromptTemplateEnvelope Envelope[models.PromptTemplate, None]
+type PromptTemplateListEnvelope Envelope[models.PromptTemplateList, None]
+type PromptTemplateVersionListEnvelope Envelope[models.PromptTemplateVersionList, None]
+type RenderedPromptEnvelope Envelope[models.RenderedPrompt, None]
+
+// CreatePromptTemplateRequest represents the request body for adding a template to the library.
+// Placeholders are written as {{name}}.
+type CreatePromptTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Content     string `json:"content"`
+}
+
+// RenderPromptTemplateRequest represents the request body for rendering a template, a zero
+// version renders the latest one.
+type RenderPromptTemplateRequest struct {
+	Version   int               `json:"version,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// PromptTemplateSelection picks a library template as the system prompt of a chat request.
+type PromptTemplateSelection struct {
+	ID        string            `json:"id"`
+	Version   int               `json:"version,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+func (app *App) GetAllPromptTemplatesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	templateList, err := app.repositories.PromptTemplates.ListPromptTemplates()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, PromptTemplateListEnvelope{Data: templateList}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) CreatePromptTemplateHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var createRequest CreatePromptTemplateRequest
+	if err := app.ReadJSON(w, r, &createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	validationErrors := map[string]string{}
+	if strings.TrimSpace(createRequest.Name) == "" {
+		validationErrors["name"] = "must not be empty"
+	}
+	if strings.TrimSpace(createRequest.Content) == "" {
+		validationErrors["content"] = "must not be empty"
+	}
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	template, err := app.repositories.PromptTemplates.CreatePromptTemplate(requestUserID(r),
+		strings.TrimSpace(createRequest.Name), createRequest.Description, createRequest.Content)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, PromptTemplateEnvelope{Data: template}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) GetPromptTemplateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	template, err := app.repositories.PromptTemplates.GetPromptTemplate(ps.ByName("prompt_id"))
+	if err != nil {
+		app.promptTemplateErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, PromptTemplateEnvelope{Data: template}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) UpdatePromptTemplateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var update models.PromptTemplateUpdate
+	if err := app.ReadJSON(w, r, &update); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	validationErrors := map[string]string{}
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		validationErrors["name"] = "must not be empty"
+	}
+	if update.Content != nil && strings.TrimSpace(*update.Content) == "" {
+		validationErrors["content"] = "must not be empty"
+	}
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	template, err := app.repositories.PromptTemplates.UpdatePromptTemplate(requestUserID(r), ps.ByName("prompt_id"), update)
+	if err != nil {
+		app.promptTemplateErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, PromptTemplateEnvelope{Data: template}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) DeletePromptTemplateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	err := app.repositories.PromptTemplates.DeletePromptTemplate(requestUserID(r), ps.ByName("prompt_id"))
+	if err != nil {
+		app.promptTemplateErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *App) GetPromptTemplateVersionsHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	template, err := app.repositories.PromptTemplates.GetPromptTemplate(ps.ByName("prompt_id"))
+	if err != nil {
+		app.promptTemplateErrorResponse(w, r, err)
+		return
+	}
+
+	// Newest version first.
+	versions := slices.Clone(template.Versions)
+	slices.Reverse(versions)
+
+	err = app.WriteJSON(w, http.StatusOK, PromptTemplateVersionListEnvelope{
+		Data: models.PromptTemplateVersionList{Items: versions},
+	}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) RenderPromptTemplateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var renderRequest RenderPromptTemplateRequest
+	if err := app.ReadJSON(w, r, &renderRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	templateID := ps.ByName("prompt_id")
+	version, err := app.repositories.PromptTemplates.GetPromptTemplateVersion(templateID, renderRequest.Version)
+	if err != nil {
+		app.promptTemplateErrorResponse(w, r, err)
+		return
+	}
+
+	content, missing := repositories.RenderPrompt(version.Content, renderRequest.Variables)
+	if len(missing) > 0 {
+		app.failedValidationResponse(w, r, missingPromptVariableErrors("variables", missing))
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, RenderedPromptEnvelope{
+		Data: models.RenderedPrompt{
+			TemplateID: templateID,
+			Version:    version.Version,
+			Content:    content,
+		},
+	}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) promptTemplateErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, repositories.ErrPromptTemplateNotFound) || errors.Is(err, repositories.ErrPromptTemplateVersionNotFound) {
+		app.notFoundResponse(w, r)
+		return
+	}
+	if errors.Is(err, repositories.ErrPromptTemplateForbidden) {
+		app.forbiddenResponse(w, r, err.Error())
+		return
+	}
+	app.serverErrorResponse(w, r, err)
+}
+
+// validatePromptTemplateSelection adds the field errors of a chat request's template selection,
+// version is nil when the selected template or version does not exist.
+func validatePromptTemplateSelection(validationErrors map[string]string, request ChatCompletionRequest, version *models.PromptTemplateVersion) {
+	selection := request.PromptTemplate
+
+	switch {
+	case selection.ID == "":
+		validationErrors["prompt_template.id"] = "must be provided"
+		return
+	case version == nil && selection.Version != 0:
+		validationErrors["prompt_template.version"] = fmt.Sprintf("prompt template %q has no version %d", selection.ID, selection.Version)
+		return
+	case version == nil:
+		validationErrors["prompt_template.id"] = fmt.Sprintf("prompt template %q does not exist", selection.ID)
+		return
+	}
+
+	if _, missing := repositories.RenderPrompt(version.Content, selection.Variables); len(missing) > 0 {
+		for field, message := range missingPromptVariableErrors("prompt_template.variables", missing) {
+			validationErrors[field] = message
+		}
+	}
+
+	if len(request.Messages) > 0 && request.Messages[0].Role == llamastack.SystemRole {
+		validationErrors["messages[0].role"] = "must not be a system message when prompt_template is set"
+	}
+}
+
+// withPromptTemplate renders the selected template and prepends it as the system message.
+func withPromptTemplate(messages []llamastack.Message, selection PromptTemplateSelection, version models.PromptTemplateVersion) []llamastack.Message {
+	content, _ := repositories.RenderPrompt(version.Content, selection.Variables)
+
+	return append([]llamastack.Message{{Role: llamastack.SystemRole, Content: content}}, messages...)
+}
+
+func missingPromptVariableErrors(field string, missing []string) map[string]string {
+	validationErrors := map[string]string{}
+	for _, name := range missing {
+		validationErrors[field+"."+name] = "must be provided"
+	}
+	return validationErrors
+}
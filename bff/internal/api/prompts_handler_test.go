@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPromptTemplateHandler(t *testing.T) {
+	app := newTestApp()
+
+	template, err := app.repositories.PromptTemplates.CreatePromptTemplate("alice", "greeting", "", "You are a {{tone}} assistant.")
+	assert.NoError(t, err)
+	params := httprouter.Params{{Key: "prompt_id", Value: template.ID}}
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, PromptTemplateRenderPath, RenderPromptTemplateRequest{
+		Variables: map[string]string{"tone": "friendly"},
+	})
+	app.RenderPromptTemplateHandler(rr, req, params)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope RenderedPromptEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "You are a friendly assistant.", envelope.Data.Content)
+	assert.Equal(t, 1, envelope.Data.Version)
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodPost, PromptTemplateRenderPath, RenderPromptTemplateRequest{})
+	app.RenderPromptTemplateHandler(rr, req, params)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestChatCompletionHandlerPromptTemplateValidation(t *testing.T) {
+	app := newTestApp()
+
+	template, err := app.repositories.PromptTemplates.CreatePromptTemplate("alice", "greeting", "", "You are a {{tone}} assistant.")
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+		PromptTemplate: &PromptTemplateSelection{ID: template.ID},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "prompt_template.variables.tone")
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+		PromptTemplate: &PromptTemplateSelection{ID: template.ID, Variables: map[string]string{"tone": "calm"}},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPromptTemplateHandlersOnlyCreatorWrites(t *testing.T) {
+	app := newTestApp()
+
+	template, err := app.repositories.PromptTemplates.CreatePromptTemplate("alice", "greeting", "", "You are a {{tone}} assistant.")
+	assert.NoError(t, err)
+	params := httprouter.Params{{Key: "prompt_id", Value: template.ID}}
+
+	name := "renamed"
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPatch, PromptTemplatePath, map[string]any{"name": name})
+	req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, "bob"))
+	app.UpdatePromptTemplateHandler(rr, req, params)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodDelete, PromptTemplatePath, nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, "bob"))
+	app.DeletePromptTemplateHandler(rr, req, params)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodDelete, PromptTemplatePath, nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, "alice"))
+	app.DeletePromptTemplateHandler(rr, req, params)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type ProviderListEnvelope Envelope[models.ProviderList, None]
+
+// GetAllProvidersHandler lists the providers of the distribution, the api query parameter keeps
+// only the providers of that API, e.g. vector_io for the providers vector databases can be
+// registered with.
+func (app *App) GetAllProvidersHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	providerList, err := app.repositories.LlamaStackClient.GetAllProviders(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	result := ProviderListEnvelope{
+		Data: convertProviderList(providerList, r.URL.Query().Get("api")),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func convertProviderList(providerList *llamastack.ProviderList, api string) models.ProviderList {
+	items := []models.Provider{}
+
+	for _, provider := range providerList.Data {
+		if api != "" && provider.API != api {
+			continue
+		}
+		items = append(items, models.Provider{
+			API:          provider.API,
+			ProviderID:   provider.ProviderID,
+			ProviderType: provider.ProviderType,
+		})
+	}
+
+	return models.ProviderList{
+		Items: items,
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -31,8 +33,20 @@ func (app *App) HandleLlamaStackProxy(w http.ResponseWriter, r *http.Request) {
 		slog.String("original_path", r.URL.Path),
 		slog.String("proxy_url", proxyURL))
 
+	usage, body, err := app.observeProxyUsage(w, r, proxyPath)
+	if err != nil {
+		logger.Error("Failed to read proxy request body", slog.String("error", err.Error()))
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(w, fmt.Sprintf("Request body must not be larger than %d bytes", maxBytesError.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
 	// Create new request
-	req, err := http.NewRequest(r.Method, proxyURL, r.Body)
+	req, err := http.NewRequest(r.Method, proxyURL, body)
 	if err != nil {
 		logger.Error("Failed to create proxy request", slog.String("error", err.Error()))
 		http.Error(w, "Failed to create proxy request: "+err.Error(), http.StatusInternalServerError)
@@ -67,7 +81,18 @@ func (app *App) HandleLlamaStackProxy(w http.ResponseWriter, r *http.Request) {
 		w.Header()[k] = v
 	}
 	w.WriteHeader(resp.StatusCode)
-	if _, err := io.Copy(w, resp.Body); err != nil {
+
+	var responseBody io.Reader = resp.Body
+	if usage != nil && resp.StatusCode == http.StatusOK {
+		responseBody = usage.watch(resp)
+	}
+
+	if _, err := io.Copy(w, responseBody); err != nil {
 		logger.Error("Failed to copy response body", slog.String("error", err.Error()))
+		return
+	}
+
+	if usage != nil && resp.StatusCode == http.StatusOK {
+		usage.finish()
 	}
 }
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/auth"
+	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+// maxProxyUsageBuffer bounds what is kept in memory to find the usage of a proxied response,
+// a non streamed response or a single event line larger than this is not accounted.
+const maxProxyUsageBuffer = 4 * 1024 * 1024
+
+// The users of proxied tokens are cached briefly, the proxy would otherwise ask the user info
+// endpoint for every inference.
+const (
+	proxyTokenCacheTTL        = 5 * time.Minute
+	maxProxyTokenCacheEntries = 1000
+)
+
+// inferenceProxyPaths are the Llama Stack routes whose responses report token usage.
+var inferenceProxyPaths = []string{
+	"/v1/inference/chat-completion",
+	"/v1/inference/completion",
+	"/v1/openai/v1/chat/completions",
+	"/v1/openai/v1/completions",
+	"/v1/openai/v1/embeddings",
+}
+
+// proxyUsagePayload picks the usage out of Llama Stack responses, which report metrics, and
+// of the OpenAI compatible ones, which report usage.
+type proxyUsagePayload struct {
+	Metrics []llamastack.Metric `json:"metrics"`
+	Usage   *struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// proxyUsageObserver sees the response body of a proxied inference and records its usage
+// once the response is complete. Streamed responses are inspected line by line so only the
+// current event is buffered.
+type proxyUsageObserver struct {
+	app       *App
+	r         *http.Request
+	modelID   string
+	started   time.Time
+	streaming bool
+	buffer    bytes.Buffer
+	overflow  bool
+	found     bool
+
+	promptTokens     int64
+	completionTokens int64
+}
+
+// observeProxyUsage returns an observer when the proxied request is an inference, together
+// with the request body to forward since reading the model id consumes the original one. The
+// body is read up to the upload size limit, the request must not be proxied when it fails.
+func (app *App) observeProxyUsage(w http.ResponseWriter, r *http.Request, proxyPath string) (*proxyUsageObserver, io.Reader, error) {
+	if r.Method != http.MethodPost || !slices.Contains(inferenceProxyPaths, proxyPath) {
+		return nil, r.Body, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, app.maxUploadSize()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var request struct {
+		ModelID string `json:"model_id"`
+		Model   string `json:"model"`
+	}
+	_ = json.Unmarshal(body, &request)
+
+	modelID := request.ModelID
+	if modelID == "" {
+		modelID = request.Model
+	}
+
+	return &proxyUsageObserver{
+		app:     app,
+		r:       r,
+		modelID: modelID,
+		started: time.Now(),
+	}, bytes.NewReader(body), nil
+}
+
+// watch starts observing the body of a successful response.
+func (o *proxyUsageObserver) watch(resp *http.Response) io.Reader {
+	o.streaming = strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+	return io.TeeReader(resp.Body, o)
+}
+
+func (o *proxyUsageObserver) Write(p []byte) (int, error) {
+	if o.overflow {
+		return len(p), nil
+	}
+
+	o.buffer.Write(p)
+
+	if o.streaming {
+		for {
+			i := bytes.IndexByte(o.buffer.Bytes(), '\n')
+			if i < 0 {
+				break
+			}
+			o.observeLine(o.buffer.Next(i + 1))
+		}
+	}
+
+	if o.buffer.Len() > maxProxyUsageBuffer {
+		// A streamed response only loses the current event, a plain one cannot be parsed anymore.
+		o.overflow = !o.streaming
+		o.buffer.Reset()
+	}
+
+	return len(p), nil
+}
+
+func (o *proxyUsageObserver) observeLine(line []byte) {
+	if data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:")); ok {
+		o.observePayload(bytes.TrimSpace(data))
+	}
+}
+
+func (o *proxyUsageObserver) observePayload(data []byte) {
+	var payload proxyUsagePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return
+	}
+
+	switch {
+	case len(payload.Metrics) > 0:
+		o.promptTokens, o.completionTokens = tokensFromMetrics(payload.Metrics)
+		o.found = true
+	case payload.Usage != nil:
+		o.promptTokens = payload.Usage.PromptTokens
+		o.completionTokens = payload.Usage.CompletionTokens
+		o.found = true
+	}
+}
+
+// finish records the usage of the response, responses without any usage are skipped.
+func (o *proxyUsageObserver) finish() {
+	if o.streaming {
+		o.observeLine(o.buffer.Bytes())
+	} else if !o.overflow {
+		o.observePayload(o.buffer.Bytes())
+	}
+
+	if !o.found {
+		helper.GetContextLoggerFromReq(o.r).Debug("No token usage found in proxied inference response")
+		return
+	}
+
+	o.app.recordUsage(o.r, models.UsageRecord{
+		UserID:           o.app.proxyUserID(o.r),
+		ModelID:          o.modelID,
+		Source:           models.ProxyUsageSource,
+		PromptTokens:     o.promptTokens,
+		CompletionTokens: o.completionTokens,
+		LatencyMs:        time.Since(o.started).Milliseconds(),
+	})
+}
+
+// proxyUserID identifies the caller of the unprotected proxy for usage accounting, falling
+// back to the anonymous user when no valid token is sent.
+func (app *App) proxyUserID(r *http.Request) string {
+	if !app.config.OAuthEnabled {
+		return AnonymousUserID
+	}
+
+	token, err := auth.ExtractToken(r)
+	if err != nil {
+		return AnonymousUserID
+	}
+
+	userID, ok := app.proxyTokenCache.Get(token)
+	if !ok {
+		oauthHandler := auth.NewOAuthHandler(app.config, helper.GetContextLoggerFromReq(r))
+		userID, err = oauthHandler.ValidateToken(r.Context(), token)
+		if err != nil {
+			userID = ""
+		}
+		app.proxyTokenCache.Add(token, userID)
+	}
+
+	if userID == "" {
+		return AnonymousUserID
+	}
+	return userID
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type RAGQueryResultEnvelope Envelope[models.RAGQueryResult, None]
+
+// RAGQueryRequest represents the request body for querying vector databases
+type RAGQueryRequest struct {
+	Content            string   `json:"content"`
+	VectorDBIDs        []string `json:"vector_db_ids"`
+	MaxChunks          *int     `json:"max_chunks,omitempty"`
+	MaxTokensInContext *int     `json:"max_tokens_in_context,omitempty"`
+}
+
+func (app *App) QueryHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var queryRequest RAGQueryRequest
+	if err := app.ReadJSON(w, r, &queryRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// Validate required fields
+	if strings.TrimSpace(queryRequest.Content) == "" {
+		app.badRequestResponse(w, r, errors.New("content is required"))
+		return
+	}
+	if len(queryRequest.VectorDBIDs) == 0 {
+		app.badRequestResponse(w, r, errors.New("vector_db_ids are required"))
+		return
+	}
+	if queryRequest.MaxChunks != nil && *queryRequest.MaxChunks <= 0 {
+		app.badRequestResponse(w, r, errors.New("max_chunks must be greater than zero"))
+		return
+	}
+	if queryRequest.MaxTokensInContext != nil && *queryRequest.MaxTokensInContext <= 0 {
+		app.badRequestResponse(w, r, errors.New("max_tokens_in_context must be greater than zero"))
+		return
+	}
+
+	for _, vectorDBID := range queryRequest.VectorDBIDs {
+		exists, err := app.checkifVectorDBExists(client, vectorDBID)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		if !exists {
+			app.badRequestResponse(w, r, fmt.Errorf("vector database %q does not exist", vectorDBID))
+			return
+		}
+	}
+
+	result, err := app.repositories.LlamaStackClient.QueryDocuments(client, newRAGQueryRequest(queryRequest))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := RAGQueryResultEnvelope{
+		Data: convertRAGQueryResult(result),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, response, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func newRAGQueryRequest(queryRequest RAGQueryRequest) llamastack.RAGQueryRequest {
+	request := llamastack.RAGQueryRequest{
+		Content:     queryRequest.Content,
+		VectorDBIDs: queryRequest.VectorDBIDs,
+	}
+
+	if queryRequest.MaxChunks != nil || queryRequest.MaxTokensInContext != nil {
+		request.QueryConfig = &llamastack.RAGQueryConfig{}
+		if queryRequest.MaxChunks != nil {
+			request.QueryConfig.MaxChunks = *queryRequest.MaxChunks
+		}
+		if queryRequest.MaxTokensInContext != nil {
+			request.QueryConfig.MaxTokensInContext = *queryRequest.MaxTokensInContext
+		}
+	}
+
+	return request
+}
+
+func convertRAGQueryResult(result *llamastack.RAGQueryResult) models.RAGQueryResult {
+	chunks := []models.RAGChunk{}
+
+	// The metadata slices are index aligned, tolerate upstream versions that omit some of them.
+	for i, documentID := range result.Metadata.DocumentIDs {
+		chunk := models.RAGChunk{
+			DocumentID: documentID,
+		}
+		if i < len(result.Metadata.Chunks) {
+			chunk.Content = string(result.Metadata.Chunks[i])
+		}
+		if i < len(result.Metadata.Scores) {
+			chunk.Score = result.Metadata.Scores[i]
+		}
+		if i < len(result.Metadata.VectorDBIDs) {
+			chunk.Metadata = map[string]any{"vector_db_id": result.Metadata.VectorDBIDs[i]}
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return models.RAGQueryResult{
+		Content: string(result.Content),
+		Chunks:  chunks,
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandlerReturnsInsertedChunks(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.UploadHandler(rr, newTestRequest(t, http.MethodPost, UploadPath, UploadRequest{
+		Documents: []llamastack.Document{
+			{DocumentID: "doc-1", Content: "Llama Stack serves models"},
+			{DocumentID: "doc-2", Content: "Unrelated content"},
+		},
+		VectorDBID:     "query-test-db",
+		EmbeddingModel: "default-model-id-2",
+	}), nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	maxChunks := 1
+	app.QueryHandler(rr, newTestRequest(t, http.MethodPost, QueryPath, RAGQueryRequest{
+		Content:     "llama models",
+		VectorDBIDs: []string{"query-test-db"},
+		MaxChunks:   &maxChunks,
+	}), nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope RAGQueryResultEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	assert.Len(t, envelope.Data.Chunks, 1)
+	assert.Equal(t, "doc-1", envelope.Data.Chunks[0].DocumentID)
+	assert.Equal(t, 1.0, envelope.Data.Chunks[0].Score)
+	assert.Equal(t, "query-test-db", envelope.Data.Chunks[0].Metadata["vector_db_id"])
+	assert.Contains(t, envelope.Data.Content, "Llama Stack serves models")
+}
+
+func TestQueryHandlerUnknownVectorDB(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.QueryHandler(rr, newTestRequest(t, http.MethodPost, QueryPath, RAGQueryRequest{
+		Content:     "anything",
+		VectorDBIDs: []string{"missing-db"},
+	}), nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+// CitationsEventName names the event sent after a retrieval-augmented answer, clients that
+// ignore event names skip it as it carries no chat chunk.
+const CitationsEventName = "citations"
+
+type CitationsEvent struct {
+	Citations []models.RAGChunk `json:"citations"`
+}
+
+type ragPromptData struct {
+	Context string
+	Query   string
+}
+
+// parseRAGPromptTemplate parses tmpl, falling back to the default template when it is empty.
+func parseRAGPromptTemplate(tmpl string) (*template.Template, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = config.DefaultRAGPromptTemplate
+	}
+
+	parsed, err := template.New("rag-prompt").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Executing against sample data catches templates referencing unknown fields at startup.
+	if err := parsed.Execute(&strings.Builder{}, ragPromptData{}); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// augmentWithRetrievedContext queries the vector databases with the last message, which has to
+// be a user message, and rewrites it through the RAG prompt template. The retrieved chunks are
+// returned so they can be reported as citations.
+func (app *App) augmentWithRetrievedContext(client integrations.HTTPClientInterface, messages []llamastack.Message, vectorDBIDs []string) ([]llamastack.Message, []models.RAGChunk, error) {
+	last := messages[len(messages)-1]
+
+	result, err := app.repositories.LlamaStackClient.QueryDocuments(client, llamastack.RAGQueryRequest{
+		Content:     last.Content,
+		VectorDBIDs: vectorDBIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl := app.ragPromptTemplate
+	if tmpl == nil {
+		tmpl, err = parseRAGPromptTemplate(config.DefaultRAGPromptTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var prompt strings.Builder
+	if err := tmpl.Execute(&prompt, ragPromptData{Context: string(result.Content), Query: last.Content}); err != nil {
+		return nil, nil, fmt.Errorf("failed to render RAG prompt: %w", err)
+	}
+
+	// Copy so the caller's messages are left untouched.
+	augmented := append([]llamastack.Message{}, messages...)
+	augmented[len(augmented)-1].Content = prompt.String()
+
+	return augmented, convertRAGQueryResult(result).Chunks, nil
+}
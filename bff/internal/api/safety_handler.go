@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type ShieldListEnvelope Envelope[models.ShieldList, None]
+
+const (
+	InputShieldStage  = "input"
+	OutputShieldStage = "output"
+)
+
+func (app *App) GetAllShieldsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	shieldList, err := app.repositories.LlamaStackClient.GetAllShields(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	result := ShieldListEnvelope{
+		Data: convertShieldList(shieldList),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// runShields runs messages through every shield in order and returns the first blocking
+// violation. Violations below the error level are only logged.
+func (app *App) runShields(r *http.Request, client integrations.HTTPClientInterface, shieldIDs []string, messages []llamastack.Message, stage string) (*models.SafetyViolation, error) {
+	logger := helper.GetContextLoggerFromReq(r)
+
+	for _, shieldID := range shieldIDs {
+		response, err := app.repositories.LlamaStackClient.RunShield(client, llamastack.RunShieldRequest{
+			ShieldID: shieldID,
+			Messages: messages,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if response.Violation == nil {
+			continue
+		}
+
+		if response.Violation.ViolationLevel != llamastack.ErrorViolationLevel {
+			logger.Info("Shield reported a non blocking violation",
+				slog.String("shield_id", shieldID),
+				slog.String("stage", stage),
+				slog.String("level", response.Violation.ViolationLevel))
+			continue
+		}
+
+		return &models.SafetyViolation{
+			ShieldID: shieldID,
+			Stage:    stage,
+			Level:    response.Violation.ViolationLevel,
+			Message:  response.Violation.UserMessage,
+			Metadata: response.Violation.Metadata,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// validateShieldIDs adds a field error for every shield of shieldIDs missing from shieldList.
+func validateShieldIDs(validationErrors map[string]string, field string, shieldIDs []string, shieldList *llamastack.ShieldList) {
+	for i, shieldID := range shieldIDs {
+		found := false
+		if shieldList != nil {
+			for _, shield := range shieldList.Data {
+				if shield.Identifier == shieldID {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			validationErrors[fmt.Sprintf("%s[%d]", field, i)] = fmt.Sprintf("shield %q does not exist", shieldID)
+		}
+	}
+}
+
+func convertShieldList(shieldList *llamastack.ShieldList) models.ShieldList {
+	items := []models.Shield{}
+
+	for _, shield := range shieldList.Data {
+		items = append(items, models.Shield{
+			Identifier:         shield.Identifier,
+			ProviderID:         shield.ProviderID,
+			ProviderResourceID: shield.ProviderResourceID,
+		})
+	}
+
+	return models.ShieldList{
+		Items: items,
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatCompletionHandlerInputShieldViolation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "tell me something " + mocks.MockUnsafeKeyword},
+		},
+		InputShields: []string{"llama-guard"},
+	})
+
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var envelope SafetyViolationErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "llama-guard", envelope.Error.Violation.ShieldID)
+	assert.Equal(t, InputShieldStage, envelope.Error.Violation.Stage)
+	assert.Equal(t, llamastack.ErrorViolationLevel, envelope.Error.Violation.Level)
+	assert.NotEmpty(t, envelope.Error.Message)
+}
+
+func TestChatCompletionHandlerOutputShieldPasses(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+		OutputShields: []string{"llama-guard"},
+	})
+
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body, err := io.ReadAll(rr.Result().Body)
+	assert.NoError(t, err)
+
+	var text string
+	for _, event := range parseTestSSEEvents(string(body)) {
+		var chunk llamastack.ChatCompletionResponseStreamChunk
+		assert.NoError(t, json.Unmarshal([]byte(event.data), &chunk))
+		text += chunk.Event.Delta.Text
+	}
+	assert.Equal(t, "This is a mock response from default-model-id-1 to: hello there", text)
+}
+
+func TestChatCompletionHandlerUnknownShield(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+		OutputShields: []string{"missing-shield"},
+	})
+
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+// validateSamplingParams adds a field error for every parameter of params outside the range of
+// ranges, parameters without a range are accepted as is.
+func validateSamplingParams(validationErrors map[string]string, params models.SamplingParams, ranges models.SamplingParamRanges) {
+	checkRange := func(name string, value *float64, r *models.ParameterRange) {
+		if value == nil || r == nil {
+			return
+		}
+		if *value < r.Min || *value > r.Max {
+			validationErrors["sampling_params."+name] = fmt.Sprintf("must be between %g and %g", r.Min, r.Max)
+		}
+	}
+
+	checkRange("temperature", params.Temperature, ranges.Temperature)
+	checkRange("top_p", params.TopP, ranges.TopP)
+	checkRange("repetition_penalty", params.RepetitionPenalty, ranges.RepetitionPenalty)
+
+	if params.MaxTokens != nil {
+		maxTokens := float64(*params.MaxTokens)
+		checkRange("max_tokens", &maxTokens, ranges.MaxTokens)
+	}
+}
+
+// convertSamplingParams maps sampling params to the Llama Stack representation, nil when none
+// are set so Llama Stack applies its own defaults. A zero temperature selects greedy sampling.
+func convertSamplingParams(params models.SamplingParams) *llamastack.SamplingParams {
+	if params.IsEmpty() {
+		return nil
+	}
+
+	strategy := llamastack.SamplingStrategy{Type: llamastack.GreedySamplingStrategy}
+	if (params.Temperature != nil && *params.Temperature > 0) || params.TopP != nil {
+		strategy = llamastack.SamplingStrategy{
+			Type:        llamastack.TopPSamplingStrategy,
+			Temperature: params.Temperature,
+			TopP:        params.TopP,
+		}
+	}
+
+	return &llamastack.SamplingParams{
+		Strategy:          strategy,
+		MaxTokens:         params.MaxTokens,
+		RepetitionPenalty: params.RepetitionPenalty,
+	}
+}
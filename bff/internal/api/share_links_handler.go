@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+type ShareLinkEnvelope Envelope[models.ShareLink, None]
+type ShareLinkListEnvelope Envelope[models.ShareLinkList, None]
+type SharedChatbotEnvelope Envelope[models.SharedChatbot, None]
+
+// DefaultShareLinkTTL applies when a share link is created without an expiry, it is capped by
+// the configured maximum.
+const DefaultShareLinkTTL = 7 * 24 * time.Hour
+
+// shareLinkUserPrefix marks the user id share link chats are accounted to.
+const shareLinkUserPrefix = "share:"
+
+// CreateShareLinkRequest represents the request body for sharing a chatbot configuration
+type CreateShareLinkRequest struct {
+	models.ChatbotConfig
+	ExpiresInSeconds int64 `json:"expires_in_seconds,omitempty"`
+}
+
+// SharedChatCompletionRequest represents the request body of a share link chat, everything but
+// the conversation is fixed by the link.
+type SharedChatCompletionRequest struct {
+	Messages []llamastack.Message `json:"messages"`
+}
+
+func (app *App) CreateShareLinkHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var createRequest CreateShareLinkRequest
+	if err := app.ReadJSON(w, r, &createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	config := createRequest.ChatbotConfig
+	resources, err := app.loadChatRequestResources(client, ChatCompletionRequest{
+		ModelID:     config.ModelID,
+		VectorDBIDs: config.VectorDBIDs,
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	validationErrors := map[string]string{}
+	validateChatModelID(validationErrors, config.ModelID, resources.models)
+	validateVectorDBIDs(validationErrors, config.VectorDBIDs, resources.vectorDBs)
+	if config.SamplingParams != nil {
+		validateSamplingParams(validationErrors, *config.SamplingParams, app.config.ModelProfiles.ProfileFor(config.ModelID).Ranges)
+	}
+
+	maxTTL := app.config.ShareLinkMaxTTL
+	if maxTTL <= 0 {
+		maxTTL = DefaultShareLinkTTL
+	}
+	ttl := min(DefaultShareLinkTTL, maxTTL)
+	if createRequest.ExpiresInSeconds != 0 {
+		ttl = time.Duration(createRequest.ExpiresInSeconds) * time.Second
+		if ttl <= 0 || ttl > maxTTL {
+			validationErrors["expires_in_seconds"] = fmt.Sprintf("must be between 1 and %d", int64(maxTTL.Seconds()))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	link, err := app.repositories.ShareLinks.CreateShareLink(requestUserID(r), config, ttl)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, ShareLinkEnvelope{Data: link}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) GetAllShareLinksHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	linkList, err := app.repositories.ShareLinks.ListShareLinks(requestUserID(r))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, ShareLinkListEnvelope{Data: linkList}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetShareLinkHandler returns a share link of the user together with its audit.
+func (app *App) GetShareLinkHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	link, err := app.repositories.ShareLinks.GetShareLink(requestUserID(r), ps.ByName("share_link_id"))
+	if err != nil {
+		app.shareLinkErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, ShareLinkEnvelope{Data: link}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) RevokeShareLinkHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	_, err := app.repositories.ShareLinks.RevokeShareLink(requestUserID(r), ps.ByName("share_link_id"))
+	if err != nil {
+		app.shareLinkErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedChatbotHandler resolves a share token for the people it was shared with, it is not
+// authenticated since the signed token is the credential.
+func (app *App) GetSharedChatbotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	link, ok := app.resolveShareLink(w, r, ps.ByName("share_token"))
+	if !ok {
+		return
+	}
+
+	// Every resolve is audited, which rewrites the store, so they are limited like chats.
+	if retryAfter, err := app.repositories.ShareLinks.AllowShareLinkResolve(link.ID); err != nil {
+		app.rateLimitedResponse(w, r, err.Error(), retryAfter)
+		return
+	}
+
+	app.recordShareLinkUse(r, link.ID, models.ShareLinkResolvedEvent, "")
+
+	result := SharedChatbotEnvelope{
+		Data: models.SharedChatbot{
+			ModelID:        link.Config.ModelID,
+			VectorDBIDs:    link.Config.VectorDBIDs,
+			SamplingParams: link.Config.SamplingParams,
+			ExpiresAt:      link.ExpiresAt,
+		},
+	}
+
+	err := app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// SharedChatCompletionHandler streams a chat completion restricted to the configuration of a
+// share link, the caller only supplies the conversation.
+func (app *App) SharedChatCompletionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	link, ok := app.resolveShareLink(w, r, ps.ByName("share_token"))
+	if !ok {
+		return
+	}
+
+	// Rejected chats are not audited, a flood of them would push the useful entries out.
+	if retryAfter, err := app.repositories.ShareLinks.AllowShareLinkChat(link.ID); err != nil {
+		app.rateLimitedResponse(w, r, err.Error(), retryAfter)
+		return
+	}
+
+	var sharedRequest SharedChatCompletionRequest
+	if err := app.ReadJSON(w, r, &sharedRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// The system prompt belongs to the link, callers cannot replace it.
+	for i, message := range sharedRequest.Messages {
+		if message.Role == llamastack.SystemRole {
+			app.failedValidationResponse(w, r, map[string]string{
+				fmt.Sprintf("messages[%d].role", i): "system messages are not allowed on shared chatbots",
+			})
+			return
+		}
+	}
+
+	messages := sharedRequest.Messages
+	if link.Config.SystemPrompt != "" {
+		messages = append([]llamastack.Message{{Role: llamastack.SystemRole, Content: link.Config.SystemPrompt}}, messages...)
+	}
+
+	app.recordShareLinkUse(r, link.ID, models.ShareLinkChatEvent, "")
+
+	// Usage of share link chats is accounted to the link rather than to the anonymous user.
+	ctx := context.WithValue(r.Context(), constants.UserIDKey, shareLinkUserPrefix+link.ID)
+
+	app.serveChatCompletion(w, r.WithContext(ctx), client, ChatCompletionRequest{
+		ModelID:        link.Config.ModelID,
+		Messages:       messages,
+		VectorDBIDs:    link.Config.VectorDBIDs,
+		SamplingParams: link.Config.SamplingParams,
+	})
+}
+
+// resolveShareLink writes the error response and returns false when token does not grant access.
+// Uses of expired or revoked links are audited, once a minute per link.
+func (app *App) resolveShareLink(w http.ResponseWriter, r *http.Request, token string) (models.ShareLink, bool) {
+	link, linkID, err := app.repositories.ShareLinks.ResolveShareToken(token)
+
+	switch {
+	case err == nil:
+		return link, true
+	case errors.Is(err, repositories.ErrShareLinkExpired), errors.Is(err, repositories.ErrShareLinkRevoked):
+		app.recordShareLinkUse(r, linkID, models.ShareLinkRejectedEvent, err.Error())
+		app.forbiddenResponse(w, r, err.Error())
+	case errors.Is(err, repositories.ErrShareTokenInvalid):
+		app.notFoundResponse(w, r)
+	default:
+		app.serverErrorResponse(w, r, err)
+	}
+
+	return models.ShareLink{}, false
+}
+
+// recordShareLinkUse audits a use of a share link, failures are logged only.
+func (app *App) recordShareLinkUse(r *http.Request, linkID string, event string, detail string) {
+	entry := models.ShareLinkAuditEntry{
+		Event:      event,
+		RemoteAddr: app.clientAddress(r),
+		Detail:     detail,
+	}
+
+	if err := app.repositories.ShareLinks.RecordShareLinkUse(linkID, entry); err != nil {
+		app.LogError(r, fmt.Errorf("failed to audit share link use: %w", err))
+	}
+}
+
+func (app *App) shareLinkErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, repositories.ErrShareLinkNotFound) {
+		app.notFoundResponse(w, r)
+		return
+	}
+	app.serverErrorResponse(w, r, err)
+}
+
+// clientAddress returns the address of the client behind the trusted proxies. The hops of the
+// X-Forwarded-For header are only trusted when the request comes from a trusted proxy, the
+// rightmost hop which is not a trusted proxy is the client, anything before it can be forged.
+func (app *App) clientAddress(r *http.Request) string {
+	if !app.trustedProxy(r.RemoteAddr) {
+		return r.RemoteAddr
+	}
+
+	client := r.RemoteAddr
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			break
+		}
+		client = hop
+		if !app.trustedProxy(hop) {
+			break
+		}
+	}
+	return client
+}
+
+// trustedProxy reports whether address, with or without port, is one of the trusted proxies.
+func (app *App) trustedProxy(address string) bool {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return false
+		}
+		addr = addrPort.Addr()
+	}
+
+	for _, prefix := range app.config.TrustedProxies {
+		if prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
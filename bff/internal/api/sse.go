@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+// sseWriter writes server-sent events to the client and flushes after every event so they
+// reach the browser as soon as they are produced.
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// newSSEWriter sends the event stream headers, once called the response status can no longer
+// be changed and failures have to be reported as events.
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Stops reverse proxies such as the OpenShift router from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	rc := http.NewResponseController(w)
+	// Generations routinely outlive the server wide write timeout, not every writer supports
+	// deadlines (e.g. in tests) so the error is ignored.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.WriteHeader(http.StatusOK)
+
+	return &sseWriter{w: w, rc: rc}
+}
+
+// WriteData writes data as an unnamed event.
+func (s *sseWriter) WriteData(data any) error {
+	return s.WriteEvent("", data)
+}
+
+// WriteEvent writes data as JSON, named by event unless it is empty.
+func (s *sseWriter) WriteEvent(event string, data any) error {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", js); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
+
+// StreamErrorEvent mirrors the shape of a stream chunk so clients already parsing chat
+// streams can surface failures that happen after the response has started.
+type StreamErrorEvent struct {
+	Event StreamError `json:"event"`
+}
+
+type StreamError struct {
+	EventType string `json:"event_type"`
+	Error     string `json:"error"`
+}
+
+func newStreamErrorEvent(message string) StreamErrorEvent {
+	return StreamErrorEvent{
+		Event: StreamError{
+			EventType: "error",
+			Error:     message,
+		},
+	}
+}
+
+// relayEventStream forwards every event of stream to the client and closes the stream once it
+// is drained, failed, cancelled or the client went away. It reports whether the stream was
+// relayed completely.
+func relayEventStream[T any](app *App, r *http.Request, sse *sseWriter, stream repositories.EventStream[T]) bool {
+	logger := helper.GetContextLoggerFromReq(r)
+
+	defer func() {
+		if err := stream.Close(); err != nil {
+			logger.Warn("failed to close event stream", "error", err)
+		}
+	}()
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+
+		if err != nil {
+			if repositories.IsGenerationCancelled(r.Context()) {
+				logger.Debug("Generation cancelled by its owner")
+				app.writeGenerationCancelledEvent(r, sse)
+				return false
+			}
+			if r.Context().Err() != nil {
+				logger.Debug("Client disconnected from event stream")
+				return false
+			}
+
+			app.LogError(r, err)
+			if err := sse.WriteData(newStreamErrorEvent("the server encountered a problem and could not complete the response")); err != nil {
+				app.LogError(r, err)
+			}
+			return false
+		}
+
+		if err := sse.WriteData(event); err != nil {
+			app.LogError(r, fmt.Errorf("failed to write stream event: %w", err))
+			return false
+		}
+	}
+}
+
+// collectEventStream drains stream into memory and closes it.
+func collectEventStream[T any](stream repositories.EventStream[T]) ([]T, error) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	var events []T
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+}
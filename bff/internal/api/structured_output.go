@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+// StructuredOutputEventName names the event sent after an answer constrained by a response
+// format, once the answer was checked against the schema.
+const StructuredOutputEventName = "structured_output"
+
+// ResponseFormat asks for an answer in JSON matching JSONSchema, it is passed on to the structured
+// output support of the inference provider and the answer is validated by the BFF.
+type ResponseFormat struct {
+	// Only llamastack.JSONSchemaResponseFormatType is supported.
+	Type       string          `json:"type"`
+	JSONSchema json.RawMessage `json:"json_schema"`
+}
+
+// StructuredOutputEvent carries either the parsed answer or why it does not match the schema.
+type StructuredOutputEvent struct {
+	Output json.RawMessage        `json:"output,omitempty"`
+	Error  *StructuredOutputError `json:"error,omitempty"`
+}
+
+type StructuredOutputError struct {
+	Message string `json:"message"`
+	// The schema violations, empty when the answer is not JSON at all.
+	Errors []SchemaValidationError `json:"errors,omitempty"`
+}
+
+func validateResponseFormat(validationErrors map[string]string, format *ResponseFormat) {
+	if format.Type != llamastack.JSONSchemaResponseFormatType {
+		validationErrors["response_format.type"] = fmt.Sprintf("must be %s", llamastack.JSONSchemaResponseFormatType)
+	}
+	if len(format.JSONSchema) == 0 {
+		validationErrors["response_format.json_schema"] = "must be provided"
+		return
+	}
+	if _, err := parseJSONSchema(format.JSONSchema); err != nil {
+		validationErrors["response_format.json_schema"] = err.Error()
+	}
+}
+
+func convertResponseFormat(format *ResponseFormat) *llamastack.ResponseFormat {
+	if format == nil {
+		return nil
+	}
+	return &llamastack.ResponseFormat{
+		Type:       format.Type,
+		JSONSchema: format.JSONSchema,
+	}
+}
+
+// checkStructuredOutput parses answer and validates it against the schema of format.
+func checkStructuredOutput(format *ResponseFormat, answer string) StructuredOutputEvent {
+	// The request was validated, the schema parses.
+	schema, _ := parseJSONSchema(format.JSONSchema)
+
+	answer = strings.TrimSpace(answer)
+	// Models without native structured output tend to wrap JSON in a Markdown code block.
+	if fenced, ok := strings.CutPrefix(answer, "```"); ok {
+		if newline := strings.IndexByte(fenced, '\n'); newline >= 0 {
+			fenced = fenced[newline+1:]
+		}
+		answer = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fenced), "```"))
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(answer), &value); err != nil {
+		return StructuredOutputEvent{Error: &StructuredOutputError{
+			Message: fmt.Sprintf("the answer is not valid JSON: %s", err),
+		}}
+	}
+
+	if errs := schema.Validate(value); len(errs) > 0 {
+		return StructuredOutputEvent{Error: &StructuredOutputError{
+			Message: "the answer does not match the schema",
+			Errors:  errs,
+		}}
+	}
+
+	return StructuredOutputEvent{Output: json.RawMessage(answer)}
+}
+
+// answerRecordingStream keeps the text of the chunks read from the stream it wraps.
+type answerRecordingStream struct {
+	repositories.ChatCompletionStream
+	answer strings.Builder
+}
+
+func (s *answerRecordingStream) Recv() (*llamastack.ChatCompletionResponseStreamChunk, error) {
+	chunk, err := s.ChatCompletionStream.Recv()
+	if chunk != nil && chunk.Event.Delta.Type == "text" {
+		s.answer.WriteString(chunk.Event.Delta.Text)
+	}
+	return chunk, err
+}
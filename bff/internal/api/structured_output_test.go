@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+const testInvoiceSchema = `{
+	"type": "object",
+	"properties": {
+		"vendor": {"type": "string", "minLength": 1},
+		"total": {"type": "number", "minimum": 0},
+		"currency": {"enum": ["EUR", "USD"]},
+		"lines": {"type": "array", "items": {"$ref": "#/$defs/line"}}
+	},
+	"required": ["vendor", "total"],
+	"additionalProperties": false,
+	"$defs": {
+		"line": {"type": "object", "properties": {"quantity": {"type": "integer"}}, "required": ["quantity"]}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema, err := parseJSONSchema(json.RawMessage(testInvoiceSchema))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		value  string
+		errors []SchemaValidationError
+	}{
+		{value: `{"vendor": "ACME", "total": 12.5, "currency": "EUR", "lines": [{"quantity": 2}]}`},
+		{value: `[]`, errors: []SchemaValidationError{{Path: "$", Message: "must be of type object, got array"}}},
+		{
+			value: `{"vendor": "", "total": -1, "currency": "GBP", "lines": [{"quantity": 1.5}, {}], "notes": "x"}`,
+			errors: []SchemaValidationError{
+				{Path: "$.currency", Message: `must be one of ["EUR","USD"]`},
+				{Path: "$.lines[0].quantity", Message: "must be of type integer, got number"},
+				{Path: "$.lines[1].quantity", Message: "is required"},
+				{Path: "$.notes", Message: "is not an allowed property"},
+				{Path: "$.total", Message: "must be greater than or equal to 0"},
+				{Path: "$.vendor", Message: "must be at least 1 characters long"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var value any
+		assert.NoError(t, json.Unmarshal([]byte(tt.value), &value))
+		assert.Equal(t, tt.errors, schema.Validate(value), tt.value)
+	}
+}
+
+func TestParseJSONSchemaInvalid(t *testing.T) {
+	for _, raw := range []string{
+		`[]`,
+		`{"type": "decimal"}`,
+		`{"properties": {"a": {"$ref": "#/$defs/missing"}}}`,
+		`{"type": "string", "pattern": "(?<=a)b"}`,
+	} {
+		_, err := parseJSONSchema(json.RawMessage(raw))
+		assert.Error(t, err, raw)
+	}
+}
+
+func structuredOutputEvent(t *testing.T, body string) StructuredOutputEvent {
+	events := parseTestSSEEvents(body)
+	last := events[len(events)-1]
+	assert.Equal(t, StructuredOutputEventName, last.name)
+
+	var event StructuredOutputEvent
+	assert.NoError(t, json.Unmarshal([]byte(last.data), &event))
+	return event
+}
+
+func TestChatCompletionHandlerStructuredOutput(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:        "default-model-id-1",
+		Messages:       []llamastack.Message{{Role: llamastack.UserRole, Content: "Extract the invoice"}},
+		ResponseFormat: &ResponseFormat{Type: llamastack.JSONSchemaResponseFormatType, JSONSchema: json.RawMessage(testInvoiceSchema)},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	event := structuredOutputEvent(t, rr.Body.String())
+	assert.Nil(t, event.Error)
+	assert.JSONEq(t, `{"vendor": "mock", "total": 0, "currency": "EUR", "lines": [{"quantity": 1}]}`, string(event.Output))
+}
+
+func TestChatCompletionHandlerStructuredOutputViolation(t *testing.T) {
+	app := newTestApp()
+
+	// The mock answers with placeholders which do not match the pattern.
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:  "default-model-id-1",
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "Extract the order number"}},
+		ResponseFormat: &ResponseFormat{
+			Type:       llamastack.JSONSchemaResponseFormatType,
+			JSONSchema: json.RawMessage(`{"type": "object", "properties": {"order": {"type": "string", "pattern": "^[0-9]+$"}}}`),
+		},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	event := structuredOutputEvent(t, rr.Body.String())
+	assert.Empty(t, event.Output)
+	assert.NotNil(t, event.Error)
+	assert.Equal(t, []SchemaValidationError{{Path: "$.order", Message: "must match the pattern ^[0-9]+$"}}, event.Error.Errors)
+}
+
+func TestChatCompletionHandlerInvalidResponseFormat(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:        "default-model-id-1",
+		Messages:       []llamastack.Message{{Role: llamastack.UserRole, Content: "hello"}},
+		ResponseFormat: &ResponseFormat{Type: "grammar", JSONSchema: json.RawMessage(`{"type": 1}`)},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "response_format.type")
+	assert.Contains(t, fieldErrors, "response_format.json_schema")
+}
+
+func TestChatCompletionHandlerStructuredOutputHugeBounds(t *testing.T) {
+	app := newTestApp()
+
+	// The mock caps the bounds it satisfies instead of allocating a billion items.
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID:  "default-model-id-1",
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "List the tags"}},
+		ResponseFormat: &ResponseFormat{
+			Type:       llamastack.JSONSchemaResponseFormatType,
+			JSONSchema: json.RawMessage(`{"type": "array", "minItems": 1e9, "items": {"type": "string", "minLength": 1e9}}`),
+		},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	event := structuredOutputEvent(t, rr.Body.String())
+	assert.NotNil(t, event.Error)
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type ToolGroupEnvelope Envelope[models.ToolGroup, None]
+type ToolGroupListEnvelope Envelope[models.ToolGroupList, None]
+type ToolListEnvelope Envelope[models.ToolList, None]
+
+// MCPProviderID is the tool runtime provider serving Model Context Protocol tool groups.
+const MCPProviderID = "model-context-protocol"
+
+// ToolGroupRegistrationRequest represents the request body for registering a tool group
+type ToolGroupRegistrationRequest struct {
+	ToolGroupID string         `json:"toolgroup_id"`
+	ProviderID  string         `json:"provider_id"`
+	MCPEndpoint string         `json:"mcp_endpoint,omitempty"`
+	Args        map[string]any `json:"args,omitempty"`
+}
+
+func (app *App) GetAllToolGroupsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	toolGroupList, err := app.repositories.LlamaStackClient.GetAllToolGroups(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	result := ToolGroupListEnvelope{
+		Data: convertToolGroupList(toolGroupList),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) GetAllToolsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	toolList, err := app.repositories.LlamaStackClient.GetAllTools(client, r.URL.Query().Get("toolgroup_id"))
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	result := ToolListEnvelope{
+		Data: convertToolList(toolList),
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, result, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) RegisterToolGroupHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	var requestBody ToolGroupRegistrationRequest
+	if err := app.ReadJSON(w, r, &requestBody); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// Validate required fields
+	if requestBody.ToolGroupID == "" {
+		app.badRequestResponse(w, r, errors.New("toolgroup_id is required"))
+		return
+	}
+	if requestBody.ProviderID == "" {
+		app.badRequestResponse(w, r, errors.New("provider_id is required"))
+		return
+	}
+	if requestBody.ProviderID == MCPProviderID && requestBody.MCPEndpoint == "" {
+		app.badRequestResponse(w, r, errors.New("mcp_endpoint is required for model context protocol tool groups"))
+		return
+	}
+
+	registrationRequest := llamastack.ToolGroupRegistrationRequest{
+		ToolGroupID: requestBody.ToolGroupID,
+		ProviderID:  requestBody.ProviderID,
+		Args:        requestBody.Args,
+	}
+
+	if requestBody.MCPEndpoint != "" {
+		endpoint, err := url.Parse(requestBody.MCPEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			app.badRequestResponse(w, r, errors.New("mcp_endpoint must be an absolute http(s) URL"))
+			return
+		}
+		registrationRequest.MCPEndpoint = &llamastack.MCPEndpoint{URI: requestBody.MCPEndpoint}
+	}
+
+	err := app.repositories.LlamaStackClient.RegisterToolGroup(client, registrationRequest)
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	toolGroup := models.ToolGroup{
+		Identifier:         requestBody.ToolGroupID,
+		ProviderID:         requestBody.ProviderID,
+		ProviderResourceID: requestBody.ToolGroupID,
+		MCPEndpoint:        requestBody.MCPEndpoint,
+		Args:               requestBody.Args,
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, ToolGroupEnvelope{Data: toolGroup}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func convertToolGroup(toolGroup *llamastack.ToolGroup) models.ToolGroup {
+	result := models.ToolGroup{
+		Identifier:         toolGroup.Identifier,
+		ProviderID:         toolGroup.ProviderID,
+		ProviderResourceID: toolGroup.ProviderResourceID,
+		Args:               toolGroup.Args,
+	}
+	if toolGroup.MCPEndpoint != nil {
+		result.MCPEndpoint = toolGroup.MCPEndpoint.URI
+	}
+	return result
+}
+
+func convertToolGroupList(toolGroupList *llamastack.ToolGroupList) models.ToolGroupList {
+	items := []models.ToolGroup{}
+
+	for _, toolGroup := range toolGroupList.Data {
+		items = append(items, convertToolGroup(&toolGroup))
+	}
+
+	return models.ToolGroupList{
+		Items: items,
+	}
+}
+
+func convertTool(tool *llamastack.Tool) models.Tool {
+	parameters := []models.ToolParameter{}
+	for _, parameter := range tool.Parameters {
+		parameters = append(parameters, models.ToolParameter{
+			Name:          parameter.Name,
+			ParameterType: parameter.ParameterType,
+			Description:   parameter.Description,
+			Required:      parameter.Required,
+			Default:       parameter.Default,
+		})
+	}
+
+	return models.Tool{
+		Identifier:  tool.Identifier,
+		ToolGroupID: tool.ToolGroupID,
+		ProviderID:  tool.ProviderID,
+		Description: tool.Description,
+		Parameters:  parameters,
+	}
+}
+
+func convertToolList(toolList *llamastack.ToolList) models.ToolList {
+	items := []models.Tool{}
+
+	for _, tool := range toolList.Data {
+		items = append(items, convertTool(&tool))
+	}
+
+	return models.ToolList{
+		Items: items,
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllToolsHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.GetAllToolsHandler(rr, newTestRequest(t, http.MethodGet, ToolListPath, nil), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope ToolListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Len(t, envelope.Data.Items, 2)
+
+	rr = httptest.NewRecorder()
+	app.GetAllToolsHandler(rr, newTestRequest(t, http.MethodGet, ToolListPath+"?toolgroup_id=builtin::rag", nil), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Len(t, envelope.Data.Items, 1)
+	assert.Equal(t, "knowledge_search", envelope.Data.Items[0].Identifier)
+	assert.Equal(t, "query", envelope.Data.Items[0].Parameters[0].Name)
+	assert.True(t, envelope.Data.Items[0].Parameters[0].Required)
+
+	rr = httptest.NewRecorder()
+	app.GetAllToolsHandler(rr, newTestRequest(t, http.MethodGet, ToolListPath+"?toolgroup_id=unknown", nil), nil)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestRegisterToolGroupHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ToolGroupListPath, ToolGroupRegistrationRequest{
+		ToolGroupID: "mcp::docs",
+		ProviderID:  MCPProviderID,
+		MCPEndpoint: "http://localhost:8000/sse",
+	})
+	app.RegisterToolGroupHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	app.GetAllToolGroupsHandler(rr, newTestRequest(t, http.MethodGet, ToolGroupListPath, nil), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope ToolGroupListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Len(t, envelope.Data.Items, 3)
+	assert.Equal(t, "mcp::docs", envelope.Data.Items[2].Identifier)
+	assert.Equal(t, "http://localhost:8000/sse", envelope.Data.Items[2].MCPEndpoint)
+
+	// Registered tool groups have no tools in the mock.
+	rr = httptest.NewRecorder()
+	app.GetAllToolsHandler(rr, newTestRequest(t, http.MethodGet, ToolListPath+"?toolgroup_id=mcp::docs", nil), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var toolEnvelope ToolListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&toolEnvelope))
+	assert.Empty(t, toolEnvelope.Data.Items)
+}
+
+func TestRegisterToolGroupHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		name    string
+		request ToolGroupRegistrationRequest
+	}{
+		{name: "missing id", request: ToolGroupRegistrationRequest{ProviderID: "tavily-search"}},
+		{name: "missing provider", request: ToolGroupRegistrationRequest{ToolGroupID: "search"}},
+		{name: "MCP without endpoint", request: ToolGroupRegistrationRequest{ToolGroupID: "mcp::docs", ProviderID: MCPProviderID}},
+		{name: "relative endpoint", request: ToolGroupRegistrationRequest{ToolGroupID: "mcp::docs", ProviderID: MCPProviderID, MCPEndpoint: "/sse"}},
+		{name: "non HTTP endpoint", request: ToolGroupRegistrationRequest{ToolGroupID: "mcp::docs", ProviderID: MCPProviderID, MCPEndpoint: "ftp://localhost/sse"}},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		app.RegisterToolGroupHandler(rr, newTestRequest(t, http.MethodPost, ToolGroupListPath, tt.request), nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, tt.name)
+	}
+
+	rr := httptest.NewRecorder()
+	app.GetAllToolGroupsHandler(rr, newTestRequest(t, http.MethodGet, ToolGroupListPath, nil), nil)
+
+	var envelope ToolGroupListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Len(t, envelope.Data.Items, 2)
+}
+
+func TestRegisterToolGroupRequiresAdmin(t *testing.T) {
+	// The user info of a token names the user the token belongs to.
+	userInfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"preferred_username":%q}`, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer userInfo.Close()
+
+	app := newTestApp()
+	app.config.OAuthEnabled = true
+	app.config.OAuthUserInfoEndpoint = userInfo.URL
+	app.config.AdminUsers = []string{"alice"}
+	routes := app.Routes()
+
+	for _, tt := range []struct {
+		userID   string
+		expected int
+	}{
+		{userID: "bob", expected: http.StatusForbidden},
+		{userID: "alice", expected: http.StatusCreated},
+	} {
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodPost, ToolGroupListPath, ToolGroupRegistrationRequest{
+			ToolGroupID: "mcp::internal",
+			ProviderID:  MCPProviderID,
+			MCPEndpoint: "http://10.0.0.1:8000/sse",
+		})
+		req.Header.Set("Authorization", "Bearer "+tt.userID)
+		routes.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.userID)
+	}
+}
+
+func TestRegisterToolGroupUpstreamError(t *testing.T) {
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"detail": "Tool group builtin::rag already exists"}`)
+	}))
+	defer llamaStack.Close()
+
+	lsClient, err := repositories.NewLlamaStackClient()
+	assert.NoError(t, err)
+	app := newTestApp()
+	app.repositories = repositories.NewRepositories(lsClient)
+
+	client, err := integrations.NewHTTPClient(slog.Default(), llamaStack.URL)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ToolGroupListPath, ToolGroupRegistrationRequest{
+		ToolGroupID: "builtin::rag",
+		ProviderID:  "rag-runtime",
+	})
+	app.RegisterToolGroupHandler(rr, req.WithContext(context.WithValue(req.Context(), constants.LlamaStackHttpClientKey, client)), nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "Tool group builtin::rag already exists", envelope.Error.Message)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/chunking"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+// validateChunkingOptions checks the chunking fields of request, errors are keyed by field name.
+func validateChunkingOptions(request UploadRequest) map[string]string {
+	validationErrors := map[string]string{}
+
+	if request.ChunkSizeInTokens != nil && *request.ChunkSizeInTokens < 1 {
+		validationErrors["chunk_size_in_tokens"] = "must be a positive integer"
+	}
+
+	if request.ChunkingStrategy != "" && !slices.Contains(chunking.Strategies, request.ChunkingStrategy) {
+		validationErrors["chunking_strategy"] = "must be one of " + strings.Join(chunking.Strategies, ", ")
+	}
+
+	if overlap := request.ChunkOverlapInTokens; overlap != nil {
+		options := request.chunkingOptions()
+		switch {
+		case request.ChunkingStrategy == "":
+			validationErrors["chunk_overlap_in_tokens"] = "requires chunking_strategy, Llama Stack picks the overlap itself"
+		case *overlap < 0 || *overlap > options.ChunkSizeInTokens/2:
+			validationErrors["chunk_overlap_in_tokens"] = fmt.Sprintf("must be between 0 and half of the chunk size, %d", options.ChunkSizeInTokens/2)
+		}
+	}
+
+	return validationErrors
+}
+
+// chunkingOptions returns the chunking options of request. The overlap defaults to a quarter of
+// the chunk size for the fixed strategy, as in Llama Stack, and to none for the other strategies
+// as they cut at natural boundaries.
+func (request UploadRequest) chunkingOptions() chunking.Options {
+	options := chunking.Options{
+		Strategy:          request.ChunkingStrategy,
+		ChunkSizeInTokens: chunking.DefaultChunkSizeInTokens,
+	}
+	if request.ChunkSizeInTokens != nil {
+		options.ChunkSizeInTokens = *request.ChunkSizeInTokens
+	}
+
+	switch {
+	case request.ChunkOverlapInTokens != nil:
+		options.ChunkOverlapInTokens = *request.ChunkOverlapInTokens
+	case options.Strategy == chunking.FixedStrategy:
+		options.ChunkOverlapInTokens = options.ChunkSizeInTokens / 4
+	}
+	return options
+}
+
+// chunkDocument cuts document into the chunks inserted into the vector database, the metadata of
+// the document is copied to every chunk along with its position.
+func chunkDocument(document llamastack.Document, options chunking.Options) []llamastack.Chunk {
+	var chunks []llamastack.Chunk
+
+	for _, chunk := range chunking.Split(document.Content, options) {
+		chunks = append(chunks, llamastack.Chunk{Content: chunk.Content, Metadata: chunkMetadata(document, chunk, options.Strategy)})
+	}
+
+	return chunks
+}
+
+// chunkMetadata returns the metadata stored with chunk, the metadata of document extended with
+// the position of the chunk.
+func chunkMetadata(document llamastack.Document, chunk chunking.Chunk, strategy string) map[string]any {
+	metadata := maps.Clone(document.Metadata)
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["document_id"] = document.DocumentID
+	metadata["token_count"] = chunk.TokenCount
+	metadata["chunk_index"] = chunk.Index
+	metadata["start_offset"] = chunk.StartOffset
+	metadata["end_offset"] = chunk.EndOffset
+	metadata["start_line"] = chunk.StartLine
+	metadata["end_line"] = chunk.EndLine
+	metadata["chunking_strategy"] = strategy
+	if chunk.Heading != "" {
+		metadata["heading"] = chunk.Heading
+	}
+	return metadata
+}
+
+// insertChunkedDocuments chunks the documents of request in the BFF and inserts the chunks,
+// a request per document keeps requests small.
+func (app *App) insertChunkedDocuments(client integrations.HTTPClientInterface, request UploadRequest) error {
+	options := request.chunkingOptions()
+
+	for _, document := range request.Documents {
+		chunks := chunkDocument(document, options)
+		if len(chunks) == 0 {
+			continue
+		}
+
+		err := app.repositories.LlamaStackClient.InsertChunks(client, llamastack.VectorIOInsertRequest{
+			VectorDBID: request.VectorDBID,
+			Chunks:     chunks,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert chunks of document %s: %w", document.DocumentID, err)
+		}
+	}
+
+	return nil
+}
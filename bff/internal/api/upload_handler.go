@@ -17,6 +17,11 @@ type UploadRequest struct {
 	VectorDBID        string                `json:"vector_db_id"`
 	ChunkSizeInTokens *int                  `json:"chunk_size_in_tokens,omitempty"`
 	EmbeddingModel    string                `json:"embedding_model"`
+	// ChunkingStrategy has the BFF chunk the documents with one of chunking.Strategies, Llama
+	// Stack chunks them when it is empty.
+	ChunkingStrategy string `json:"chunking_strategy,omitempty"`
+	// Only used with ChunkingStrategy, see chunkingOptions for the default.
+	ChunkOverlapInTokens *int `json:"chunk_overlap_in_tokens,omitempty"`
 }
 
 func (app *App) UploadHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
@@ -27,9 +32,21 @@ func (app *App) UploadHandler(w http.ResponseWriter, r *http.Request, params htt
 		return
 	}
 
-	// Parse the request body
+	// Parse the request body, multipart forms carry files whose text is extracted here
 	var uploadRequest UploadRequest
-	if err := json.NewDecoder(r.Body).Decode(&uploadRequest); err != nil {
+	if isMultipartRequest(r) {
+		var validationErrors map[string]string
+		var err error
+		uploadRequest, validationErrors, err = app.readMultipartUpload(w, r)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+		if len(validationErrors) > 0 {
+			app.failedValidationResponse(w, r, validationErrors)
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&uploadRequest); err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
@@ -47,6 +64,10 @@ func (app *App) UploadHandler(w http.ResponseWriter, r *http.Request, params htt
 		app.badRequestResponse(w, r, errors.New("embedding_model is required"))
 		return
 	}
+	if validationErrors := validateChunkingOptions(uploadRequest); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
 
 	// Check if vector database exists
 	exists, err := app.checkifVectorDBExists(client, uploadRequest.VectorDBID)
@@ -74,15 +95,19 @@ func (app *App) UploadHandler(w http.ResponseWriter, r *http.Request, params htt
 		app.logger.Info("Vector database already exists", "vector_db_id", uploadRequest.VectorDBID)
 	}
 
-	// Create the document insert request
-	documentInsertRequest := llamastack.DocumentInsertRequest{
-		Documents:         uploadRequest.Documents,
-		VectorDBID:        uploadRequest.VectorDBID,
-		ChunkSizeInTokens: uploadRequest.ChunkSizeInTokens,
-	}
+	if uploadRequest.ChunkingStrategy != "" {
+		err = app.insertChunkedDocuments(client, uploadRequest)
+	} else {
+		// Create the document insert request
+		documentInsertRequest := llamastack.DocumentInsertRequest{
+			Documents:         uploadRequest.Documents,
+			VectorDBID:        uploadRequest.VectorDBID,
+			ChunkSizeInTokens: uploadRequest.ChunkSizeInTokens,
+		}
 
-	// Insert documents
-	err = app.repositories.LlamaStackClient.InsertDocuments(client, documentInsertRequest)
+		// Insert documents
+		err = app.repositories.LlamaStackClient.InsertDocuments(client, documentInsertRequest)
+	}
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
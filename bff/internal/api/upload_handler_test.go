@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+type testUploadFile struct {
+	filename string
+	content  string
+}
+
+func newTestMultipartUploadRequest(t *testing.T, fields map[string]string, files []testUploadFile) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		assert.NoError(t, writer.WriteField(name, value))
+	}
+	for _, file := range files {
+		part, err := writer.CreateFormFile(UploadFilesField, file.filename)
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(file.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	req := newTestRequest(t, http.MethodPost, UploadPath, nil)
+	req.Body = io.NopCloser(&body)
+	req.ContentLength = int64(body.Len())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadHandlerMultipart(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestMultipartUploadRequest(t, map[string]string{
+		"vector_db_id":         "default-vector-db-id-1",
+		"embedding_model":      "default-model-id-2",
+		"chunk_size_in_tokens": "256",
+	}, []testUploadFile{
+		{filename: "guide.html", content: "<h1>Setup</h1><p>Install the zebra operator.</p>"},
+		{filename: "guide.html", content: "<p>Upgrade the zebra operator.</p>"},
+	})
+	app.UploadHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	result, err := app.repositories.LlamaStackClient.QueryDocuments(nil, llamastack.RAGQueryRequest{
+		Content:     "zebra operator",
+		VectorDBIDs: []string{"default-vector-db-id-1"},
+	})
+	assert.NoError(t, err)
+	// Duplicate filenames get distinct document ids.
+	assert.ElementsMatch(t, []string{"guide.html", "guide.html (2)"}, result.Metadata.DocumentIDs)
+	assert.Contains(t, result.Metadata.Chunks, llamastack.TextContent("# Setup\n\nInstall the zebra operator."))
+}
+
+func TestUploadHandlerMultipartValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestMultipartUploadRequest(t, map[string]string{
+		"vector_db_id":         "default-vector-db-id-1",
+		"embedding_model":      "default-model-id-2",
+		"chunk_size_in_tokens": "0",
+	}, []testUploadFile{
+		{filename: "notes.md", content: "# Notes"},
+		{filename: "photo.png", content: "\x89PNG\r\n\x1a\n"},
+		{filename: "empty.txt", content: "  \n"},
+	})
+	app.UploadHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Len(t, fieldErrors, 3)
+	assert.Contains(t, fieldErrors, "chunk_size_in_tokens")
+	assert.Contains(t, fieldErrors["files[1]"], "unsupported file type")
+	assert.Contains(t, fieldErrors["files[2]"], "empty.txt")
+}
+
+func TestUploadHandlerMultipartTooLarge(t *testing.T) {
+	app := newTestApp()
+	app.config.MaxUploadSize = 1024
+
+	rr := httptest.NewRecorder()
+	req := newTestMultipartUploadRequest(t, map[string]string{"vector_db_id": "default-vector-db-id-1"}, []testUploadFile{
+		{filename: "large.txt", content: string(bytes.Repeat([]byte("a"), 2048))},
+	})
+	app.UploadHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUploadHandlerChunking(t *testing.T) {
+	app := newTestApp()
+
+	chunkSize := 20
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, UploadPath, UploadRequest{
+		Documents: []llamastack.Document{{
+			DocumentID: "runbook",
+			Content:    "# Runbook\n\n## Restart\n\nRestart the zebra service.\n\n## Backup\n\nBack up the zebra database nightly.",
+		}},
+		VectorDBID:        "default-vector-db-id-1",
+		EmbeddingModel:    "default-model-id-2",
+		ChunkSizeInTokens: &chunkSize,
+		ChunkingStrategy:  "markdown",
+	})
+	app.UploadHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	result, err := app.repositories.LlamaStackClient.QueryDocuments(nil, llamastack.RAGQueryRequest{
+		Content:     "zebra",
+		VectorDBIDs: []string{"default-vector-db-id-1"},
+	})
+	assert.NoError(t, err)
+	// Every section is a chunk of its own.
+	assert.Equal(t, []string{"runbook", "runbook"}, result.Metadata.DocumentIDs)
+	assert.ElementsMatch(t, []llamastack.TextContent{
+		"## Restart\n\nRestart the zebra service.",
+		"## Backup\n\nBack up the zebra database nightly.",
+	}, result.Metadata.Chunks)
+}
+
+func TestChunkDocumentMetadata(t *testing.T) {
+	request := UploadRequest{ChunkingStrategy: "markdown"}
+	chunks := chunkDocument(llamastack.Document{
+		DocumentID: "runbook",
+		Content:    "# Runbook\n\n## Restart\n\nRestart the service.",
+		Metadata:   map[string]any{"filename": "runbook.md"},
+	}, request.chunkingOptions())
+
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, map[string]any{
+		"filename":          "runbook.md",
+		"document_id":       "runbook",
+		"token_count":       8,
+		"chunk_index":       0,
+		"start_offset":      11,
+		"end_offset":        43,
+		"start_line":        3,
+		"end_line":          5,
+		"chunking_strategy": "markdown",
+		"heading":           "Runbook > Restart",
+	}, chunks[0].Metadata)
+}
+
+func TestUploadHandlerChunkingValidation(t *testing.T) {
+	app := newTestApp()
+
+	chunkSize := 100
+	overlap := 60
+
+	tests := []struct {
+		request UploadRequest
+		field   string
+	}{
+		{request: UploadRequest{ChunkingStrategy: "semantic"}, field: "chunking_strategy"},
+		{request: UploadRequest{ChunkOverlapInTokens: &overlap}, field: "chunk_overlap_in_tokens"},
+		{request: UploadRequest{ChunkingStrategy: "fixed", ChunkSizeInTokens: &chunkSize, ChunkOverlapInTokens: &overlap}, field: "chunk_overlap_in_tokens"},
+	}
+
+	for _, tt := range tests {
+		tt.request.Documents = []llamastack.Document{{DocumentID: "doc", Content: "text"}}
+		tt.request.VectorDBID = "default-vector-db-id-1"
+		tt.request.EmbeddingModel = "default-model-id-2"
+
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodPost, UploadPath, tt.request)
+		app.UploadHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, tt.field)
+
+		var envelope ErrorEnvelope
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+		var fieldErrors map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+		assert.Contains(t, fieldErrors, tt.field)
+	}
+}
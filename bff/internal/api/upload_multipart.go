@@ -0,0 +1,142 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/extraction"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const (
+	// UploadFilesField is the multipart form field documents are uploaded in, the other fields
+	// are named as in UploadRequest.
+	UploadFilesField = "files"
+
+	// Files above this size are buffered on disk while the upload is parsed.
+	maxUploadMemory = 8 << 20
+)
+
+func isMultipartRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// readMultipartUpload reads an UploadRequest from a multipart form, the text of every uploaded
+// file is extracted into a document. Errors of single fields and files are keyed by field name,
+// the returned error is for unreadable forms.
+func (app *App) readMultipartUpload(w http.ResponseWriter, r *http.Request) (UploadRequest, map[string]string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, app.maxUploadSize())
+
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return UploadRequest{}, nil, fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit)
+		}
+		return UploadRequest{}, nil, fmt.Errorf("body contains an invalid multipart form: %w", err)
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			app.logger.Error("Failed to remove uploaded files", "error", err)
+		}
+	}()
+
+	uploadRequest := UploadRequest{
+		VectorDBID:       r.FormValue("vector_db_id"),
+		EmbeddingModel:   r.FormValue("embedding_model"),
+		ChunkingStrategy: r.FormValue("chunking_strategy"),
+	}
+	validationErrors := map[string]string{}
+
+	for field, target := range map[string]**int{
+		"chunk_size_in_tokens":    &uploadRequest.ChunkSizeInTokens,
+		"chunk_overlap_in_tokens": &uploadRequest.ChunkOverlapInTokens,
+	} {
+		if value := r.FormValue(field); value != "" {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				validationErrors[field] = "must be an integer"
+			}
+			*target = &number
+		}
+	}
+
+	files := r.MultipartForm.File[UploadFilesField]
+	if len(files) == 0 {
+		validationErrors[UploadFilesField] = "must contain at least one file"
+	}
+
+	documentIDs := map[string]bool{}
+	for i, file := range files {
+		document, err := extractUploadedFile(file)
+		if err != nil {
+			validationErrors[fmt.Sprintf("%s[%d]", UploadFilesField, i)] = fmt.Sprintf("%s: %v", file.Filename, err)
+			continue
+		}
+
+		// The filename is the document id, which the model sees in retrieved context.
+		documentID := document.DocumentID
+		for n := 2; documentIDs[documentID]; n++ {
+			documentID = fmt.Sprintf("%s (%d)", document.DocumentID, n)
+		}
+		documentIDs[documentID] = true
+		document.DocumentID = documentID
+
+		uploadRequest.Documents = append(uploadRequest.Documents, document)
+	}
+
+	// Report every problem of the form at once, field errors above take precedence.
+	for field, message := range validateChunkingOptions(uploadRequest) {
+		if _, ok := validationErrors[field]; !ok {
+			validationErrors[field] = message
+		}
+	}
+
+	return uploadRequest, validationErrors, nil
+}
+
+// extractUploadedFile turns an uploaded file into a document holding its text.
+func extractUploadedFile(file *multipart.FileHeader) (llamastack.Document, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return llamastack.Document{}, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return llamastack.Document{}, err
+	}
+
+	mimeType := extraction.DetectMimeType(file.Filename, file.Header.Get("Content-Type"), data)
+	if mimeType == "" {
+		return llamastack.Document{}, fmt.Errorf("unsupported file type, supported types are %s", strings.Join(extraction.SupportedMimeTypes, ", "))
+	}
+
+	text, err := extraction.Extract(mimeType, data)
+	if err != nil {
+		return llamastack.Document{}, err
+	}
+
+	// The content is text now, the type of the file is kept in the metadata.
+	contentType := extraction.PlainTextMimeType
+	if mimeType == extraction.MarkdownMimeType {
+		contentType = extraction.MarkdownMimeType
+	}
+
+	return llamastack.Document{
+		DocumentID: file.Filename,
+		Content:    text,
+		MimeType:   &contentType,
+		Metadata: map[string]any{
+			"filename":  file.Filename,
+			"mime_type": mimeType,
+		},
+	}, nil
+}
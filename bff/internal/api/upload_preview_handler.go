@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/chunking"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+type UploadPreviewEnvelope Envelope[models.UploadPreview, None]
+
+// UploadPreviewHandler chunks the documents of an upload without storing them, so chunking
+// options can be compared before anything is written to a vector database. It takes the JSON
+// and multipart payloads of UploadHandler, vector_db_id is ignored and embedding_model is only
+// used to look up its context length in the model profiles. Llama Stack is never called, uploads
+// without chunking_strategy are previewed with the fixed strategy Llama Stack approximates.
+func (app *App) UploadPreviewHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var uploadRequest UploadRequest
+	if isMultipartRequest(r) {
+		var validationErrors map[string]string
+		var err error
+		uploadRequest, validationErrors, err = app.readMultipartUpload(w, r)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+		if len(validationErrors) > 0 {
+			app.failedValidationResponse(w, r, validationErrors)
+			return
+		}
+	} else {
+		// JSON uploads are bounded like multipart ones.
+		r.Body = http.MaxBytesReader(w, r.Body, app.maxUploadSize())
+		if err := json.NewDecoder(r.Body).Decode(&uploadRequest); err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				err = fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit)
+			}
+			app.badRequestResponse(w, r, err)
+			return
+		}
+
+		validationErrors := validateChunkingOptions(uploadRequest)
+		if len(uploadRequest.Documents) == 0 {
+			validationErrors["documents"] = "must contain at least one document"
+		}
+		if len(validationErrors) > 0 {
+			app.failedValidationResponse(w, r, validationErrors)
+			return
+		}
+	}
+
+	options := uploadRequest.chunkingOptions()
+	if options.Strategy == "" {
+		options.Strategy = chunking.FixedStrategy
+		// Llama Stack overlaps chunks by a quarter of their size.
+		options.ChunkOverlapInTokens = options.ChunkSizeInTokens / 4
+	}
+
+	// Chunks past the context length of the embedding model are truncated when embedded.
+	maxChunkTokens := options.ChunkSizeInTokens
+	maxChunkTokensReason := "the chunk size"
+	if uploadRequest.EmbeddingModel != "" {
+		contextLength := app.config.ModelProfiles.ProfileFor(uploadRequest.EmbeddingModel).ContextLength
+		if contextLength > 0 && contextLength < maxChunkTokens {
+			maxChunkTokens = contextLength
+			maxChunkTokensReason = fmt.Sprintf("the context length of %s, the rest would not be embedded", uploadRequest.EmbeddingModel)
+		}
+	}
+
+	preview := models.UploadPreview{
+		ChunkingStrategy:     options.Strategy,
+		ChunkSizeInTokens:    options.ChunkSizeInTokens,
+		ChunkOverlapInTokens: options.ChunkOverlapInTokens,
+		Documents:            []models.DocumentPreview{},
+	}
+
+	for _, document := range uploadRequest.Documents {
+		documentPreview := models.DocumentPreview{
+			DocumentID: document.DocumentID,
+			TokenCount: chunking.EstimateTokens(document.Content),
+			Chunks:     []models.ChunkPreview{},
+		}
+
+		for _, chunk := range chunking.Split(document.Content, options) {
+			if chunk.TokenCount > maxChunkTokens {
+				documentPreview.Warnings = append(documentPreview.Warnings,
+					fmt.Sprintf("chunk %d has about %d tokens, more than %d tokens of %s", chunk.Index, chunk.TokenCount, maxChunkTokens, maxChunkTokensReason))
+			}
+
+			documentPreview.Chunks = append(documentPreview.Chunks, models.ChunkPreview{
+				Content:    chunk.Content,
+				TokenCount: chunk.TokenCount,
+				Metadata:   chunkMetadata(document, chunk, options.Strategy),
+			})
+			preview.TotalTokens += chunk.TokenCount
+		}
+
+		if len(documentPreview.Chunks) == 0 {
+			documentPreview.Warnings = append(documentPreview.Warnings, "document is empty, nothing would be stored")
+		}
+		preview.TotalChunks += len(documentPreview.Chunks)
+		preview.Documents = append(preview.Documents, documentPreview)
+	}
+
+	err := app.WriteJSON(w, http.StatusOK, UploadPreviewEnvelope{Data: preview}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
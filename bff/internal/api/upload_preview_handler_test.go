@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadPreviewHandler(t *testing.T) {
+	app := newTestApp()
+
+	chunkSize := 20
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, UploadPreviewPath, UploadRequest{
+		Documents: []llamastack.Document{
+			{
+				DocumentID: "runbook",
+				Content:    "# Runbook\n\n## Restart\n\nRestart the zebra service.\n\n## Backup\n\nBack up the zebra database nightly.",
+			},
+			{DocumentID: "blank", Content: " \n"},
+		},
+		VectorDBID:        "default-vector-db-id-1",
+		ChunkSizeInTokens: &chunkSize,
+		ChunkingStrategy:  "markdown",
+	})
+	app.UploadPreviewHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var envelope UploadPreviewEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	preview := envelope.Data
+	assert.Equal(t, "markdown", preview.ChunkingStrategy)
+	assert.Equal(t, 20, preview.ChunkSizeInTokens)
+	assert.Equal(t, 2, preview.TotalChunks)
+	assert.Len(t, preview.Documents, 2)
+
+	runbook := preview.Documents[0]
+	assert.Len(t, runbook.Chunks, 2)
+	assert.Equal(t, "## Restart\n\nRestart the zebra service.", runbook.Chunks[0].Content)
+	assert.Equal(t, "Runbook > Backup", runbook.Chunks[1].Metadata["heading"])
+	assert.Equal(t, runbook.Chunks[0].TokenCount+runbook.Chunks[1].TokenCount, preview.TotalTokens)
+	assert.Empty(t, runbook.Warnings)
+
+	assert.Empty(t, preview.Documents[1].Chunks)
+	assert.Len(t, preview.Documents[1].Warnings, 1)
+
+	// Nothing is stored.
+	result, err := app.repositories.LlamaStackClient.QueryDocuments(nil, llamastack.RAGQueryRequest{
+		Content:     "zebra",
+		VectorDBIDs: []string{"default-vector-db-id-1"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Metadata.DocumentIDs)
+}
+
+func TestUploadPreviewHandlerDefaults(t *testing.T) {
+	app := newTestApp()
+	app.config.ModelProfiles = config.ModelProfiles{"default-model-id-2": {ContextLength: 4}}
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, UploadPreviewPath, UploadRequest{
+		Documents:      []llamastack.Document{{DocumentID: "doc", Content: strings.Repeat("zebra ", 10)}},
+		EmbeddingModel: "default-model-id-2",
+	})
+	app.UploadPreviewHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var envelope UploadPreviewEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	// Without a strategy the chunking of Llama Stack is approximated.
+	assert.Equal(t, "fixed", envelope.Data.ChunkingStrategy)
+	assert.Equal(t, 512, envelope.Data.ChunkSizeInTokens)
+	assert.Equal(t, 128, envelope.Data.ChunkOverlapInTokens)
+
+	// The chunk is larger than the embedding model can embed.
+	assert.Len(t, envelope.Data.Documents[0].Chunks, 1)
+	assert.Len(t, envelope.Data.Documents[0].Warnings, 1)
+	assert.Contains(t, envelope.Data.Documents[0].Warnings[0], "default-model-id-2")
+}
+
+func TestUploadPreviewHandlerMultipart(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestMultipartUploadRequest(t, map[string]string{"chunking_strategy": "sentence"}, []testUploadFile{
+		{filename: "notes.md", content: "First sentence. Second sentence."},
+	})
+	app.UploadPreviewHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var envelope UploadPreviewEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "notes.md", envelope.Data.Documents[0].DocumentID)
+	assert.Equal(t, "First sentence. Second sentence.", envelope.Data.Documents[0].Chunks[0].Content)
+}
+
+func TestUploadPreviewHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, UploadPreviewPath, UploadRequest{ChunkingStrategy: "semantic"})
+	app.UploadPreviewHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var fieldErrors map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+	assert.Contains(t, fieldErrors, "documents")
+	assert.Contains(t, fieldErrors, "chunking_strategy")
+}
+
+func TestUploadPreviewHandlerBodyTooLarge(t *testing.T) {
+	app := newTestApp()
+	app.config.MaxUploadSize = 64
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, UploadPreviewPath, UploadRequest{
+		Documents: []llamastack.Document{{DocumentID: "big", Content: strings.Repeat("word ", 100)}},
+	})
+	app.UploadPreviewHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "body must not be larger than 64 bytes", envelope.Error.Message)
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/chunking"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+)
+
+type UsageReportEnvelope Envelope[models.UsageReport, None]
+
+// GetUsageHandler reports the usage of all users to admins, other users only get their own
+// usage per model.
+func (app *App) GetUsageHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	params := r.URL.Query()
+	admin := app.isAdmin(r)
+	validationErrors := map[string]string{}
+
+	parseTime := func(name string) time.Time {
+		value := params.Get(name)
+		if value == "" {
+			return time.Time{}
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			validationErrors[name] = "must be an RFC 3339 timestamp, e.g. 2025-01-31T00:00:00Z"
+		}
+		return parsed
+	}
+	from := parseTime("from")
+	to := parseTime("to")
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		validationErrors["to"] = "must be after from"
+	}
+
+	groupBy := params.Get("group_by")
+	if groupBy == "" {
+		groupBy = repositories.UsageGroupByModel
+		if admin {
+			groupBy = repositories.UsageGroupByUser
+		}
+	}
+	if groupBy != repositories.UsageGroupByUser && groupBy != repositories.UsageGroupByModel {
+		validationErrors["group_by"] = fmt.Sprintf("must be one of %s, %s", repositories.UsageGroupByUser, repositories.UsageGroupByModel)
+	}
+
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	// The usage per user names every user of the BFF.
+	var userID string
+	if !admin {
+		if groupBy == repositories.UsageGroupByUser {
+			app.forbiddenResponse(w, r, "admin access required")
+			return
+		}
+		userID = requestUserID(r)
+	}
+
+	report, err := app.repositories.Usage.GetUsageReport(from, to, groupBy, userID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, UsageReportEnvelope{Data: report}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// recordUsage stores record, failures are logged only since they must not fail the inference.
+func (app *App) recordUsage(r *http.Request, record models.UsageRecord) {
+	if err := app.repositories.Usage.RecordUsage(record); err != nil {
+		app.LogError(r, fmt.Errorf("failed to record usage: %w", err))
+	}
+}
+
+// meteredChatCompletionStream records the usage of a chat completion once it is drained or
+// closed. Token counts come from the metrics Llama Stack attaches to the final chunk.
+type meteredChatCompletionStream struct {
+	repositories.ChatCompletionStream
+	app     *App
+	r       *http.Request
+	modelID string
+	started time.Time
+	metrics []llamastack.Metric
+	once    sync.Once
+}
+
+func (app *App) meterChatCompletion(r *http.Request, modelID string, started time.Time, stream repositories.ChatCompletionStream) repositories.ChatCompletionStream {
+	return &meteredChatCompletionStream{
+		ChatCompletionStream: stream,
+		app:                  app,
+		r:                    r,
+		modelID:              modelID,
+		started:              started,
+	}
+}
+
+func (s *meteredChatCompletionStream) Recv() (*llamastack.ChatCompletionResponseStreamChunk, error) {
+	chunk, err := s.ChatCompletionStream.Recv()
+	if chunk != nil && len(chunk.Metrics) > 0 {
+		s.metrics = chunk.Metrics
+	}
+	if errors.Is(err, io.EOF) {
+		s.record()
+	}
+	return chunk, err
+}
+
+func (s *meteredChatCompletionStream) Close() error {
+	s.record()
+	return s.ChatCompletionStream.Close()
+}
+
+func (s *meteredChatCompletionStream) record() {
+	s.once.Do(func() {
+		promptTokens, completionTokens := tokensFromMetrics(s.metrics)
+		s.app.recordUsage(s.r, models.UsageRecord{
+			UserID:           requestUserID(s.r),
+			ModelID:          s.modelID,
+			Source:           models.ChatCompletionUsageSource,
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			LatencyMs:        time.Since(s.started).Milliseconds(),
+		})
+	})
+}
+
+func tokensFromMetrics(metrics []llamastack.Metric) (promptTokens int64, completionTokens int64) {
+	for _, metric := range metrics {
+		switch metric.Metric {
+		case "prompt_tokens":
+			promptTokens = int64(metric.Value)
+		case "completion_tokens":
+			completionTokens = int64(metric.Value)
+		}
+	}
+	return promptTokens, completionTokens
+}
+
+// meteredAgentTurnStream records the usage of an agent turn once it is drained or closed.
+// Llama Stack reports no usage for agent turns, the prompt tokens are estimated from the
+// instructions and input messages and the completion tokens from the text of the inference steps.
+type meteredAgentTurnStream struct {
+	repositories.AgentTurnStream
+	app          *App
+	r            *http.Request
+	modelID      string
+	started      time.Time
+	promptTokens int64
+	completion   strings.Builder
+	once         sync.Once
+}
+
+func (app *App) meterAgentTurn(r *http.Request, agent *llamastack.Agent, messages []llamastack.Message, started time.Time, stream repositories.AgentTurnStream) repositories.AgentTurnStream {
+	promptTokens := int64(chunking.EstimateTokens(agent.AgentConfig.Instructions))
+	for _, message := range messages {
+		promptTokens += int64(chunking.EstimateTokens(message.Content))
+	}
+
+	return &meteredAgentTurnStream{
+		AgentTurnStream: stream,
+		app:             app,
+		r:               r,
+		modelID:         agent.AgentConfig.Model,
+		started:         started,
+		promptTokens:    promptTokens,
+	}
+}
+
+func (s *meteredAgentTurnStream) Recv() (*llamastack.AgentTurnResponseStreamChunk, error) {
+	chunk, err := s.AgentTurnStream.Recv()
+	if chunk != nil {
+		payload := chunk.Event.Payload
+		switch {
+		case payload.EventType == llamastack.StepProgressEventType && payload.StepType == llamastack.InferenceStepType:
+			var delta llamastack.ContentDelta
+			if json.Unmarshal(payload.Delta, &delta) == nil && delta.Type == "text" {
+				s.completion.WriteString(delta.Text)
+			}
+		case payload.EventType == llamastack.TurnCompleteEventType && s.completion.Len() == 0:
+			// Turns whose steps were not streamed only carry the output message.
+			if payload.Turn != nil && payload.Turn.OutputMessage != nil {
+				s.completion.WriteString(string(payload.Turn.OutputMessage.Content))
+			}
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		s.record()
+	}
+	return chunk, err
+}
+
+func (s *meteredAgentTurnStream) Close() error {
+	s.record()
+	return s.AgentTurnStream.Close()
+}
+
+func (s *meteredAgentTurnStream) record() {
+	s.once.Do(func() {
+		s.app.recordUsage(s.r, models.UsageRecord{
+			UserID:           requestUserID(s.r),
+			ModelID:          s.modelID,
+			Source:           models.AgentTurnUsageSource,
+			PromptTokens:     s.promptTokens,
+			CompletionTokens: int64(chunking.EstimateTokens(s.completion.String())),
+			LatencyMs:        time.Since(s.started).Milliseconds(),
+			Estimated:        true,
+		})
+	})
+}
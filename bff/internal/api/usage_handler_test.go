@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/auth"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatCompletionUsageIsReported(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, ChatCompletionsPath, ChatCompletionRequest{
+		ModelID: "default-model-id-1",
+		Messages: []llamastack.Message{
+			{Role: llamastack.UserRole, Content: "hello there"},
+		},
+	})
+	app.ChatCompletionHandler(rr, req, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req = newTestRequest(t, http.MethodGet, UsagePath+"?group_by=model", nil)
+	app.GetUsageHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope UsageReportEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, repositories.UsageGroupByModel, envelope.Data.GroupBy)
+	assert.Len(t, envelope.Data.Items, 1)
+	assert.Equal(t, "default-model-id-1", envelope.Data.Items[0].ModelID)
+	assert.Equal(t, int64(1), envelope.Data.Items[0].Requests)
+	assert.Greater(t, envelope.Data.Items[0].CompletionTokens, int64(0))
+	assert.Equal(t, envelope.Data.Items[0].PromptTokens+envelope.Data.Items[0].CompletionTokens, envelope.Data.Items[0].TotalTokens)
+}
+
+func TestGetUsageHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, UsagePath+"?from=yesterday&group_by=team", nil)
+	app.GetUsageHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestGetUsageHandlerScopedToUser(t *testing.T) {
+	app := newTestApp()
+	app.config.OAuthEnabled = true
+	app.config.AdminUsers = []string{"alice"}
+
+	for _, record := range []models.UsageRecord{
+		{UserID: "alice", ModelID: "llama3.2:3b", PromptTokens: 10},
+		{UserID: "bob", ModelID: "llama3.2:3b", PromptTokens: 20},
+		{UserID: "bob", ModelID: "granite-3.3-8b", PromptTokens: 30},
+	} {
+		assert.NoError(t, app.repositories.Usage.RecordUsage(record))
+	}
+
+	getUsage := func(userID string, query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodGet, UsagePath+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, userID))
+		app.GetUsageHandler(rr, req, nil)
+		return rr
+	}
+
+	// Other users only get their own usage per model.
+	rr := getUsage("bob", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope UsageReportEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, repositories.UsageGroupByModel, envelope.Data.GroupBy)
+	assert.Equal(t, "bob", envelope.Data.UserID)
+	assert.Len(t, envelope.Data.Items, 2)
+	assert.Equal(t, int64(30), envelope.Data.Items[0].PromptTokens)
+	assert.Equal(t, int64(20), envelope.Data.Items[1].PromptTokens)
+
+	assert.Equal(t, http.StatusForbidden, getUsage("bob", "?group_by=user").Code)
+
+	// Admins get the usage of everyone, per user by default.
+	rr = getUsage("alice", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	envelope = UsageReportEnvelope{}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, repositories.UsageGroupByUser, envelope.Data.GroupBy)
+	assert.Empty(t, envelope.Data.UserID)
+	assert.Len(t, envelope.Data.Items, 2)
+	assert.Equal(t, "bob", envelope.Data.Items[0].UserID)
+}
+
+func TestLlamaStackProxyRecordsUsage(t *testing.T) {
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"event\":{\"event_type\":\"progress\",\"delta\":{\"type\":\"text\",\"text\":\"hi\"}}}\n\n")
+		_, _ = fmt.Fprint(w, "data: {\"event\":{\"event_type\":\"complete\",\"delta\":{\"type\":\"text\",\"text\":\"\"}},\"metrics\":[{\"metric\":\"prompt_tokens\",\"value\":12},{\"metric\":\"completion_tokens\",\"value\":3}]}\n\n")
+	}))
+	defer llamaStack.Close()
+
+	app := newTestApp()
+	app.config.LlamaStackURL = llamaStack.URL
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/llama-stack/v1/inference/chat-completion",
+		strings.NewReader(`{"model_id":"proxied-model","messages":[],"stream":true}`))
+	app.HandleLlamaStackProxy(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "prompt_tokens")
+
+	report, err := app.repositories.Usage.GetUsageReport(time.Time{}, time.Time{}, repositories.UsageGroupByUser, "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	assert.Equal(t, AnonymousUserID, report.Items[0].UserID)
+	assert.Equal(t, int64(12), report.Items[0].PromptTokens)
+	assert.Equal(t, int64(3), report.Items[0].CompletionTokens)
+}
+
+func TestAgentTurnAndEmbeddingsUsageIsReported(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	app.CreateAgentHandler(rr, newTestRequest(t, http.MethodPost, AgentListPath, AgentCreateRequest{
+		Model:        "default-model-id-1",
+		Instructions: "You are a helpful assistant",
+	}), nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var agent AgentEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&agent))
+	agentParams := httprouter.Params{{Key: "agent_id", Value: agent.Data.AgentID}}
+
+	rr = httptest.NewRecorder()
+	app.CreateAgentSessionHandler(rr, newTestRequest(t, http.MethodPost, AgentSessionListPath, AgentSessionCreateRequest{
+		SessionName: "playground",
+	}), agentParams)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var session AgentSessionEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&session))
+
+	rr = httptest.NewRecorder()
+	app.CreateAgentTurnHandler(rr, newTestRequest(t, http.MethodPost, AgentTurnListPath, AgentTurnCreateRequest{
+		Messages: []llamastack.Message{{Role: llamastack.UserRole, Content: "hello agent"}},
+	}), append(agentParams, httprouter.Param{Key: "session_id", Value: session.Data.SessionID}))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	app.EmbeddingsHandler(rr, newTestRequest(t, http.MethodPost, EmbeddingsPath, EmbeddingsRequest{
+		ModelID: "default-model-id-2",
+		Texts:   []string{"vector databases"},
+	}), nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	report, err := app.repositories.Usage.GetUsageReport(time.Time{}, time.Time{}, repositories.UsageGroupByModel, "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 2)
+
+	byModel := map[string]models.UsageSummary{}
+	for _, item := range report.Items {
+		byModel[item.ModelID] = item
+	}
+
+	// Prompt tokens are estimated from the instructions and the message.
+	turn := byModel["default-model-id-1"]
+	assert.Equal(t, int64(1), turn.Requests)
+	assert.Equal(t, int64(7+3), turn.PromptTokens)
+	assert.Greater(t, turn.CompletionTokens, int64(0))
+
+	embeddings := byModel["default-model-id-2"]
+	assert.Equal(t, int64(1), embeddings.Requests)
+	assert.Equal(t, int64(4), embeddings.PromptTokens)
+	assert.Zero(t, embeddings.CompletionTokens)
+}
+
+func TestLlamaStackProxyRejectsLargeBody(t *testing.T) {
+	called := false
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer llamaStack.Close()
+
+	app := newTestApp()
+	app.config.LlamaStackURL = llamaStack.URL
+	app.config.MaxUploadSize = 16
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/llama-stack/v1/inference/chat-completion",
+		strings.NewReader(`{"model_id":"proxied-model","messages":[]}`))
+	app.HandleLlamaStackProxy(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.False(t, called)
+}
+
+func TestLlamaStackProxyCachesUser(t *testing.T) {
+	userInfoCalls := 0
+	userInfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userInfoCalls++
+		_, _ = fmt.Fprint(w, `{"preferred_username":"alice"}`)
+	}))
+	defer userInfo.Close()
+
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"usage":{"prompt_tokens":5,"completion_tokens":2}}`)
+	}))
+	defer llamaStack.Close()
+
+	app := newTestApp()
+	app.config.LlamaStackURL = llamaStack.URL
+	app.config.OAuthEnabled = true
+	app.config.OAuthUserInfoEndpoint = userInfo.URL
+	app.proxyTokenCache = auth.NewTokenCache(time.Minute, 10)
+
+	for range 2 {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/llama-stack/v1/openai/v1/chat/completions",
+			strings.NewReader(`{"model":"proxied-model","messages":[]}`))
+		req.Header.Set("Authorization", "Bearer alice-token")
+		app.HandleLlamaStackProxy(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	assert.Equal(t, 1, userInfoCalls)
+
+	report, err := app.repositories.Usage.GetUsageReport(time.Time{}, time.Time{}, repositories.UsageGroupByUser, "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	assert.Equal(t, "alice", report.Items[0].UserID)
+	assert.Equal(t, int64(2), report.Items[0].Requests)
+}
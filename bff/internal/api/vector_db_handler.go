@@ -1,24 +1,44 @@
 package api
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
 )
 
 type VectorDBEnvelope Envelope[models.VectorDB, None]
-type VectorDBListEnvelope Envelope[models.VectorDBList, None]
+type VectorDBListEnvelope Envelope[models.VectorDBList, *models.ListMetadata]
+
+var vectorDBSortFields = listSortFields[llamastack.VectorDB]{
+	"identifier":      compareStrings(func(v llamastack.VectorDB) string { return v.Identifier }),
+	"provider_id":     compareStrings(func(v llamastack.VectorDB) string { return v.ProviderID }),
+	"embedding_model": compareStrings(func(v llamastack.VectorDB) string { return v.EmbeddingModel }),
+	"embedding_dimension": func(a, b llamastack.VectorDB) int {
+		return cmp.Compare(a.EmbeddingDimension, b.EmbeddingDimension)
+	},
+}
 
 // VectorDBRegistrationRequest represents the request body for registering a vector database
 type VectorDBRegistrationRequest struct {
 	VectorDBID     string `json:"vector_db_id"`
 	EmbeddingModel string `json:"embedding_model"`
+	// Must match the embedding dimension of the model, defaults to it.
+	EmbeddingDimension int64 `json:"embedding_dimension,omitempty"`
+	// One of the vector_io providers, Llama Stack picks the first one when omitted.
+	ProviderID string `json:"provider_id,omitempty"`
+	// The name of the collection at the provider, defaults to VectorDBID.
+	ProviderVectorDBID string `json:"provider_vector_db_id,omitempty"`
 }
 
 func (app *App) RegisterVectorDBHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
@@ -30,7 +50,7 @@ func (app *App) RegisterVectorDBHandler(w http.ResponseWriter, r *http.Request,
 
 	// Parse the request body
 	var requestBody VectorDBRegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+	if err := app.ReadJSON(w, r, &requestBody); err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
@@ -45,19 +65,75 @@ func (app *App) RegisterVectorDBHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	// Llama Stack registers an existing vector database again without error, which must not hand
+	// it over to the user registering it again.
+	_, err := app.repositories.LlamaStackClient.GetVectorDB(client, requestBody.VectorDBID)
+	if err == nil {
+		app.failedValidationResponse(w, r, map[string]string{
+			"vector_db_id": fmt.Sprintf("vector database %q already exists", requestBody.VectorDBID),
+		})
+		return
+	}
+	var httpError *integrations.HTTPError
+	if !errors.As(err, &httpError) || httpError.StatusCode != http.StatusNotFound {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	modelList, err := app.repositories.LlamaStackClient.GetAllModels(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	providerList, err := app.repositories.LlamaStackClient.GetAllProviders(client)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if validationErrors := validateVectorDBRegistration(requestBody, modelList, providerList); len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
+	embeddingDimension := requestBody.EmbeddingDimension
+	if embeddingDimension == 0 {
+		embeddingDimension = modelEmbeddingDimension(findModel(modelList, requestBody.EmbeddingModel))
+	}
+
 	// Create a VectorDB struct for the repository call
 	vectorDB := llamastack.VectorDB{
-		Identifier: requestBody.VectorDBID,
+		Identifier:         requestBody.VectorDBID,
+		ProviderID:         requestBody.ProviderID,
+		ProviderResourceID: requestBody.ProviderVectorDBID,
+		EmbeddingDimension: embeddingDimension,
 		// Other fields will be populated by the Llama Stack API
 	}
 
 	// Register the vector database
-	err := app.repositories.LlamaStackClient.RegisterVectorDB(client, vectorDB, requestBody.EmbeddingModel)
+	err = app.repositories.LlamaStackClient.RegisterVectorDB(client, vectorDB, requestBody.EmbeddingModel)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	// The BFF does not forward the token of the user to Llama Stack, it records the owner itself.
+	if app.config.OAuthEnabled {
+		err := app.repositories.VectorDBOwners.SetOwner(requestBody.VectorDBID, requestUserID(r))
+		if errors.Is(err, repositories.ErrVectorDBOwnerExists) {
+			// Another user registered the same id concurrently.
+			app.failedValidationResponse(w, r, map[string]string{
+				"vector_db_id": fmt.Sprintf("vector database %q already exists", requestBody.VectorDBID),
+			})
+			return
+		}
+		if err != nil {
+			app.serverErrorResponse(w, r, fmt.Errorf("failed to record the owner of vector database %s: %w", requestBody.VectorDBID, err))
+			return
+		}
+	}
+
 	// Return success response
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(map[string]string{
@@ -76,14 +152,29 @@ func (app *App) GetAllVectorDBsHandler(w http.ResponseWriter, r *http.Request, _
 		return
 	}
 
+	listParams, validationErrors := parseListParams(r.URL.Query(), vectorDBSortFields)
+	if len(validationErrors) > 0 {
+		app.failedValidationResponse(w, r, validationErrors)
+		return
+	}
+
 	vectorDBList, err := app.repositories.LlamaStackClient.GetAllVectorDBs(client)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	owners, err := app.repositories.VectorDBOwners.GetOwners()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	page, metadata := paginate(vectorDBList.Data, listParams, vectorDBSortFields)
+
 	result := VectorDBListEnvelope{
-		Data: convertVectorDBList(vectorDBList),
+		Data:     convertVectorDBList(&llamastack.VectorDBList{Data: page}, owners),
+		Metadata: metadata,
 	}
 
 	err = app.WriteJSON(w, http.StatusOK, result, nil)
@@ -93,21 +184,151 @@ func (app *App) GetAllVectorDBsHandler(w http.ResponseWriter, r *http.Request, _
 	}
 }
 
-func convertVectorDB(vectorDB *llamastack.VectorDB) models.VectorDB {
+func (app *App) GetVectorDBHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	vectorDB, err := app.repositories.LlamaStackClient.GetVectorDB(client, ps.ByName("vector_db_id"))
+	if err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	owner, err := app.repositories.VectorDBOwners.GetOwner(vectorDB.Identifier)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, VectorDBEnvelope{Data: convertVectorDB(vectorDB, owner)}, nil)
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *App) UnregisterVectorDBHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	client, ok := r.Context().Value(constants.LlamaStackHttpClientKey).(integrations.HTTPClientInterface)
+
+	if !ok {
+		app.serverErrorResponse(w, r, errors.New("REST client not found"))
+		return
+	}
+
+	vectorDBID := ps.ByName("vector_db_id")
+
+	if _, err := app.repositories.LlamaStackClient.GetVectorDB(client, vectorDBID); err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	owns, err := app.ownsVectorDB(r, vectorDBID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !owns {
+		app.forbiddenResponse(w, r, "only the owner of a vector database can unregister it")
+		return
+	}
+
+	if err := app.repositories.LlamaStackClient.UnregisterVectorDB(client, vectorDBID); err != nil {
+		app.upstreamErrorResponse(w, r, err)
+		return
+	}
+
+	// A vector database registered again under the same id starts without owner.
+	if err := app.repositories.VectorDBOwners.DeleteOwner(vectorDBID); err != nil {
+		app.LogError(r, fmt.Errorf("failed to forget the owner of vector database %s: %w", vectorDBID, err))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateVectorDBRegistration checks the embedding model and provider of request against the
+// distribution, errors are keyed by field name.
+func validateVectorDBRegistration(request VectorDBRegistrationRequest, modelList *llamastack.ModelList, providerList *llamastack.ProviderList) map[string]string {
+	validationErrors := map[string]string{}
+
+	if request.EmbeddingDimension < 0 {
+		validationErrors["embedding_dimension"] = "must be a positive integer"
+	}
+
+	model := findModel(modelList, request.EmbeddingModel)
+	switch {
+	case model == nil:
+		validationErrors["embedding_model"] = fmt.Sprintf("model %q does not exist", request.EmbeddingModel)
+	case model.ModelType != EmbeddingModelType:
+		validationErrors["embedding_model"] = fmt.Sprintf("model %q is not an embedding model", request.EmbeddingModel)
+	case request.EmbeddingDimension > 0:
+		// Vectors of another dimension than the model produces cannot be stored or searched.
+		if dimension := modelEmbeddingDimension(model); dimension > 0 && dimension != request.EmbeddingDimension {
+			validationErrors["embedding_dimension"] = fmt.Sprintf("must match the embedding dimension %d of model %q", dimension, request.EmbeddingModel)
+		}
+	}
+
+	if request.ProviderID != "" {
+		providerIDs := vectorIOProviderIDs(providerList)
+		if !slices.Contains(providerIDs, request.ProviderID) {
+			validationErrors["provider_id"] = "must be one of the vector_io providers " + strings.Join(providerIDs, ", ")
+		}
+	}
+
+	return validationErrors
+}
+
+// modelEmbeddingDimension returns the embedding dimension in the metadata of model, zero when it
+// is unknown.
+func modelEmbeddingDimension(model *llamastack.Model) int64 {
+	return metadataInt(model, "embedding_dimension")
+}
+
+func vectorIOProviderIDs(providerList *llamastack.ProviderList) []string {
+	var providerIDs []string
+	for _, provider := range providerList.Data {
+		if provider.API == llamastack.VectorIOProviderAPI {
+			providerIDs = append(providerIDs, provider.ProviderID)
+		}
+	}
+	return providerIDs
+}
+
+// ownsVectorDB reports whether the user of r may change the vector database vectorDBID. Anybody
+// may while authentication is disabled, otherwise only the owner recorded by the BFF, so vector
+// databases registered without authentication cannot be changed once it is enabled.
+func (app *App) ownsVectorDB(r *http.Request, vectorDBID string) (bool, error) {
+	if !app.config.OAuthEnabled {
+		return true, nil
+	}
+
+	owner, err := app.repositories.VectorDBOwners.GetOwner(vectorDBID)
+	if err != nil {
+		return false, err
+	}
+	return owner != "" && owner == requestUserID(r), nil
+}
+
+func convertVectorDB(vectorDB *llamastack.VectorDB, owner string) models.VectorDB {
 	return models.VectorDB{
 		Identifier:         vectorDB.Identifier,
 		ProviderID:         vectorDB.ProviderID,
 		ProviderResourceID: vectorDB.ProviderResourceID,
 		EmbeddingDimension: vectorDB.EmbeddingDimension,
 		EmbeddingModel:     vectorDB.EmbeddingModel,
+		Owner:              owner,
 	}
 }
 
-func convertVectorDBList(vectorDBList *llamastack.VectorDBList) models.VectorDBList {
+// convertVectorDBList converts vectorDBList, owners maps vector database ids to their owners.
+func convertVectorDBList(vectorDBList *llamastack.VectorDBList, owners map[string]string) models.VectorDBList {
 	var items []models.VectorDB
 
 	for _, vectorDB := range vectorDBList.Data {
-		items = append(items, convertVectorDB(&vectorDB))
+		items = append(items, convertVectorDB(&vectorDB, owners[vectorDB.Identifier]))
 	}
 
 	return models.VectorDBList{
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/constants"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/mocks"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVectorDBRequest(t *testing.T, method string, vectorDBID string, userID string) (*http.Request, httprouter.Params) {
+	req := newTestRequest(t, method, VectorDBListPath+"/"+vectorDBID, nil)
+	if userID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, userID))
+	}
+	return req, httprouter.Params{{Key: "vector_db_id", Value: vectorDBID}}
+}
+
+func TestVectorDBOwnership(t *testing.T) {
+	app := newTestApp()
+	app.config.OAuthEnabled = true
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, VectorDBListPath, VectorDBRegistrationRequest{
+		VectorDBID:     "alice-docs",
+		EmbeddingModel: "default-model-id-2",
+	})
+	req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, "alice"))
+	app.RegisterVectorDBHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req, ps := newTestVectorDBRequest(t, http.MethodGet, "alice-docs", "bob")
+	app.GetVectorDBHandler(rr, req, ps)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var envelope VectorDBEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "alice", envelope.Data.Owner)
+
+	tests := []struct {
+		vectorDBID string
+		userID     string
+		expected   int
+	}{
+		{vectorDBID: "alice-docs", userID: "bob", expected: http.StatusForbidden},
+		// Vector DBs registered without authentication have no owner.
+		{vectorDBID: "default-vector-db-id-1", userID: "alice", expected: http.StatusForbidden},
+		{vectorDBID: "alice-docs", userID: "alice", expected: http.StatusNoContent},
+		{vectorDBID: "alice-docs", userID: "alice", expected: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rr = httptest.NewRecorder()
+		req, ps = newTestVectorDBRequest(t, http.MethodDelete, tt.vectorDBID, tt.userID)
+		app.UnregisterVectorDBHandler(rr, req, ps)
+
+		assert.Equal(t, tt.expected, rr.Code, "%s by %s", tt.vectorDBID, tt.userID)
+	}
+}
+
+func TestRegisterVectorDBCannotTakeOverOwnership(t *testing.T) {
+	app := newTestApp()
+	app.config.OAuthEnabled = true
+
+	request := VectorDBRegistrationRequest{
+		VectorDBID:     "alice-docs",
+		EmbeddingModel: "default-model-id-2",
+	}
+
+	// Llama Stack accepts the same registration twice.
+	vectorDB := llamastack.VectorDB{Identifier: request.VectorDBID}
+	assert.NoError(t, app.repositories.LlamaStackClient.RegisterVectorDB(nil, vectorDB, request.EmbeddingModel))
+	assert.NoError(t, app.repositories.LlamaStackClient.RegisterVectorDB(nil, vectorDB, request.EmbeddingModel))
+	assert.NoError(t, app.repositories.LlamaStackClient.UnregisterVectorDB(nil, request.VectorDBID))
+
+	for _, tt := range []struct {
+		userID   string
+		expected int
+	}{
+		{userID: "alice", expected: http.StatusCreated},
+		{userID: "bob", expected: http.StatusUnprocessableEntity},
+		{userID: "alice", expected: http.StatusUnprocessableEntity},
+	} {
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodPost, VectorDBListPath, request)
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, tt.userID))
+		app.RegisterVectorDBHandler(rr, req, nil)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.userID)
+	}
+
+	owner, err := app.repositories.VectorDBOwners.GetOwner("alice-docs")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+
+	rr := httptest.NewRecorder()
+	req, ps := newTestVectorDBRequest(t, http.MethodDelete, "alice-docs", "bob")
+	app.UnregisterVectorDBHandler(rr, req, ps)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUnregisterVectorDBWithoutAuth(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req, ps := newTestVectorDBRequest(t, http.MethodDelete, "default-vector-db-id-1", "")
+	app.UnregisterVectorDBHandler(rr, req, ps)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req, ps = newTestVectorDBRequest(t, http.MethodGet, "default-vector-db-id-1", "")
+	app.GetVectorDBHandler(rr, req, ps)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestRegisterVectorDBHandlerOptions(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, VectorDBListPath, VectorDBRegistrationRequest{
+		VectorDBID:         "pg-docs",
+		EmbeddingModel:     "default-model-id-2",
+		ProviderID:         "pgvector",
+		ProviderVectorDBID: "docs_collection",
+	})
+	app.RegisterVectorDBHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req, ps := newTestVectorDBRequest(t, http.MethodGet, "pg-docs", "")
+	app.GetVectorDBHandler(rr, req, ps)
+
+	var envelope VectorDBEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "pgvector", envelope.Data.ProviderID)
+	assert.Equal(t, "docs_collection", envelope.Data.ProviderResourceID)
+	// The dimension defaults to the one of the embedding model.
+	assert.Equal(t, int64(mocks.MockEmbeddingDimension), envelope.Data.EmbeddingDimension)
+}
+
+func TestRegisterVectorDBHandlerValidation(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		request VectorDBRegistrationRequest
+		field   string
+	}{
+		{request: VectorDBRegistrationRequest{EmbeddingModel: "missing-model"}, field: "embedding_model"},
+		{request: VectorDBRegistrationRequest{EmbeddingModel: "default-model-id-1"}, field: "embedding_model"},
+		{request: VectorDBRegistrationRequest{EmbeddingModel: "default-model-id-2", EmbeddingDimension: 768}, field: "embedding_dimension"},
+		{request: VectorDBRegistrationRequest{EmbeddingModel: "default-model-id-2", EmbeddingDimension: -1}, field: "embedding_dimension"},
+		// Only vector_io providers can store vector databases.
+		{request: VectorDBRegistrationRequest{EmbeddingModel: "default-model-id-2", ProviderID: "llama-guard"}, field: "provider_id"},
+	}
+
+	for _, tt := range tests {
+		tt.request.VectorDBID = "invalid-docs"
+
+		rr := httptest.NewRecorder()
+		req := newTestRequest(t, http.MethodPost, VectorDBListPath, tt.request)
+		app.RegisterVectorDBHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, tt.field)
+
+		var envelope ErrorEnvelope
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+		var fieldErrors map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(envelope.Error.Message), &fieldErrors))
+		assert.Contains(t, fieldErrors, tt.field)
+	}
+}
+
+func TestRegisterVectorDBHandlerUnknownField(t *testing.T) {
+	app := newTestApp()
+
+	// A misspelled option must not silently fall back to its default.
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, VectorDBListPath, map[string]any{
+		"vector_db_id":       "typo-docs",
+		"embedding_model":    "default-model-id-2",
+		"embeding_dimension": 384,
+	})
+	app.RegisterVectorDBHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var envelope ErrorEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, `body contains unknown key "embeding_dimension"`, envelope.Error.Message)
+}
+
+func TestGetAllProvidersHandler(t *testing.T) {
+	app := newTestApp()
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodGet, ProviderListPath+"?api=vector_io", nil)
+	app.GetAllProvidersHandler(rr, req, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "mock-password")
+
+	var envelope ProviderListEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+
+	var providerIDs []string
+	for _, provider := range envelope.Data.Items {
+		providerIDs = append(providerIDs, provider.ProviderID)
+	}
+	assert.Equal(t, []string{"faiss", "milvus", "pgvector"}, providerIDs)
+}
+
+func TestVectorDBOwnershipRecordedByBFF(t *testing.T) {
+	// Llama Stack never reports an owner since the token of the user is not forwarded.
+	registered := false
+	llamaStack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/models":
+			_, _ = fmt.Fprint(w, `{"data": [{"identifier": "all-minilm", "model_type": "embedding", "metadata": {"embedding_dimension": 384}}]}`)
+		case r.URL.Path == "/v1/providers":
+			_, _ = fmt.Fprint(w, `{"data": []}`)
+		case r.Method == http.MethodPost:
+			registered = true
+			_, _ = fmt.Fprint(w, `{"identifier": "alice-docs"}`)
+		case !registered:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"detail": "Vector DB alice-docs not found"}`)
+		case r.Method == http.MethodGet:
+			_, _ = fmt.Fprint(w, `{"identifier": "alice-docs", "embedding_model": "all-minilm", "embedding_dimension": 384}`)
+		case r.Method == http.MethodDelete:
+			registered = false
+		}
+	}))
+	defer llamaStack.Close()
+
+	lsClient, err := repositories.NewLlamaStackClient()
+	assert.NoError(t, err)
+	app := newTestApp()
+	app.config.OAuthEnabled = true
+	app.repositories = repositories.NewRepositories(lsClient)
+
+	client, err := integrations.NewHTTPClient(slog.Default(), llamaStack.URL)
+	assert.NoError(t, err)
+	withClient := func(req *http.Request) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), constants.LlamaStackHttpClientKey, client))
+	}
+
+	rr := httptest.NewRecorder()
+	req := newTestRequest(t, http.MethodPost, VectorDBListPath, VectorDBRegistrationRequest{
+		VectorDBID:     "alice-docs",
+		EmbeddingModel: "all-minilm",
+	})
+	req = req.WithContext(context.WithValue(req.Context(), constants.UserIDKey, "alice"))
+	app.RegisterVectorDBHandler(rr, withClient(req), nil)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req, ps := newTestVectorDBRequest(t, http.MethodGet, "alice-docs", "bob")
+	app.GetVectorDBHandler(rr, withClient(req), ps)
+
+	var envelope VectorDBEnvelope
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&envelope))
+	assert.Equal(t, "alice", envelope.Data.Owner)
+
+	for _, tt := range []struct {
+		userID   string
+		expected int
+	}{
+		{userID: "bob", expected: http.StatusForbidden},
+		{userID: "alice", expected: http.StatusNoContent},
+		{userID: "alice", expected: http.StatusNotFound},
+	} {
+		rr = httptest.NewRecorder()
+		req, ps = newTestVectorDBRequest(t, http.MethodDelete, "alice-docs", tt.userID)
+		app.UnregisterVectorDBHandler(rr, withClient(req), ps)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.userID)
+	}
+
+	owner, err := app.repositories.VectorDBOwners.GetOwner("alice-docs")
+	assert.NoError(t, err)
+	assert.Empty(t, owner)
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -20,6 +21,9 @@ type TokenResponse struct {
 	Scope        string `json:"scope"`
 }
 
+// maxUserInfoSize bounds the user info response read to find the username.
+const maxUserInfoSize = 1 << 20
+
 type OAuthHandler struct {
 	config config.EnvConfig
 	client *http.Client
@@ -51,8 +55,39 @@ func ExtractToken(r *http.Request) (string, error) {
 	return parts[1], nil
 }
 
-// ValidateToken validates the token with the configured OAuth user info endpoint
-func (h *OAuthHandler) ValidateToken(ctx context.Context, token string) error {
+// defaultUsernameClaims are looked up in the user info response in order when no claim is
+// configured, they cover the OpenShift user object and the OIDC userinfo claims.
+var defaultUsernameClaims = []string{"metadata.name", "preferred_username", "sub"}
+
+// usernameFromUserInfo returns the first of claims set to a non-empty string in the user info
+// response, claims are dot separated paths into the JSON object.
+func usernameFromUserInfo(body []byte, claims []string) string {
+	var userInfo map[string]any
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return ""
+	}
+
+	for _, claim := range claims {
+		var value any = userInfo
+		for _, key := range strings.Split(claim, ".") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				value = nil
+				break
+			}
+			value = object[key]
+		}
+		if username, ok := value.(string); ok && username != "" {
+			return username
+		}
+	}
+	return ""
+}
+
+// ValidateToken validates the token with the configured OAuth user info endpoint and returns
+// the name of the user it belongs to. Any 200 response validates the token, the name is empty
+// when the response has none of the username claims.
+func (h *OAuthHandler) ValidateToken(ctx context.Context, token string) (string, error) {
 	// Use configurable user info endpoint, fallback to OpenShift default if not set
 	userInfoEndpoint := h.config.OAuthUserInfoEndpoint
 	if userInfoEndpoint == "" {
@@ -67,7 +102,7 @@ func (h *OAuthHandler) ValidateToken(ctx context.Context, token string) error {
 		h.logger.Error("Failed to create token validation request",
 			slog.String("error", err.Error()),
 			slog.String("endpoint", userInfoEndpoint))
-		return fmt.Errorf("error creating validation request")
+		return "", fmt.Errorf("error creating validation request")
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -76,7 +111,7 @@ func (h *OAuthHandler) ValidateToken(ctx context.Context, token string) error {
 		h.logger.Error("Token validation request failed",
 			slog.String("error", err.Error()),
 			slog.String("endpoint", userInfoEndpoint))
-		return fmt.Errorf("token validation failed")
+		return "", fmt.Errorf("token validation failed")
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -93,11 +128,33 @@ func (h *OAuthHandler) ValidateToken(ctx context.Context, token string) error {
 			slog.Int("response_body_length", len(body)))
 
 		// Return generic error message without exposing sensitive details
-		return fmt.Errorf("token validation failed")
+		return "", fmt.Errorf("token validation failed")
+	}
+
+	claims := defaultUsernameClaims
+	if h.config.OAuthUsernameClaim != "" {
+		claims = []string{h.config.OAuthUsernameClaim}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxUserInfoSize))
+	if err != nil {
+		h.logger.Error("Failed to read token validation response",
+			slog.String("error", err.Error()),
+			slog.String("endpoint", userInfoEndpoint))
+		return "", fmt.Errorf("token validation failed")
+	}
+
+	username := usernameFromUserInfo(body, claims)
+	if username == "" {
+		// Never map an authenticated user onto the shared anonymous owner.
+		h.logger.Error("Token validation response has no username claim",
+			slog.String("endpoint", userInfoEndpoint),
+			slog.Any("claims", claims))
+		return "", fmt.Errorf("token validation failed")
 	}
 
 	h.logger.Info("Token validation successful")
-	return nil
+	return username, nil
 }
 
 // PropagateToken propagates the token to the backend service
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUserInfoServer(t *testing.T, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    string
+		status   int
+		body     string
+		expected string
+	}{
+		{name: "OpenShift user", status: http.StatusOK, body: `{"kind":"User","metadata":{"name":"alice","uid":"1234"}}`, expected: "alice"},
+		{name: "OIDC claims", status: http.StatusOK, body: `{"sub":"f3a9","preferred_username":"bob"}`, expected: "bob"},
+		{name: "OIDC subject only", status: http.StatusOK, body: `{"sub":"f3a9"}`, expected: "f3a9"},
+		{name: "configured claim", claim: "email", status: http.StatusOK, body: `{"sub":"f3a9","email":"carol@example.com"}`, expected: "carol@example.com"},
+		{name: "configured nested claim", claim: "user.login", status: http.StatusOK, body: `{"user":{"login":"dave"}}`, expected: "dave"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestUserInfoServer(t, tt.status, tt.body)
+			handler := NewOAuthHandler(config.EnvConfig{OAuthUserInfoEndpoint: server.URL, OAuthUsernameClaim: tt.claim}, slog.Default())
+
+			username, err := handler.ValidateToken(context.Background(), "test-token")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, username)
+		})
+	}
+}
+
+func TestValidateTokenRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		claim  string
+		status int
+		body   string
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"metadata":{"name":"alice"}}`},
+		// A valid token without username claim must not share the anonymous identity.
+		{name: "no claim", status: http.StatusOK, body: `{"groups":["dev"]}`},
+		{name: "configured claim missing", claim: "email", status: http.StatusOK, body: `{"preferred_username":"bob"}`},
+		{name: "not JSON", status: http.StatusOK, body: `ok`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestUserInfoServer(t, tt.status, tt.body)
+			handler := NewOAuthHandler(config.EnvConfig{OAuthUserInfoEndpoint: server.URL, OAuthUsernameClaim: tt.claim}, slog.Default())
+
+			_, err := handler.ValidateToken(context.Background(), "test-token")
+			assert.EqualError(t, err, "token validation failed")
+		})
+	}
+}
+
+func TestTokenCache(t *testing.T) {
+	cache := NewTokenCache(time.Minute, 2)
+
+	cache.Add("token-a", "alice")
+	cache.Add("token-b", "")
+
+	userID, ok := cache.Get("token-a")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", userID)
+
+	// Failed validations are cached as well.
+	userID, ok = cache.Get("token-b")
+	assert.True(t, ok)
+	assert.Empty(t, userID)
+
+	// A full cache starts over.
+	cache.Add("token-c", "carol")
+	_, ok = cache.Get("token-a")
+	assert.False(t, ok)
+	_, ok = cache.Get("token-c")
+	assert.True(t, ok)
+
+	expired := NewTokenCache(-time.Second, 2)
+	expired.Add("token-a", "alice")
+	_, ok = expired.Get("token-a")
+	assert.False(t, ok)
+
+	var disabled *TokenCache
+	disabled.Add("token-a", "alice")
+	_, ok = disabled.Get("token-a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// TokenCache remembers the users tokens were validated for, so a token sent repeatedly is not
+// validated against the user info endpoint every time. Tokens are only kept as SHA-256 hashes.
+// A nil cache remembers nothing.
+type TokenCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[[sha256.Size]byte]tokenCacheEntry
+	mutex      sync.Mutex
+}
+
+type tokenCacheEntry struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// NewTokenCache keeps entries for ttl and holds at most maxEntries of them.
+func NewTokenCache(ttl time.Duration, maxEntries int) *TokenCache {
+	return &TokenCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[[sha256.Size]byte]tokenCacheEntry{},
+	}
+}
+
+// Get returns the user cached for token, an empty user is cached for tokens which failed
+// validation.
+func (c *TokenCache) Get(token string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := sha256.Sum256([]byte(token))
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+
+	return entry.userID, true
+}
+
+func (c *TokenCache) Add(token string, userID string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.maxEntries {
+		for key, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	// Every entry is still valid, starting over is cheaper than tracking the oldest one.
+	if len(c.entries) >= c.maxEntries {
+		clear(c.entries)
+	}
+
+	c.entries[sha256.Sum256([]byte(token))] = tokenCacheEntry{userID: userID, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,231 @@
+// Package chunking splits document text into the chunks stored in vector databases.
+package chunking
+
+import (
+	"unicode/utf8"
+)
+
+// Strategies decide where chunks end. All of them keep chunks within the chunk size, falling
+// back to the next finer strategy for pieces which do not fit: Markdown sections and code blocks
+// are split into sentences or lines, sentences into token windows.
+const (
+	// FixedStrategy cuts token windows at word boundaries, as Llama Stack does.
+	FixedStrategy = "fixed"
+	// SentenceStrategy packs whole sentences, paragraphs always start a new sentence.
+	SentenceStrategy = "sentence"
+	// MarkdownStrategy starts a chunk at every heading and packs the paragraphs of a section,
+	// chunks carry the path of the headings they are under.
+	MarkdownStrategy = "markdown"
+	// CodeStrategy packs top-level blocks of source code, like functions, and never cuts a line.
+	CodeStrategy = "code"
+)
+
+var Strategies = []string{FixedStrategy, SentenceStrategy, MarkdownStrategy, CodeStrategy}
+
+const (
+	// DefaultChunkSizeInTokens matches the default chunk size of Llama Stack.
+	DefaultChunkSizeInTokens = 512
+
+	// CharsPerToken is used to estimate token counts, common tokenizers average about four
+	// characters per token of English text.
+	CharsPerToken = 4
+)
+
+// Options select how a document is chunked.
+type Options struct {
+	Strategy          string
+	ChunkSizeInTokens int
+	// The number of tokens at the end of a chunk repeated at the start of the next one, so text
+	// cut at a chunk boundary is found with its context. Strategies other than FixedStrategy
+	// only repeat whole sentences, lines or paragraphs.
+	ChunkOverlapInTokens int
+}
+
+// Chunk is a piece of a document with its position in the document.
+type Chunk struct {
+	Content string `json:"content"`
+	Index   int    `json:"index"`
+	// Character offsets into the document text, the end is exclusive.
+	StartOffset int `json:"start_offset"`
+	EndOffset   int `json:"end_offset"`
+	// Line numbers in the document text, starting at 1.
+	StartLine  int `json:"start_line"`
+	EndLine    int `json:"end_line"`
+	TokenCount int `json:"token_count"`
+	// The path of the Markdown headings the chunk is under, e.g. "Setup > Install".
+	Heading string `json:"heading,omitempty"`
+}
+
+// EstimateTokens estimates the number of tokens text is encoded to.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + CharsPerToken - 1) / CharsPerToken
+}
+
+// Split cuts text into chunks. The chunk size defaults to DefaultChunkSizeInTokens and the
+// overlap is kept below half of the chunk size, an unknown strategy is treated as FixedStrategy.
+func Split(text string, options Options) []Chunk {
+	if options.ChunkSizeInTokens <= 0 {
+		options.ChunkSizeInTokens = DefaultChunkSizeInTokens
+	}
+	options.ChunkOverlapInTokens = max(0, min(options.ChunkOverlapInTokens, options.ChunkSizeInTokens/2))
+
+	s := &splitter{
+		text:    text,
+		index:   newTextIndex(text),
+		size:    options.ChunkSizeInTokens,
+		overlap: options.ChunkOverlapInTokens,
+	}
+
+	var sections []section
+	whole := span{start: 0, end: len(text)}
+	switch options.Strategy {
+	case SentenceStrategy:
+		sections = []section{{spans: s.sentenceChunks(whole)}}
+	case MarkdownStrategy:
+		sections = s.markdownSections()
+	case CodeStrategy:
+		sections = []section{{spans: s.pack(s.codeBlocks(whole), s.codeLines)}}
+	default:
+		sections = []section{{spans: s.fixed(whole)}}
+	}
+
+	var chunks []Chunk
+	for _, section := range sections {
+		for _, span := range section.spans {
+			chunks = append(chunks, Chunk{
+				Content:     text[span.start:span.end],
+				Index:       len(chunks),
+				StartOffset: s.index.runeOffset(span.start),
+				EndOffset:   s.index.runeOffset(span.end),
+				StartLine:   s.index.line(span.start),
+				EndLine:     s.index.line(max(span.start, span.end-1)),
+				TokenCount:  s.tokens(span),
+				Heading:     section.heading,
+			})
+		}
+	}
+	return chunks
+}
+
+// span is a range of byte offsets into the text, the end is exclusive.
+type span struct {
+	start int
+	end   int
+}
+
+// section holds the chunks under a Markdown heading, other strategies have a single section.
+type section struct {
+	heading string
+	spans   []span
+}
+
+// Offsets are counted from checkpoints every textIndexInterval bytes.
+const textIndexInterval = 64
+
+// textIndex converts byte offsets into character offsets and line numbers.
+type textIndex struct {
+	text  string
+	runes []int
+	lines []int
+}
+
+func newTextIndex(text string) textIndex {
+	index := textIndex{text: text}
+	runes, lines := 0, 1
+	for i := 0; i <= len(text); i++ {
+		if i%textIndexInterval == 0 {
+			index.runes = append(index.runes, runes)
+			index.lines = append(index.lines, lines)
+		}
+		if i < len(text) {
+			if utf8.RuneStart(text[i]) {
+				runes++
+			}
+			if text[i] == '\n' {
+				lines++
+			}
+		}
+	}
+	return index
+}
+
+func (x textIndex) runeOffset(offset int) int {
+	checkpoint := offset / textIndexInterval
+	runes := x.runes[checkpoint]
+	for i := checkpoint * textIndexInterval; i < offset; i++ {
+		if utf8.RuneStart(x.text[i]) {
+			runes++
+		}
+	}
+	return runes
+}
+
+// line returns the line number of the byte at offset.
+func (x textIndex) line(offset int) int {
+	checkpoint := offset / textIndexInterval
+	lines := x.lines[checkpoint]
+	for i := checkpoint * textIndexInterval; i < offset; i++ {
+		if x.text[i] == '\n' {
+			lines++
+		}
+	}
+	return lines
+}
+
+type splitter struct {
+	text    string
+	index   textIndex
+	size    int
+	overlap int
+}
+
+func (s *splitter) tokens(span span) int {
+	runes := s.index.runeOffset(span.end) - s.index.runeOffset(span.start)
+	return (runes + CharsPerToken - 1) / CharsPerToken
+}
+
+// pack groups consecutive units into chunks of up to size tokens, units which are too large on
+// their own are cut by split. A chunk following a full one starts with its last units that fit
+// into the overlap.
+func (s *splitter) pack(units []span, split func(span) []span) []span {
+	var chunks []span
+	first := -1
+
+	flush := func(last int) {
+		if first >= 0 {
+			chunks = append(chunks, span{start: units[first].start, end: units[last].end})
+		}
+		first = -1
+	}
+
+	for i, unit := range units {
+		if s.tokens(unit) > s.size {
+			flush(i - 1)
+			chunks = append(chunks, split(unit)...)
+			continue
+		}
+		if first < 0 {
+			first = i
+			continue
+		}
+		if s.tokens(span{start: units[first].start, end: unit.end}) <= s.size {
+			continue
+		}
+
+		previousFirst := first
+		flush(i - 1)
+
+		// Repeat the last units of the full chunk, as long as they fit with unit.
+		first = i
+		for first-1 > previousFirst {
+			overlap := span{start: units[first-1].start, end: units[i-1].end}
+			if s.tokens(overlap) > s.overlap || s.tokens(span{start: overlap.start, end: unit.end}) > s.size {
+				break
+			}
+			first--
+		}
+	}
+	flush(len(units) - 1)
+
+	return chunks
+}
@@ -0,0 +1,131 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chunkContents(chunks []Chunk) []string {
+	contents := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		contents[i] = chunk.Content
+	}
+	return contents
+}
+
+func TestSplitFixed(t *testing.T) {
+	// Every word is two tokens with its trailing space.
+	text := "alpha bravo charlie delta echo foxtrot golf"
+
+	chunks := Split(text, Options{Strategy: FixedStrategy, ChunkSizeInTokens: 6, ChunkOverlapInTokens: 2})
+	assert.Equal(t, []string{
+		"alpha bravo charlie",
+		"charlie delta echo",
+		"echo foxtrot golf",
+	}, chunkContents(chunks))
+
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.Equal(t, chunk.Content, text[chunk.StartOffset:chunk.EndOffset])
+		assert.LessOrEqual(t, chunk.TokenCount, 6)
+	}
+
+	// Words longer than a chunk are cut.
+	chunks = Split(strings.Repeat("x", 10)+" end", Options{Strategy: FixedStrategy, ChunkSizeInTokens: 1})
+	assert.Equal(t, []string{"xxxx", "xxxx", "xx", "end"}, chunkContents(chunks))
+}
+
+func TestSplitSentence(t *testing.T) {
+	text := "Die Katze schläft. Der Hund bellt!\n\nNeuer Absatz ohne Punkt\nzweite Zeile. Ende?"
+
+	chunks := Split(text, Options{Strategy: SentenceStrategy, ChunkSizeInTokens: 11})
+	assert.Equal(t, []string{
+		"Die Katze schläft. Der Hund bellt!",
+		"Neuer Absatz ohne Punkt\nzweite Zeile. Ende?",
+	}, chunkContents(chunks))
+
+	// Offsets count characters, not bytes.
+	assert.Equal(t, 36, chunks[1].StartOffset)
+	assert.Equal(t, []rune(text)[chunks[1].StartOffset:chunks[1].EndOffset], []rune(chunks[1].Content))
+	assert.Equal(t, 3, chunks[1].StartLine)
+	assert.Equal(t, 4, chunks[1].EndLine)
+
+	// Whole sentences are repeated as overlap.
+	chunks = Split("One two three. Four five six. Seven eight nine.", Options{Strategy: SentenceStrategy, ChunkSizeInTokens: 8, ChunkOverlapInTokens: 4})
+	assert.Equal(t, []string{
+		"One two three. Four five six.",
+		"Four five six. Seven eight nine.",
+	}, chunkContents(chunks))
+}
+
+func TestSplitMarkdown(t *testing.T) {
+	text := `Intro text.
+
+# Guide
+
+## Install
+
+Run the installer.
+
+` + "```sh\n# not a heading\nmake install\n```" + `
+
+## Configure ##
+
+Edit the file.
+`
+
+	chunks := Split(text, Options{Strategy: MarkdownStrategy, ChunkSizeInTokens: 100})
+
+	assert.Equal(t, []string{
+		"Intro text.",
+		"## Install\n\nRun the installer.\n\n```sh\n# not a heading\nmake install\n```",
+		"## Configure ##\n\nEdit the file.",
+	}, chunkContents(chunks))
+
+	var headings []string
+	for _, chunk := range chunks {
+		headings = append(headings, chunk.Heading)
+	}
+	assert.Equal(t, []string{"", "Guide > Install", "Guide > Configure"}, headings)
+	assert.Equal(t, 5, chunks[1].StartLine)
+	assert.Equal(t, 12, chunks[1].EndLine)
+}
+
+func TestSplitCode(t *testing.T) {
+	text := `package main
+
+// add adds.
+func add(a, b int) int {
+	sum := a + b
+
+	return sum
+}
+
+func main() {
+	println(add(1, 2))
+}
+`
+
+	chunks := Split(text, Options{Strategy: CodeStrategy, ChunkSizeInTokens: 17})
+	assert.Equal(t, []string{
+		"package main",
+		"// add adds.\nfunc add(a, b int) int {\n\tsum := a + b\n\n\treturn sum\n}",
+		"func main() {\n\tprintln(add(1, 2))\n}",
+	}, chunkContents(chunks))
+
+	// Blocks too large for a chunk are cut at lines.
+	chunks = Split(text, Options{Strategy: CodeStrategy, ChunkSizeInTokens: 8})
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.TokenCount, 8, chunk.Content)
+		assert.NotContains(t, []string{"\t", "\n"}, chunk.Content[len(chunk.Content)-1:])
+	}
+	assert.Contains(t, chunkContents(chunks), "\tsum := a + b\n\n\treturn sum\n}")
+}
+
+func TestSplitEmpty(t *testing.T) {
+	for _, strategy := range Strategies {
+		assert.Empty(t, Split(" \n\n ", Options{Strategy: strategy}), strategy)
+	}
+}
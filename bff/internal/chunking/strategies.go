@@ -0,0 +1,274 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fixed cuts the words of within into windows of up to size tokens, consecutive windows share up to
+// overlap tokens of words.
+func (s *splitter) fixed(within span) []span {
+	words := s.words(within)
+
+	var chunks []span
+	for i := 0; i < len(words); {
+		if s.tokens(words[i]) > s.size {
+			// Words longer than a chunk, like encoded data, are cut anywhere.
+			chunks = append(chunks, s.cut(words[i])...)
+			i++
+			continue
+		}
+
+		last := i
+		for last+1 < len(words) && s.tokens(span{start: words[i].start, end: words[last+1].end}) <= s.size {
+			last++
+		}
+		chunks = append(chunks, span{start: words[i].start, end: words[last].end})
+		if last+1 == len(words) {
+			break
+		}
+
+		next := last + 1
+		for next-1 > i && s.tokens(span{start: words[next-1].start, end: words[last].end}) <= s.overlap {
+			next--
+		}
+		i = next
+	}
+	return chunks
+}
+
+// cut splits within into pieces of size tokens, regardless of words.
+func (s *splitter) cut(within span) []span {
+	var chunks []span
+	maxRunes := s.size * CharsPerToken
+
+	for start := within.start; start < within.end; {
+		end := start
+		for runes := 0; end < within.end && runes < maxRunes; runes++ {
+			_, width := utf8.DecodeRuneInString(s.text[end:])
+			end += width
+		}
+		chunks = append(chunks, span{start: start, end: end})
+		start = end
+	}
+	return chunks
+}
+
+// words returns the runs of non-space characters in within.
+func (s *splitter) words(within span) []span {
+	var words []span
+	start := -1
+	for i, r := range s.text[within.start:within.end] {
+		offset := within.start + i
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			words = append(words, span{start: start, end: offset})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = offset
+		}
+	}
+	if start >= 0 {
+		words = append(words, span{start: start, end: within.end})
+	}
+	return words
+}
+
+// trim shrinks within to exclude surrounding whitespace, it returns false when only whitespace
+// is left.
+func (s *splitter) trim(within span) (span, bool) {
+	text := s.text[within.start:within.end]
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	start := within.start + len(text) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	return span{start: start, end: start + len(trimmed)}, trimmed != ""
+}
+
+// A sentence ends with terminal punctuation, optionally followed by closing quotes or brackets,
+// and whitespace. Paragraph breaks end a sentence too.
+var sentenceEndRegexp = regexp.MustCompile(`[.!?。！？]+["'”’)\]]*\s+|\n[ \t]*\n\s*`)
+
+// sentences returns the sentences in within.
+func (s *splitter) sentences(within span) []span {
+	var sentences []span
+	text := s.text[within.start:within.end]
+
+	start := 0
+	for _, match := range sentenceEndRegexp.FindAllStringIndex(text, -1) {
+		if sentence, ok := s.trim(span{start: within.start + start, end: within.start + match[1]}); ok {
+			sentences = append(sentences, sentence)
+		}
+		start = match[1]
+	}
+	if sentence, ok := s.trim(span{start: within.start + start, end: within.end}); ok {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// sentenceChunks packs the sentences of within, for pieces too large for a chunk.
+func (s *splitter) sentenceChunks(within span) []span {
+	return s.pack(s.sentences(within), s.fixed)
+}
+
+var (
+	markdownHeadingRegexp = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	markdownFenceRegexp   = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+)
+
+// line is a line of the text without its line break.
+type line struct {
+	span
+	blank bool
+}
+
+func (s *splitter) lines(within span) []line {
+	var lines []line
+	for start := within.start; start < within.end; {
+		end := strings.IndexByte(s.text[start:within.end], '\n')
+		next := start + end + 1
+		if end < 0 {
+			end = within.end - start
+			next = within.end
+		}
+		content := s.text[start : start+end]
+		lines = append(lines, line{
+			span:  span{start: start, end: start + len(strings.TrimRight(content, "\r"))},
+			blank: strings.TrimSpace(content) == "",
+		})
+		start = next
+	}
+	return lines
+}
+
+// markdownSections splits the text at headings outside of fenced code blocks and packs the
+// blocks of every section. Sections holding nothing but their heading are dropped, the heading
+// is in the path of the following sections.
+func (s *splitter) markdownSections() []section {
+	var sections []section
+	var headings []string
+	var blocks []span
+	heading := ""
+	fence := ""
+	hasContent := false
+
+	flush := func() {
+		if hasContent {
+			sections = append(sections, section{heading: heading, spans: s.pack(blocks, s.markdownBlockChunks)})
+		}
+		blocks = nil
+		hasContent = false
+	}
+
+	// Blocks are separated by blank lines, fenced code blocks are kept whole.
+	block := span{start: -1}
+	addLine := func(line line) {
+		hasContent = true
+		if block.start < 0 {
+			block.start = line.start
+		}
+		block.end = line.end
+	}
+	endBlock := func() {
+		if block.start >= 0 {
+			blocks = append(blocks, block)
+		}
+		block = span{start: -1}
+	}
+
+	for _, line := range s.lines(span{start: 0, end: len(s.text)}) {
+		content := s.text[line.start:line.end]
+
+		if fence != "" {
+			addLine(line)
+			if strings.HasPrefix(strings.TrimSpace(content), fence) {
+				fence = ""
+				endBlock()
+			}
+			continue
+		}
+		if match := markdownFenceRegexp.FindStringSubmatch(content); match != nil {
+			endBlock()
+			addLine(line)
+			fence = match[1]
+			continue
+		}
+
+		if match := markdownHeadingRegexp.FindStringSubmatch(content); match != nil {
+			endBlock()
+			flush()
+
+			level := len(match[1])
+			headings = append(headings[:min(level-1, len(headings))], match[2])
+			heading = strings.Join(headings, " > ")
+
+			// The heading line starts the first chunk of its section.
+			blocks = append(blocks, line.span)
+			continue
+		}
+
+		if line.blank {
+			endBlock()
+			continue
+		}
+		addLine(line)
+	}
+	endBlock()
+	flush()
+
+	return sections
+}
+
+// markdownBlockChunks splits a block too large for a chunk, code blocks by lines and paragraphs
+// by sentences.
+func (s *splitter) markdownBlockChunks(block span) []span {
+	if markdownFenceRegexp.MatchString(s.text[block.start:block.end]) {
+		return s.codeLines(block)
+	}
+	return s.sentenceChunks(block)
+}
+
+// codeBlocks splits within into top-level blocks, a block starts at an unindented line after a
+// blank line. Closing brackets stay with the block they close.
+func (s *splitter) codeBlocks(within span) []span {
+	var blocks []span
+	block := span{start: -1}
+	previousBlank := false
+
+	for _, line := range s.lines(within) {
+		if line.blank {
+			previousBlank = true
+			continue
+		}
+
+		content := s.text[line.start:line.end]
+		startsBlock := previousBlank && !strings.HasPrefix(content, " ") && !strings.HasPrefix(content, "\t") &&
+			!strings.ContainsAny(content[:1], ")]}")
+		if startsBlock && block.start >= 0 {
+			blocks = append(blocks, block)
+			block = span{start: -1}
+		}
+		if block.start < 0 {
+			block.start = line.start
+		}
+		block.end = line.end
+		previousBlank = false
+	}
+	if block.start >= 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// codeLines packs the lines of a block too large for a chunk.
+func (s *splitter) codeLines(within span) []span {
+	var lines []span
+	for _, line := range s.lines(within) {
+		if !line.blank {
+			lines = append(lines, line.span)
+		}
+	}
+	return s.pack(lines, s.fixed)
+}
@@ -1,6 +1,10 @@
 package config
 
-import "log/slog"
+import (
+	"log/slog"
+	"net/netip"
+	"time"
+)
 
 type EnvConfig struct {
 	// General BFF configuration
@@ -9,10 +13,55 @@ type EnvConfig struct {
 	LogLevel        slog.Level
 	AllowedOrigins  []string
 	MockLSClient    bool
+	// TrustedProxies are the addresses of the proxies in front of the BFF, like the OpenShift
+	// router, whose X-Forwarded-For header is trusted to name the client.
+	TrustedProxies []netip.Prefix
 
 	// Llama Stack Configuration
 	LlamaStackURL string
 
+	// Chat Configuration
+	// RAGPromptTemplate is a text/template wrapping the last user message with the retrieved
+	// context, it is executed with the .Context and .Query fields.
+	RAGPromptTemplate string
+	// ModelProfilesPath is the JSON file ModelProfiles is loaded from at startup.
+	ModelProfilesPath string
+	// ModelProfiles holds the sampling defaults and allowed ranges per model.
+	ModelProfiles ModelProfiles
+
+	// Upload Configuration
+	// MaxUploadSize bounds the size in bytes of multipart uploads, DefaultMaxUploadSize applies
+	// when it is not positive.
+	MaxUploadSize int64
+
+	// Storage Configuration
+	// ConversationStorePath is the JSON file conversations are persisted to, when empty they
+	// are only kept in memory.
+	ConversationStorePath string
+	// PromptTemplateStorePath is the JSON file the prompt template library is persisted to,
+	// when empty templates are only kept in memory.
+	PromptTemplateStorePath string
+	// VectorDBOwnerStorePath is the JSON file the owners of vector databases are persisted to,
+	// when empty they are only kept in memory and nobody can unregister a vector database
+	// registered before a restart while OAuth is enabled.
+	VectorDBOwnerStorePath string
+
+	// Share Link Configuration
+	// ShareLinkSecret signs share link tokens, a random secret is generated when empty which
+	// invalidates all links on restart.
+	ShareLinkSecret string
+	// ShareLinkMaxTTL bounds how long a share link can stay valid.
+	ShareLinkMaxTTL time.Duration
+	// ShareLinkStorePath is the JSON file share links are persisted to, when empty they are
+	// only kept in memory.
+	ShareLinkStorePath string
+	// ShareLinkChatsPerMinute bounds the chats each share link can start per minute,
+	// DefaultShareLinkChatsPerMinute applies when it is not positive.
+	ShareLinkChatsPerMinute int
+	// ShareLinkResolvesPerMinute bounds how often each share link can be resolved per minute,
+	// DefaultShareLinkResolvesPerMinute applies when it is not positive.
+	ShareLinkResolvesPerMinute int
+
 	// OAuth Configuration
 	OAuthEnabled          bool
 	OAuthClientID         string
@@ -21,4 +70,24 @@ type EnvConfig struct {
 	OAuthServerURL        string
 	OpenShiftApiServerUrl string
 	OAuthUserInfoEndpoint string
+	// OAuthUsernameClaim is the dot separated path of the username in the user info response,
+	// metadata.name, preferred_username and sub are tried in order when empty.
+	OAuthUsernameClaim string
+	// AdminUsers may register and unregister models and register tool groups, which changes them
+	// for every user, and read the usage of every user. Only applies when OAuth is enabled,
+	// everyone is the anonymous user otherwise.
+	AdminUsers []string
 }
+
+const DefaultMaxUploadSize = 32 << 20
+
+const DefaultShareLinkChatsPerMinute = 20
+
+const DefaultShareLinkResolvesPerMinute = 60
+
+const DefaultRAGPromptTemplate = `Answer the question using the context below. If the context does not contain the answer, say that you do not know.
+
+Context:
+{{.Context}}
+
+Question: {{.Query}}`
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+// DefaultModelProfileKey selects the profile entry applied to every model, entries keyed by a
+// model identifier are applied on top of it.
+const DefaultModelProfileKey = "*"
+
+// ModelProfile holds the sampling defaults applied to chat requests for a model and the ranges
+// user supplied values must fall in.
+type ModelProfile struct {
+	Defaults models.SamplingParams      `json:"defaults"`
+	Ranges   models.SamplingParamRanges `json:"ranges"`
+	// ContextLength overrides the context length from the model metadata, in tokens. Zero
+	// keeps the metadata value.
+	ContextLength int `json:"context_length,omitempty"`
+}
+
+// ModelProfiles maps model identifiers, or DefaultModelProfileKey, to their profile.
+type ModelProfiles map[string]ModelProfile
+
+// BuiltinModelProfile bounds the parameters of models no profile file entry restricts further.
+var BuiltinModelProfile = ModelProfile{
+	Ranges: models.SamplingParamRanges{
+		Temperature:       &models.ParameterRange{Min: 0, Max: 2},
+		TopP:              &models.ParameterRange{Min: 0, Max: 1},
+		MaxTokens:         &models.ParameterRange{Min: 1, Max: 131072},
+		RepetitionPenalty: &models.ParameterRange{Min: 0, Max: 2},
+	},
+}
+
+// LoadModelProfiles reads the JSON model profile file at path, for example:
+//
+//	{
+//	  "*": {"ranges": {"max_tokens": {"min": 1, "max": 4096}}},
+//	  "meta-llama/Llama-3.2-3B-Instruct": {"defaults": {"temperature": 0.7, "top_p": 0.9}, "context_length": 8192}
+//	}
+func LoadModelProfiles(path string) (ModelProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model profiles: %w", err)
+	}
+
+	var profiles ModelProfiles
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode model profiles %s: %w", path, err)
+	}
+
+	for modelID, profile := range profiles {
+		if err := profile.validate(); err != nil {
+			return nil, fmt.Errorf("invalid model profile %q: %w", modelID, err)
+		}
+	}
+
+	return profiles, nil
+}
+
+// ProfileFor returns the profile of modelID, layering the model entry over the default entry
+// over BuiltinModelProfile one parameter at a time.
+func (p ModelProfiles) ProfileFor(modelID string) ModelProfile {
+	profile := BuiltinModelProfile
+	if defaultProfile, ok := p[DefaultModelProfileKey]; ok {
+		profile = profile.overlay(defaultProfile)
+	}
+	if modelProfile, ok := p[modelID]; ok {
+		profile = profile.overlay(modelProfile)
+	}
+	return profile
+}
+
+func (p ModelProfile) overlay(other ModelProfile) ModelProfile {
+	p.Defaults = p.Defaults.Merge(other.Defaults)
+
+	if other.Ranges.Temperature != nil {
+		p.Ranges.Temperature = other.Ranges.Temperature
+	}
+	if other.Ranges.TopP != nil {
+		p.Ranges.TopP = other.Ranges.TopP
+	}
+	if other.Ranges.MaxTokens != nil {
+		p.Ranges.MaxTokens = other.Ranges.MaxTokens
+	}
+	if other.Ranges.RepetitionPenalty != nil {
+		p.Ranges.RepetitionPenalty = other.Ranges.RepetitionPenalty
+	}
+	if other.ContextLength > 0 {
+		p.ContextLength = other.ContextLength
+	}
+
+	return p
+}
+
+func (p ModelProfile) validate() error {
+	if p.ContextLength < 0 {
+		return fmt.Errorf("context length %d must not be negative", p.ContextLength)
+	}
+
+	ranges := map[string]*models.ParameterRange{
+		"temperature":        p.Ranges.Temperature,
+		"top_p":              p.Ranges.TopP,
+		"max_tokens":         p.Ranges.MaxTokens,
+		"repetition_penalty": p.Ranges.RepetitionPenalty,
+	}
+	for name, r := range ranges {
+		if r != nil && r.Min > r.Max {
+			return fmt.Errorf("%s range minimum %g is greater than its maximum %g", name, r.Min, r.Max)
+		}
+	}
+	return nil
+}
@@ -12,4 +12,5 @@ const (
 
 	// OAuth related keys
 	AuthTokenKey contextKey = "AuthTokenKey"
+	UserIDKey    contextKey = "UserIDKey"
 )
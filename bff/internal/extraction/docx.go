@@ -0,0 +1,109 @@
+package extraction
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	wordprocessingNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	docxDocumentPart        = "word/document.xml"
+	// Bounds the decompressed size of the document part, the archive itself is bounded by the
+	// upload size.
+	maxDOCXDocumentSize = 64 << 20
+)
+
+// extractDOCX returns the text of the main document part of a Word document, one paragraph per
+// line with headings and list items written as Markdown. Headers, footers, footnotes and comments
+// are left out.
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("error reading DOCX archive: %w", err)
+	}
+
+	part, err := archive.Open(docxDocumentPart)
+	if err != nil {
+		return "", fmt.Errorf("error reading DOCX archive: %w", err)
+	}
+	defer part.Close()
+
+	var builder strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(part, maxDOCXDocumentSize))
+	// Text is only kept inside w:t elements, elsewhere it is formatting whitespace.
+	inText := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing DOCX document: %w", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if token.Name.Space != wordprocessingNamespace {
+				continue
+			}
+			switch token.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				builder.WriteByte('\t')
+			case "br", "cr":
+				builder.WriteByte('\n')
+			case "pStyle":
+				// Paragraph properties come before the runs, headings are written as Markdown.
+				builder.WriteString(docxHeadingPrefix(xmlAttr(token, "val")))
+			case "numPr":
+				builder.WriteString("- ")
+			}
+		case xml.EndElement:
+			if token.Name.Space != wordprocessingNamespace {
+				continue
+			}
+			switch token.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				builder.WriteString("\n\n")
+			case "tc":
+				builder.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inText {
+				builder.Write(token)
+			}
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// docxHeadingPrefix returns the Markdown heading prefix of the built-in heading paragraph styles.
+func docxHeadingPrefix(style string) string {
+	if style == "Title" {
+		return "# "
+	}
+	level, ok := strings.CutPrefix(style, "Heading")
+	if !ok || len(level) != 1 || level[0] < '1' || level[0] > '6' {
+		return ""
+	}
+	return strings.Repeat("#", int(level[0]-'0')) + " "
+}
+
+func xmlAttr(element xml.StartElement, local string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
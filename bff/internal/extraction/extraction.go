@@ -0,0 +1,128 @@
+// Package extraction extracts the text of uploaded documents so it can be inserted into vector
+// databases, which only take text content.
+package extraction
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	PlainTextMimeType = "text/plain"
+	MarkdownMimeType  = "text/markdown"
+	HTMLMimeType      = "text/html"
+	PDFMimeType       = "application/pdf"
+	DOCXMimeType      = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported document format")
+	ErrNoText            = errors.New("document contains no text")
+)
+
+// SupportedMimeTypes lists the mime types Extract can read.
+var SupportedMimeTypes = []string{PlainTextMimeType, MarkdownMimeType, HTMLMimeType, PDFMimeType, DOCXMimeType}
+
+var extensionMimeTypes = map[string]string{
+	".txt":      PlainTextMimeType,
+	".text":     PlainTextMimeType,
+	".md":       MarkdownMimeType,
+	".markdown": MarkdownMimeType,
+	".html":     HTMLMimeType,
+	".htm":      HTMLMimeType,
+	".pdf":      PDFMimeType,
+	".docx":     DOCXMimeType,
+}
+
+// DetectMimeType returns the mime type of a document from its filename extension, then from the
+// declared content type and last from its content. It returns an empty string for unsupported
+// documents.
+func DetectMimeType(filename string, contentType string, data []byte) string {
+	if mimeType, ok := extensionMimeTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+		return mimeType
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/x-markdown":
+			return MarkdownMimeType
+		case "application/xhtml+xml":
+			return HTMLMimeType
+		}
+		for _, mimeType := range SupportedMimeTypes {
+			if mediaType == mimeType {
+				return mimeType
+			}
+		}
+	}
+
+	// Browsers send application/octet-stream for extensions they do not know.
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	switch mediaType {
+	case PDFMimeType, HTMLMimeType, PlainTextMimeType:
+		return mediaType
+	}
+	return ""
+}
+
+// Extract returns the text of a document of the given mime type. Markdown is kept as is, HTML
+// headings and list items are turned into Markdown so the document structure survives.
+func Extract(mimeType string, data []byte) (string, error) {
+	var text string
+	var err error
+
+	switch mimeType {
+	case PlainTextMimeType, MarkdownMimeType:
+		text, err = decodeText(data)
+	case HTMLMimeType:
+		var decoded string
+		if decoded, err = decodeText(data); err == nil {
+			text = extractHTML(decoded)
+		}
+	case PDFMimeType:
+		text, err = extractPDF(data)
+	case DOCXMimeType:
+		text, err = extractDOCX(data)
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnsupportedFormat, mimeType)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = normalizeText(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+func decodeText(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return "", errors.New("document is not valid UTF-8 text")
+	}
+	return string(data), nil
+}
+
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+// normalizeText trims trailing whitespace off lines and collapses runs of blank lines.
+func normalizeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r\f\v ")
+	}
+
+	text = blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.Trim(text, "\n")
+}
@@ -0,0 +1,257 @@
+package extraction
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectMimeType(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		data        string
+		expected    string
+	}{
+		{filename: "notes.MD", expected: MarkdownMimeType},
+		{filename: "report.pdf", contentType: "application/octet-stream", expected: PDFMimeType},
+		{filename: "page", contentType: "text/html; charset=utf-8", expected: HTMLMimeType},
+		{filename: "scan", contentType: "application/octet-stream", data: "%PDF-1.7\n", expected: PDFMimeType},
+		{filename: "image.png", contentType: "image/png", data: "\x89PNG\r\n\x1a\n", expected: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, DetectMimeType(tt.filename, tt.contentType, []byte(tt.data)), tt.filename)
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	text, err := Extract(MarkdownMimeType, []byte("\xef\xbb\xbf# Title  \r\n\r\n\r\n\r\nBody\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n\nBody", text)
+
+	_, err = Extract(PlainTextMimeType, []byte("\xff\xfe"))
+	assert.Error(t, err)
+
+	_, err = Extract(PlainTextMimeType, []byte(" \n\n "))
+	assert.ErrorIs(t, err, ErrNoText)
+
+	_, err = Extract("image/png", nil)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+const testHTMLDocument = `<!DOCTYPE html>
+<html><head><title>Ignored</title><style>p { color: red; }</style></head>
+<body>
+  <!-- a comment -->
+  <h1 class="title">Install&nbsp;guide</h1>
+  <p>Run   <code>make</code>
+     then <b>deploy</b>, if a &lt; b.</p>
+  <script>if (a < b) { alert("</p>"); }</script>
+  <ul><li>One</li><li data-x="a > b">Two</li></ul>
+  <pre>line 1
+  line 2</pre>
+  <table><tr><td>A</td><td>B</td></tr></table>
+</body></html>`
+
+func TestExtractHTML(t *testing.T) {
+	text, err := Extract(HTMLMimeType, []byte(testHTMLDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, "# Install guide\n\nRun make then deploy, if a < b.\n\n- One\n\n- Two\n\nline 1\n  line 2\n\nA B", text)
+}
+
+func buildTestDOCX(t testing.TB, document string) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	writer, err := archive.Create(docxDocumentPart)
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte(document))
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Close())
+
+	return buffer.Bytes()
+}
+
+const testDOCXDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body>
+    <w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Overview</w:t></w:r></w:p>
+    <w:p><w:r><w:t xml:space="preserve">Split </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>runs</w:t></w:r><w:r><w:tab/><w:t>&amp; tabs</w:t></w:r></w:p>
+    <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>Item</w:t></w:r></w:p>
+  </w:body>
+</w:document>`
+
+func TestExtractDOCX(t *testing.T) {
+	text, err := Extract(DOCXMimeType, buildTestDOCX(t, testDOCXDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, "## Overview\n\nSplit runs\t& tabs\n\n- Item", text)
+
+	_, err = Extract(DOCXMimeType, []byte("not a zip"))
+	assert.Error(t, err)
+}
+
+// testPDFStream returns a FlateDecode stream object with the entries dict and data.
+func testPDFStream(t testing.TB, dict string, data string) string {
+	var buffer bytes.Buffer
+	writer := zlib.NewWriter(&buffer)
+	_, err := writer.Write([]byte(data))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return fmt.Sprintf("<<%s /Filter /FlateDecode /Length %d>>\nstream\n%s\nendstream", dict, buffer.Len(), buffer.String())
+}
+
+// writeTestPDF writes a PDF document with the given objects numbered from 1, the first one is
+// the catalog.
+func writeTestPDF(objects ...string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	for i, object := range objects {
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<</Size %d /Root 1 0 R>>\n%%%%EOF\n", len(objects)+1)
+	return buffer.Bytes()
+}
+
+// buildTestPDF writes a PDF document with a page per content stream. Font F1 is a simple font
+// without ToUnicode map, font F2 a composite font whose codes 0001 to 0003 map to é, A and B.
+func buildTestPDF(t testing.TB, contents ...string) []byte {
+
+	cmap := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"1 beginbfchar <0001> <00E9> endbfchar\n" +
+		"1 beginbfrange <0002> <0003> <0041> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+
+	kids := make([]string, len(contents))
+	for i := range contents {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+
+	objects := []string{
+		"<</Type /Catalog /Pages 2 0 R>>",
+		fmt.Sprintf("<</Type /Pages /Kids [%s] /Count %d /Resources <</Font <</F1 3 0 R /F2 4 0 R>>>>>>", strings.Join(kids, " "), len(contents)),
+		"<</Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding>>",
+		"<</Type /Font /Subtype /Type0 /BaseFont /Subset /Encoding /Identity-H /ToUnicode 5 0 R>>",
+		testPDFStream(t, "", cmap),
+	}
+	for i, content := range contents {
+		objects = append(objects,
+			fmt.Sprintf("<</Type /Page /Parent 2 0 R /Contents %d 0 R>>", 7+2*i),
+			testPDFStream(t, "", content))
+	}
+
+	return writeTestPDF(objects...)
+}
+
+// testPDFContents are the content streams of the pages of the test PDF document.
+var testPDFContents = []string{
+	`BT /F1 12 Tf 72 720 Td (Hello \(PDF\) world, it\222s) Tj 0 -14 Td [(Ker) -50 (ning) -300 (works)] TJ ET`,
+	`BT /F2 12 Tf 1 0 0 1 72 700 Tm <000100020003> Tj 1 0 0 1 72 686 Tm <0004> Tj ET
+BI /W 1 /H 1 /BPC 8 /CS /G ID ` + "\xffEI\xff" + ` EI`,
+}
+
+func TestExtractPDF(t *testing.T) {
+	text, err := Extract(PDFMimeType, buildTestPDF(t, testPDFContents...))
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello (PDF) world, it’s\nKerning works\n\néAB", text)
+}
+
+func TestExtractPDFInvalid(t *testing.T) {
+	_, err := Extract(PDFMimeType, []byte("plain text"))
+	assert.Error(t, err)
+
+	// Pages without text, like scans, have nothing to extract.
+	_, err = Extract(PDFMimeType, buildTestPDF(t, "0 0 100 100 re f"))
+	assert.ErrorIs(t, err, ErrNoText)
+}
+
+func TestExtractPDFRepeatedReferences(t *testing.T) {
+	// A single stream decoding to 32 MiB, listed many times in the contents of one page.
+	content := testPDFStream(t, "", "BT /F1 12 Tf (x) Tj ET"+strings.Repeat(" ", 32<<20))
+	contents := strings.TrimSpace(strings.Repeat("4 0 R ", 100))
+
+	document := writeTestPDF(
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Kids [3 0 R] /Count 1>>",
+		fmt.Sprintf("<</Type /Page /Parent 2 0 R /Contents [%s]>>", contents),
+		content,
+	)
+
+	_, err := Extract(PDFMimeType, document)
+	assert.ErrorIs(t, err, errPDFTooLarge)
+
+	// The stream is decoded once however often it is referenced.
+	parsed, err := parsePDFDocument(document)
+	assert.NoError(t, err)
+	extractor := &pdfTextExtractor{document: parsed, fonts: map[any]*pdfFont{}}
+	extractor.page(parsed.pages()[0])
+	assert.ErrorIs(t, extractor.err, errPDFTooLarge)
+	assert.Equal(t, 32<<20+len("BT /F1 12 Tf (x) Tj ET"), parsed.decodedSize)
+
+	// A form running itself twice on every nesting level.
+	document = writeTestPDF(
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Kids [3 0 R] /Count 1>>",
+		"<</Type /Page /Parent 2 0 R /Resources <</XObject <</Fm0 5 0 R>>>> /Contents 4 0 R>>",
+		testPDFStream(t, "", "/Fm0 Do"),
+		testPDFStream(t, "/Type /XObject /Subtype /Form /Resources <</XObject <</Fm0 5 0 R>>>>", "/Fm0 Do /Fm0 Do"),
+	)
+
+	_, err = Extract(PDFMimeType, document)
+	assert.ErrorIs(t, err, errPDFTooLarge)
+}
+
+func TestExtractHTMLMalformed(t *testing.T) {
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{document: "<p>unclosed <b>bold", expected: "unclosed bold"},
+		{document: "a < b and c <> d", expected: "a < b and c <> d"},
+		{document: "<p>one<p>two", expected: "one\n\ntwo"},
+		{document: "<script>x = '<p>'</script>shown<style>", expected: "shown"},
+		{document: "<h2>Title<br/>part</h2>", expected: "## Title\n\npart"},
+	}
+
+	for _, tt := range tests {
+		text, err := Extract(HTMLMimeType, []byte(tt.document))
+		assert.NoError(t, err, tt.document)
+		assert.Equal(t, tt.expected, text, tt.document)
+	}
+}
+
+// The fuzz tests only check that any input is rejected or extracted without panicking.
+
+func FuzzExtractHTML(f *testing.F) {
+	f.Add(testHTMLDocument)
+	f.Add("<p>unclosed <b>bold")
+	f.Add("<pre><pre></pre>")
+
+	f.Fuzz(func(t *testing.T, document string) {
+		_, _ = Extract(HTMLMimeType, []byte(document))
+	})
+}
+
+func FuzzExtractPDF(f *testing.F) {
+	f.Add(buildTestPDF(f, testPDFContents...))
+	f.Add(buildTestPDF(f, "0 0 100 100 re f"))
+	f.Add([]byte("%PDF-1.4\ntrailer\n<</Root 1 0 R>>\n%%EOF\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = Extract(PDFMimeType, data)
+	})
+}
+
+func FuzzExtractDOCX(f *testing.F) {
+	f.Add(buildTestDOCX(f, testDOCXDocument))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = Extract(DOCXMimeType, data)
+	})
+}
@@ -0,0 +1,152 @@
+package extraction
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Elements whose content is not document text.
+var skippedHTMLElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"title":    true,
+	"noscript": true,
+	"template": true,
+	"svg":      true,
+}
+
+// Elements which start on a new line.
+var blockHTMLElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "header": true, "hr": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "tr": true, "ul": true,
+}
+
+var headingHTMLElements = map[string]string{
+	"h1": "# ", "h2": "## ", "h3": "### ", "h4": "#### ", "h5": "##### ", "h6": "###### ",
+}
+
+// htmlTextWriter collects the text of an HTML document, collapsing whitespace outside of pre
+// elements.
+type htmlTextWriter struct {
+	builder  strings.Builder
+	preDepth int
+	// Whether whitespace was seen since the last text, it is written before the next text.
+	pendingSpace bool
+}
+
+func (w *htmlTextWriter) text(text string) {
+	if text == "" {
+		return
+	}
+	if w.preDepth > 0 {
+		w.builder.WriteString(text)
+		return
+	}
+
+	for i, field := range strings.FieldsFunc(text, isHTMLSpace) {
+		if i > 0 || w.pendingSpace || isHTMLSpace(rune(text[0])) {
+			w.space()
+		}
+		w.builder.WriteString(field)
+		w.pendingSpace = false
+	}
+	if isHTMLSpace(rune(text[len(text)-1])) {
+		w.pendingSpace = true
+	}
+}
+
+// space writes a space unless the text is at the start of a line.
+func (w *htmlTextWriter) space() {
+	if w.builder.Len() == 0 {
+		return
+	}
+	if last := w.builder.String()[w.builder.Len()-1]; last != '\n' && last != ' ' {
+		w.builder.WriteByte(' ')
+	}
+}
+
+func (w *htmlTextWriter) newline(prefix string) {
+	if w.builder.Len() > 0 {
+		w.builder.WriteString("\n\n")
+	}
+	w.builder.WriteString(prefix)
+	w.pendingSpace = false
+}
+
+func isHTMLSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+// extractHTML returns the text of an HTML document, headings and list items are written as
+// Markdown. Malformed markup is read the way browsers tokenize it.
+func extractHTML(document string) string {
+	writer := &htmlTextWriter{}
+	tokenizer := html.NewTokenizer(strings.NewReader(document))
+	// Depth of the skipped elements the tokenizer is in, their content is not text.
+	skipDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// The only error reading from a string is io.EOF.
+			return writer.builder.String()
+		}
+
+		token := tokenizer.Token()
+		name := token.Data
+
+		switch tokenType {
+		case html.TextToken:
+			if skipDepth == 0 {
+				writer.text(token.Data)
+			}
+			continue
+		case html.StartTagToken:
+			if skippedHTMLElements[name] {
+				skipDepth++
+				continue
+			}
+		case html.EndTagToken:
+			if skippedHTMLElements[name] {
+				skipDepth = max(skipDepth-1, 0)
+				continue
+			}
+		case html.SelfClosingTagToken:
+			if skippedHTMLElements[name] {
+				continue
+			}
+		default:
+			// Comments and doctypes.
+			continue
+		}
+		if skipDepth > 0 {
+			continue
+		}
+
+		closing := tokenType == html.EndTagToken
+		switch {
+		case name == "pre":
+			writer.newline("")
+			if closing {
+				writer.preDepth = max(writer.preDepth-1, 0)
+			} else if tokenType == html.StartTagToken {
+				writer.preDepth++
+			}
+		case headingHTMLElements[name] != "":
+			if closing {
+				writer.newline("")
+			} else {
+				writer.newline(headingHTMLElements[name])
+			}
+		case name == "li" && !closing:
+			writer.newline("- ")
+		case blockHTMLElements[name]:
+			writer.newline("")
+		case name == "td" || name == "th":
+			writer.pendingSpace = true
+		}
+	}
+}
@@ -0,0 +1,548 @@
+package extraction
+
+import (
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// extractPDF returns the text of the pages of a PDF document, pages are separated by blank
+// lines. Text is mapped to Unicode with the ToUnicode maps of the fonts, simple fonts without
+// one are read as WinAnsiEncoding. Scanned pages have no text, there is no OCR.
+//
+// The parser only reads what text extraction needs. The Go PDF readers are either unmaintained
+// forks of rsc.io/pdf, AGPL licensed or built to edit documents, FuzzExtractPDF covers this one.
+func extractPDF(data []byte) (string, error) {
+	document, err := parsePDFDocument(data)
+	if err != nil {
+		return "", err
+	}
+
+	pages := document.pages()
+	if len(pages) == 0 {
+		return "", errors.New("PDF document has no pages")
+	}
+
+	extractor := &pdfTextExtractor{document: document, fonts: map[any]*pdfFont{}}
+	for _, page := range pages {
+		extractor.page(page)
+		if extractor.err != nil {
+			return "", extractor.err
+		}
+	}
+	return extractor.builder.String(), nil
+}
+
+// pdfPage is a page with the resources it inherits from the page tree.
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the pages of the document in order.
+func (d *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+	visited := map[int]bool{}
+
+	var walk func(node any, resources pdfDict, depth int)
+	walk = func(node any, resources pdfDict, depth int) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.number] {
+				return
+			}
+			visited[ref.number] = true
+		}
+		dict := d.dict(node)
+		if dict == nil || depth > maxPDFDepth {
+			return
+		}
+		if nodeResources := d.dict(dict["Resources"]); nodeResources != nil {
+			resources = nodeResources
+		}
+
+		kids, ok := d.resolve(dict["Kids"]).(pdfArray)
+		if !ok {
+			pages = append(pages, pdfPage{dict: dict, resources: resources})
+			return
+		}
+		for _, kid := range kids {
+			walk(kid, resources, depth+1)
+		}
+	}
+
+	walk(d.dict(d.catalog()["Pages"]), nil, 0)
+	return pages
+}
+
+// catalog returns the document catalog, the root of the page tree.
+func (d *pdfDocument) catalog() pdfDict {
+	if root, ok := d.trailerValue("Root"); ok {
+		if catalog := d.dict(root); catalog != nil {
+			return catalog
+		}
+	}
+
+	// Damaged files may lack a trailer, the catalog with the lowest object number is used then.
+	numbers := make([]int, 0, len(d.objects))
+	for number := range d.objects {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		if dict, ok := d.objects[number].(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			return dict
+		}
+	}
+	return nil
+}
+
+// pdfFont maps the character codes of a font to text.
+type pdfFont struct {
+	toUnicode map[string]string
+	// The code lengths in bytes of the ToUnicode map, shortest first.
+	codeLengths []int
+	// Composite fonts use multi-byte codes, without a ToUnicode map they cannot be read.
+	composite bool
+}
+
+func (f *pdfFont) decode(text pdfString) string {
+	if f.toUnicode == nil {
+		if f.composite {
+			return ""
+		}
+		return decodeWinAnsi(text)
+	}
+
+	var builder strings.Builder
+	for len(text) > 0 {
+		length := 0
+		for _, codeLength := range f.codeLengths {
+			if codeLength <= len(text) {
+				if mapped, ok := f.toUnicode[string(text[:codeLength])]; ok {
+					builder.WriteString(mapped)
+					length = codeLength
+					break
+				}
+			}
+		}
+		if length == 0 {
+			// Unmapped codes of composite fonts are dropped, a guess would be garbage.
+			length = min(f.codeLengths[0], len(text))
+			if !f.composite && length == 1 {
+				builder.WriteString(decodeWinAnsi(text[:1]))
+			}
+		}
+		text = text[length:]
+	}
+	return builder.String()
+}
+
+// winAnsiSpecials maps the WinAnsiEncoding codes which differ from Latin-1.
+var winAnsiSpecials = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ', 0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›',
+	0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+}
+
+func decodeWinAnsi(text pdfString) string {
+	runes := make([]rune, 0, len(text))
+	for _, b := range text {
+		if special, ok := winAnsiSpecials[b]; ok {
+			runes = append(runes, special)
+		} else if b >= 0x20 || b == '\t' || b == '\n' {
+			runes = append(runes, rune(b))
+		}
+	}
+	return string(runes)
+}
+
+// pdfFontKey identifies direct font dictionaries, which are not comparable, by what is read
+// from them. Streams are always indirect objects, so the ToUnicode map is.
+type pdfFontKey struct {
+	toUnicode *pdfStream
+	composite bool
+}
+
+// font returns the font for a font dictionary, fonts are cached so every ToUnicode map is parsed
+// once.
+func (e *pdfTextExtractor) font(object any) *pdfFont {
+	dict := e.document.dict(object)
+	toUnicode, _ := e.document.resolve(dict["ToUnicode"]).(*pdfStream)
+	composite := dict["Subtype"] == pdfName("Type0")
+
+	key := object
+	if _, ok := object.(pdfRef); !ok {
+		key = pdfFontKey{toUnicode: toUnicode, composite: composite}
+	}
+	if font, ok := e.fonts[key]; ok {
+		return font
+	}
+
+	font := &pdfFont{composite: composite}
+	if toUnicode != nil {
+		if data, ok := e.decode(toUnicode); ok {
+			font.toUnicode, font.codeLengths = parseToUnicodeCMap(data)
+		}
+	}
+	e.fonts[key] = font
+	return font
+}
+
+// parseToUnicodeCMap reads the bfchar and bfrange mappings of a ToUnicode CMap, it returns a nil
+// map when there are none.
+func parseToUnicodeCMap(data []byte) (map[string]string, []int) {
+	mapping := map[string]string{}
+	lengths := map[int]bool{}
+	lexer := &pdfLexer{data: data}
+
+	var operands []any
+	for {
+		object, err := lexer.readObject(0)
+		if err != nil {
+			break
+		}
+		keyword, ok := object.(pdfKeyword)
+		if !ok {
+			operands = append(operands, object)
+			continue
+		}
+
+		switch keyword {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				source, ok1 := operands[i].(pdfString)
+				target, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 && len(source) > 0 && len(target) <= maxPDFCMapTargetSize {
+					mapping[string(source)] = decodeUTF16(target)
+					lengths[len(source)] = true
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(low) == 0 || len(low) != len(high) {
+					continue
+				}
+				lengths[len(low)] = true
+				mapPDFRange(mapping, low, high, operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+
+	if len(mapping) == 0 {
+		return nil, nil
+	}
+	codeLengths := make([]int, 0, len(lengths))
+	for length := range lengths {
+		codeLengths = append(codeLengths, length)
+	}
+	sort.Ints(codeLengths)
+	return mapping, codeLengths
+}
+
+const (
+	// Bounds the codes a ToUnicode map maps, two byte codes need at most 1 << 16.
+	maxPDFCMapSize = 1 << 16
+	// Bounds the UTF-16 text a single code maps to, ligatures need a few characters.
+	maxPDFCMapTargetSize = 64
+)
+
+// mapPDFRange maps the codes low to high either to consecutive characters starting with target
+// or to the characters of a target array.
+func mapPDFRange(mapping map[string]string, low pdfString, high pdfString, target any) {
+	start := codeValue(low)
+	end := codeValue(high)
+	if end < start || end-start >= maxPDFCMapSize {
+		return
+	}
+
+	for offset := 0; offset <= end-start && len(mapping) < maxPDFCMapSize; offset++ {
+		code := codeBytes(start+offset, len(low))
+		switch target := target.(type) {
+		case pdfString:
+			text := []rune(decodeUTF16(target))
+			if len(text) == 0 || len(target) > maxPDFCMapTargetSize {
+				return
+			}
+			// Only the last character is incremented, as for ligatures like "ffi".
+			text[len(text)-1] += rune(offset)
+			mapping[string(code)] = string(text)
+		case pdfArray:
+			if offset < len(target) {
+				if text, ok := target[offset].(pdfString); ok && len(text) <= maxPDFCMapTargetSize {
+					mapping[string(code)] = decodeUTF16(text)
+				}
+			}
+		}
+	}
+}
+
+func codeValue(code pdfString) int {
+	value := 0
+	for _, b := range code {
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+func codeBytes(value int, length int) []byte {
+	code := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = byte(value)
+		value >>= 8
+	}
+	return code
+}
+
+func decodeUTF16(text pdfString) string {
+	units := make([]uint16, 0, len(text)/2)
+	for i := 0; i+1 < len(text); i += 2 {
+		units = append(units, uint16(text[i])<<8|uint16(text[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+const (
+	// Bounds the content run for a document. Content streams count every time they are run, so
+	// referencing a stream many times does not multiply the work.
+	maxPDFContentSize = 256 << 20
+	// Bounds the form XObjects run for a document, a form can run itself on every nesting level.
+	maxPDFFormRuns = 10_000
+	// Bounds the extracted text.
+	maxPDFTextSize = 64 << 20
+)
+
+// pdfTextExtractor runs page content streams, writing the text they show.
+type pdfTextExtractor struct {
+	document *pdfDocument
+	fonts    map[any]*pdfFont
+	builder  strings.Builder
+	// The separator written before the next text, a space or a newline.
+	separator string
+	// The vertical position of the last text matrix, to tell new lines from moves on a line.
+	lineY float64
+	// The work done so far against the limits above, err stops the extraction once one is hit.
+	contentSize int
+	formRuns    int
+	err         error
+}
+
+// decode decodes stream, a failure only skips the stream unless the document is too large.
+func (e *pdfTextExtractor) decode(stream *pdfStream) ([]byte, bool) {
+	data, err := e.document.decodeStream(stream)
+	if errors.Is(err, errPDFTooLarge) {
+		e.err = err
+	}
+	return data, err == nil
+}
+
+// addContent counts content about to be run, it reports false once there is too much.
+func (e *pdfTextExtractor) addContent(size int) bool {
+	e.contentSize += size
+	if e.contentSize > maxPDFContentSize {
+		e.err = errPDFTooLarge
+		return false
+	}
+	return true
+}
+
+func (e *pdfTextExtractor) page(page pdfPage) {
+	if e.builder.Len() > 0 {
+		e.builder.WriteString("\n\n")
+	}
+	e.separator = ""
+
+	var content []byte
+	contents := e.document.resolve(page.dict["Contents"])
+	streams, ok := contents.(pdfArray)
+	if !ok {
+		streams = pdfArray{contents}
+	}
+	// A page may split its content over several streams, the split can be anywhere.
+	for _, stream := range streams {
+		if stream, ok := e.document.resolve(stream).(*pdfStream); ok {
+			if data, ok := e.decode(stream); ok && e.addContent(len(data)) {
+				content = append(append(content, data...), '\n')
+			}
+		}
+		if e.err != nil {
+			return
+		}
+	}
+
+	e.run(content, page.resources, 0)
+}
+
+// run interprets the text operators of a content stream.
+func (e *pdfTextExtractor) run(content []byte, resources pdfDict, depth int) {
+	if depth > maxPDFDepth {
+		return
+	}
+
+	fonts := e.document.dict(resources["Font"])
+	font := &pdfFont{}
+	lexer := &pdfLexer{data: content}
+	var operands []any
+
+	for e.err == nil {
+		object, err := lexer.readObject(0)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			// Skip the rest of a broken content stream, what was read is kept.
+			return
+		}
+		operator, ok := object.(pdfKeyword)
+		if !ok {
+			operands = append(operands, object)
+			continue
+		}
+
+		switch operator {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok && fonts != nil {
+					font = e.font(fonts[name])
+				}
+			}
+		case "Tj":
+			e.show(font, operands)
+		case "'", "\"":
+			e.newline()
+			e.show(font, operands)
+		case "TJ":
+			if len(operands) > 0 {
+				if array, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range array {
+						switch item := item.(type) {
+						case pdfString:
+							e.write(font.decode(item))
+						case float64:
+							// Large negative adjustments, in thousandths of an em, are word gaps.
+							if item < -200 {
+								e.space()
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[len(operands)-1].(float64); ok && math.Abs(ty) > 0.1 {
+					e.newline()
+				} else {
+					e.space()
+				}
+			}
+		case "T*":
+			e.newline()
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[len(operands)-1].(float64); ok {
+					if math.Abs(y-e.lineY) > 0.1 {
+						e.newline()
+					} else {
+						e.space()
+					}
+					e.lineY = y
+				}
+			}
+		case "Do":
+			if len(operands) > 0 {
+				if name, ok := operands[len(operands)-1].(pdfName); ok {
+					e.runForm(e.document.dict(resources["XObject"]), name, resources, depth)
+				}
+			}
+		case "BI":
+			lexer.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// runForm runs the content of a form XObject, images are ignored.
+func (e *pdfTextExtractor) runForm(xObjects pdfDict, name pdfName, resources pdfDict, depth int) {
+	if xObjects == nil {
+		return
+	}
+	stream, ok := e.document.resolve(xObjects[name]).(*pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	e.formRuns++
+	if e.formRuns > maxPDFFormRuns {
+		e.err = errPDFTooLarge
+		return
+	}
+	data, ok := e.decode(stream)
+	if !ok || !e.addContent(len(data)) {
+		return
+	}
+	if formResources := e.document.dict(stream.dict["Resources"]); formResources != nil {
+		resources = formResources
+	}
+	e.run(data, resources, depth+1)
+}
+
+// skipInlineImage skips the data of an inline image, up to its EI operator.
+func (l *pdfLexer) skipInlineImage() {
+	for l.pos+2 < len(l.data) {
+		if l.data[l.pos] == 'I' && l.data[l.pos+1] == 'D' && isPDFSpace(l.data[l.pos+2]) {
+			break
+		}
+		l.pos++
+	}
+	for l.pos+2 < len(l.data) {
+		if isPDFSpace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 == len(l.data) || isPDFSpace(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+func (e *pdfTextExtractor) show(font *pdfFont, operands []any) {
+	if len(operands) == 0 {
+		return
+	}
+	if text, ok := operands[len(operands)-1].(pdfString); ok {
+		e.write(font.decode(text))
+	}
+}
+
+func (e *pdfTextExtractor) write(text string) {
+	if text == "" {
+		return
+	}
+	if e.builder.Len()+len(text) > maxPDFTextSize {
+		e.err = errPDFTooLarge
+		return
+	}
+	if e.separator != "" && e.builder.Len() > 0 {
+		current := e.builder.String()
+		if last := current[len(current)-1]; last != '\n' && !(e.separator == " " && last == ' ') && !strings.HasPrefix(text, e.separator) {
+			e.builder.WriteString(e.separator)
+		}
+	}
+	e.separator = ""
+	e.builder.WriteString(text)
+}
+
+func (e *pdfTextExtractor) space() {
+	if e.separator == "" {
+		e.separator = " "
+	}
+}
+
+func (e *pdfTextExtractor) newline() {
+	e.separator = "\n"
+}
@@ -0,0 +1,578 @@
+package extraction
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// The PDF object types, numbers are float64, booleans bool and null nil.
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfArray   []any
+	pdfDict    map[pdfName]any
+	pdfKeyword string
+	pdfRef     struct{ number, generation int }
+)
+
+// pdfStream is a stream object, its data is still encoded.
+type pdfStream struct {
+	dict pdfDict
+	data []byte
+}
+
+const (
+	// Bounds the decoded size of a single stream against decompression bombs.
+	maxPDFStreamSize = 64 << 20
+	// Bounds the decoded size of all streams of a document, a stream referenced several times
+	// is decoded and counted once.
+	maxPDFDecodedSize = 256 << 20
+	// Bounds nesting of arrays, dictionaries, references and form XObjects.
+	maxPDFDepth = 32
+)
+
+var (
+	errPDFSyntax   = errors.New("invalid PDF syntax")
+	errPDFTooLarge = errors.New("PDF document is too large to extract")
+)
+
+// pdfLexer reads PDF objects and content stream operators.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(b byte) bool {
+	return b == 0 || b == '\t' || b == '\n' || b == '\f' || b == '\r' || b == ' '
+}
+
+func isPDFDelimiter(b byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), b) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch {
+		case isPDFSpace(l.data[l.pos]):
+			l.pos++
+		case l.data[l.pos] == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// readRegular reads a run of regular characters, the body of names, numbers and keywords.
+func (l *pdfLexer) readRegular() []byte {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return l.data[start:l.pos]
+}
+
+// readObject reads the next object, io.EOF at the end of the data. Operators and the closing
+// delimiters ] and >> are returned as keywords.
+func (l *pdfLexer) readObject(depth int) (any, error) {
+	if depth > maxPDFDepth {
+		return nil, fmt.Errorf("%w: objects nested too deeply", errPDFSyntax)
+	}
+
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	switch b := l.data[l.pos]; {
+	case b == '/':
+		l.pos++
+		return pdfName(decodePDFName(l.readRegular())), nil
+	case b == '(':
+		l.pos++
+		return l.readLiteralString(), nil
+	case bytes.HasPrefix(l.data[l.pos:], []byte("<<")):
+		l.pos += 2
+		return l.readDict(depth)
+	case b == '<':
+		l.pos++
+		return l.readHexString(), nil
+	case b == '[':
+		l.pos++
+		return l.readArray(depth)
+	case bytes.HasPrefix(l.data[l.pos:], []byte(">>")):
+		l.pos += 2
+		return pdfKeyword(">>"), nil
+	case b == ']' || b == '{' || b == '}' || b == ')' || b == '>':
+		l.pos++
+		return pdfKeyword(l.data[l.pos-1 : l.pos]), nil
+	case b == '+' || b == '-' || b == '.' || b >= '0' && b <= '9':
+		return l.readNumberOrRef()
+	}
+
+	word := l.readRegular()
+	switch string(word) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+func (l *pdfLexer) readNumberOrRef() (any, error) {
+	word := l.readRegular()
+	number, err := strconv.ParseFloat(string(word), 64)
+	if err != nil {
+		// Keep going, some producers write malformed numbers like 0.-5.
+		return 0.0, nil
+	}
+
+	// An indirect reference is two integers followed by R.
+	if isPDFInteger(word) {
+		start := l.pos
+		l.skipSpace()
+		generation := l.readRegular()
+		l.skipSpace()
+		if isPDFInteger(generation) && l.pos < len(l.data) && l.data[l.pos] == 'R' &&
+			(l.pos+1 == len(l.data) || isPDFSpace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			generationNumber, _ := strconv.Atoi(string(generation))
+			return pdfRef{number: int(number), generation: generationNumber}, nil
+		}
+		l.pos = start
+	}
+	return number, nil
+}
+
+func isPDFInteger(word []byte) bool {
+	if len(word) == 0 {
+		return false
+	}
+	for _, b := range word {
+		if b < '0' || b > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func decodePDFName(name []byte) string {
+	if bytes.IndexByte(name, '#') < 0 {
+		return string(name)
+	}
+	var decoded []byte
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) {
+			if value, err := strconv.ParseUint(string(name[i+1:i+3]), 16, 8); err == nil {
+				decoded = append(decoded, byte(value))
+				i += 2
+				continue
+			}
+		}
+		decoded = append(decoded, name[i])
+	}
+	return string(decoded)
+}
+
+func (l *pdfLexer) readLiteralString() pdfString {
+	var result []byte
+	nesting := 1
+
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		l.pos++
+
+		switch b {
+		case '(':
+			nesting++
+		case ')':
+			nesting--
+			if nesting == 0 {
+				return result
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return result
+			}
+			escaped := l.data[l.pos]
+			l.pos++
+			switch escaped {
+			case 'n':
+				b = '\n'
+			case 'r':
+				b = '\r'
+			case 't':
+				b = '\t'
+			case 'b':
+				b = '\b'
+			case 'f':
+				b = '\f'
+			case '\r':
+				// A line continuation.
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					value := int(escaped - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					b = byte(value)
+				} else {
+					b = escaped
+				}
+			}
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+func (l *pdfLexer) readHexString() pdfString {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		end = len(l.data) - l.pos
+	}
+	digits := make([]byte, 0, end)
+	for _, b := range l.data[l.pos : l.pos+end] {
+		if !isPDFSpace(b) {
+			digits = append(digits, b)
+		}
+	}
+	l.pos = min(l.pos+end+1, len(l.data))
+
+	// An odd number of digits is padded with 0.
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded := make([]byte, len(digits)/2)
+	n, _ := hex.Decode(decoded, digits)
+	return decoded[:n]
+}
+
+func (l *pdfLexer) readArray(depth int) (pdfArray, error) {
+	array := pdfArray{}
+	for {
+		object, err := l.readObject(depth + 1)
+		if err != nil {
+			return array, err
+		}
+		if object == pdfKeyword("]") {
+			return array, nil
+		}
+		array = append(array, object)
+	}
+}
+
+func (l *pdfLexer) readDict(depth int) (pdfDict, error) {
+	dict := pdfDict{}
+	for {
+		key, err := l.readObject(depth + 1)
+		if err != nil {
+			return dict, err
+		}
+		if key == pdfKeyword(">>") {
+			return dict, nil
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			// Skip malformed entries.
+			continue
+		}
+		value, err := l.readObject(depth + 1)
+		if err != nil {
+			return dict, err
+		}
+		if value == pdfKeyword(">>") {
+			return dict, nil
+		}
+		dict[name] = value
+	}
+}
+
+// pdfDocument holds the objects of a PDF file by object number.
+type pdfDocument struct {
+	objects map[int]any
+	// The trailer dictionaries of files with cross-reference tables.
+	trailers []pdfDict
+	// decoded caches the result of decodeStream by stream, decodedSize sums its data.
+	decoded     map[*pdfStream]pdfDecodedStream
+	decodedSize int
+}
+
+type pdfDecodedStream struct {
+	data []byte
+	err  error
+}
+
+var pdfObjectRegexp = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// parsePDFDocument reads every object in data. The cross-reference table is not used, scanning
+// the file also recovers damaged files, later objects replace earlier ones as with incremental
+// updates.
+func parsePDFDocument(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\f\r "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing %%PDF header", errPDFSyntax)
+	}
+
+	document := &pdfDocument{objects: map[int]any{}, decoded: map[*pdfStream]pdfDecodedStream{}}
+	var objectStreams []*pdfStream
+
+	for pos := 0; pos < len(data); {
+		match := pdfObjectRegexp.FindSubmatchIndex(data[pos:])
+		if match == nil {
+			break
+		}
+		number, _ := strconv.Atoi(string(data[pos+match[2] : pos+match[3]]))
+		lexer := &pdfLexer{data: data, pos: pos + match[1]}
+
+		object, err := lexer.readObject(0)
+		if err != nil {
+			pos = lexer.pos
+			continue
+		}
+		if dict, ok := object.(pdfDict); ok {
+			if stream, ok := lexer.readStream(dict); ok {
+				object = stream
+				if stream.dict["Type"] == pdfName("ObjStm") {
+					objectStreams = append(objectStreams, stream)
+				}
+			}
+		}
+		document.objects[number] = object
+		pos = lexer.pos
+	}
+
+	// Objects in object streams are compressed, they come after the stream is read.
+	for _, stream := range objectStreams {
+		if err := document.readObjectStream(stream); err != nil {
+			return nil, err
+		}
+	}
+
+	for pos := 0; ; {
+		index := bytes.Index(data[pos:], []byte("trailer"))
+		if index < 0 {
+			break
+		}
+		lexer := &pdfLexer{data: data, pos: pos + index + len("trailer")}
+		if trailer, err := lexer.readObject(0); err == nil {
+			if dict, ok := trailer.(pdfDict); ok {
+				document.trailers = append(document.trailers, dict)
+			}
+		}
+		pos = lexer.pos
+	}
+
+	if len(document.objects) == 0 {
+		return nil, fmt.Errorf("%w: no objects found", errPDFSyntax)
+	}
+	if _, ok := document.trailerValue("Encrypt"); ok {
+		return nil, errors.New("encrypted PDF documents are not supported")
+	}
+	return document, nil
+}
+
+// readStream reads the stream following dict, if any.
+func (l *pdfLexer) readStream(dict pdfDict) (*pdfStream, bool) {
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return nil, false
+	}
+	start := l.pos + len("stream")
+	if bytes.HasPrefix(l.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(l.data) && (l.data[start] == '\n' || l.data[start] == '\r') {
+		start++
+	}
+
+	// The Length is trusted when direct and followed by endstream, else endstream is searched.
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && start+int(length) <= len(l.data) {
+		end := start + int(length)
+		if bytes.HasPrefix(bytes.TrimLeft(l.data[end:], "\r\n "), []byte("endstream")) {
+			l.pos = end
+			return &pdfStream{dict: dict, data: l.data[start:end]}, true
+		}
+	}
+
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return &pdfStream{dict: dict, data: l.data[start:]}, true
+	}
+	l.pos = start + end + len("endstream")
+	return &pdfStream{dict: dict, data: bytes.TrimRight(l.data[start:start+end], "\r\n")}, true
+}
+
+// readObjectStream adds the objects of stream, it only fails when the document is too large.
+func (d *pdfDocument) readObjectStream(stream *pdfStream) error {
+	data, err := d.decodeStream(stream)
+	if errors.Is(err, errPDFTooLarge) {
+		return err
+	}
+	if err != nil {
+		return nil
+	}
+	count, _ := stream.dict["N"].(float64)
+	first, _ := stream.dict["First"].(float64)
+	if int(first) > len(data) {
+		return nil
+	}
+
+	// The stream starts with pairs of object numbers and offsets relative to First.
+	header := &pdfLexer{data: data[:int(first)]}
+	for i := 0; i < int(count); i++ {
+		number, err1 := header.readObject(0)
+		offset, err2 := header.readObject(0)
+		if err1 != nil || err2 != nil {
+			return nil
+		}
+		numberValue, ok1 := number.(float64)
+		offsetValue, ok2 := offset.(float64)
+		if !ok1 || !ok2 || int(first)+int(offsetValue) >= len(data) {
+			continue
+		}
+
+		// Objects are rarely both in the file body and in a stream, the body is kept then.
+		if _, ok := d.objects[int(numberValue)]; ok {
+			continue
+		}
+		lexer := &pdfLexer{data: data, pos: int(first) + int(offsetValue)}
+		if object, err := lexer.readObject(0); err == nil {
+			d.objects[int(numberValue)] = object
+		}
+	}
+	return nil
+}
+
+// trailerValue looks key up in the trailer, which is either a trailer dictionary or the
+// dictionary of a cross-reference stream.
+func (d *pdfDocument) trailerValue(key pdfName) (any, bool) {
+	// The last trailer belongs to the latest incremental update.
+	for i := len(d.trailers) - 1; i >= 0; i-- {
+		if value, ok := d.trailers[i][key]; ok {
+			return value, true
+		}
+	}
+	for _, object := range d.objects {
+		if stream, ok := object.(*pdfStream); ok && stream.dict["Type"] == pdfName("XRef") {
+			if value, ok := stream.dict[key]; ok {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// resolve follows indirect references.
+func (d *pdfDocument) resolve(object any) any {
+	for i := 0; i < maxPDFDepth; i++ {
+		ref, ok := object.(pdfRef)
+		if !ok {
+			return object
+		}
+		object = d.objects[ref.number]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(object any) pdfDict {
+	switch object := d.resolve(object).(type) {
+	case pdfDict:
+		return object
+	case *pdfStream:
+		return object.dict
+	}
+	return nil
+}
+
+// decodeStream applies the filters of stream, only the filters used for text content are
+// supported. It fails with errPDFTooLarge once the document decoded more than maxPDFDecodedSize.
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	if decoded, ok := d.decoded[stream]; ok {
+		return decoded.data, decoded.err
+	}
+
+	data, err := d.applyFilters(stream)
+	if err == nil && d.decodedSize+len(data) > maxPDFDecodedSize {
+		data, err = nil, errPDFTooLarge
+	}
+	d.decodedSize += len(data)
+	d.decoded[stream] = pdfDecodedStream{data: data, err: err}
+	return data, err
+}
+
+func (d *pdfDocument) applyFilters(stream *pdfStream) ([]byte, error) {
+	var filters pdfArray
+	switch filter := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = pdfArray{filter}
+	case pdfArray:
+		filters = filter
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		var err error
+		switch d.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			lexer := &pdfLexer{data: data}
+			data = lexer.readHexString()
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			err = fmt.Errorf("unsupported PDF stream filter %v", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	inflated, err := io.ReadAll(io.LimitReader(reader, maxPDFStreamSize+1))
+	if len(inflated) > maxPDFStreamSize {
+		return nil, errors.New("PDF stream is too large")
+	}
+	// Truncated streams are common, keep what could be read.
+	if err != nil && len(inflated) == 0 {
+		return nil, err
+	}
+	return inflated, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimLeft(data, "\x00\t\n\f\r "), []byte("<~"))
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	decoded := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(decoded, data, true)
+	return decoded[:n], err
+}
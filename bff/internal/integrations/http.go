@@ -1,6 +1,7 @@
 package integrations
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	helper "github.com/opendatahub-io/llama-stack-modular-ui/internal/helpers"
@@ -17,6 +19,8 @@ type HTTPClientInterface interface {
 	GET(url string) ([]byte, error)
 	POST(url string, body io.Reader) ([]byte, error)
 	PATCH(url string, body io.Reader) ([]byte, error)
+	DELETE(url string) ([]byte, error)
+	POSTStream(ctx context.Context, url string, body io.Reader) (io.ReadCloser, error)
 }
 
 type HTTPClient struct {
@@ -79,6 +83,10 @@ func (c *HTTPClient) GET(url string) ([]byte, error) {
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
+	if response.StatusCode != http.StatusOK {
+		return nil, newHTTPError(response.StatusCode, body)
+	}
+
 	return body, nil
 }
 
@@ -116,21 +124,7 @@ func (c *HTTPClient) POST(url string, body io.Reader) ([]byte, error) {
 
 	// Certain operations like DB creation just return 200
 	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		if err := json.Unmarshal(responseBody, &errorResponse); err != nil {
-			return nil, fmt.Errorf("error parsing error response: %w", err)
-		}
-		httpError := &HTTPError{
-			StatusCode:    response.StatusCode,
-			ErrorResponse: errorResponse,
-		}
-		//Sometimes the code comes empty from model registry API
-		//also not all error codes are correctly implemented
-		//see https://github.com/kubeflow/model-registry/issues/95
-		if httpError.Code == "" {
-			httpError.Code = strconv.Itoa(response.StatusCode)
-		}
-		return nil, httpError
+		return nil, newHTTPError(response.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
@@ -167,26 +161,119 @@ func (c *HTTPClient) PATCH(url string, body io.Reader) ([]byte, error) {
 	}
 
 	if response.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		if err := json.Unmarshal(responseBody, &errorResponse); err != nil {
-			return nil, fmt.Errorf("error parsing error response: %w", err) // updated wording
-		}
-		httpError := &HTTPError{
-			StatusCode:    response.StatusCode,
-			ErrorResponse: errorResponse,
-		}
-		//Sometimes the code comes empty from model registry API
-		//also not all error codes are correctly implemented
-		//see https://github.com/kubeflow/model-registry/issues/95
-		if httpError.Code == "" {
-			httpError.Code = strconv.Itoa(response.StatusCode)
+		return nil, newHTTPError(response.StatusCode, responseBody)
+	}
+
+	return responseBody, nil
+}
+
+func (c *HTTPClient) DELETE(url string) ([]byte, error) {
+	fullURL := c.baseURL + url
+	req, err := http.NewRequest(http.MethodDelete, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	requestId := uuid.NewString()
+
+	logUpstreamReq(c.logger, requestId, req)
+
+	response, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			c.logger.Warn("failed to close response body", "error", closeErr)
 		}
-		return nil, httpError
+	}()
+
+	responseBody, err := io.ReadAll(response.Body)
+	logUpstreamResp(c.logger, requestId, response, responseBody)
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+		return nil, newHTTPError(response.StatusCode, responseBody)
 	}
 
 	return responseBody, nil
 }
 
+// POSTStream sends a POST request and hands back the open response body so that streamed
+// responses (e.g. server-sent events) can be consumed incrementally. The caller must close
+// the returned body. Cancelling ctx aborts the upstream request.
+func (c *HTTPClient) POSTStream(ctx context.Context, url string, body io.Reader) (io.ReadCloser, error) {
+	requestId := uuid.NewString()
+
+	fullURL := c.baseURL + url
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	logUpstreamReq(c.logger, requestId, req)
+
+	response, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer func() {
+			if closeErr := response.Body.Close(); closeErr != nil {
+				c.logger.Warn("failed to close response body", "error", closeErr)
+			}
+		}()
+
+		responseBody, err := io.ReadAll(response.Body)
+		logUpstreamResp(c.logger, requestId, response, responseBody)
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+
+		return nil, newHTTPError(response.StatusCode, responseBody)
+	}
+
+	// The body is streamed to the caller, so only the status and headers are logged here.
+	logUpstreamResp(c.logger, requestId, response, nil)
+
+	return response.Body, nil
+}
+
+func newHTTPError(statusCode int, responseBody []byte) error {
+	var errorResponse struct {
+		ErrorResponse
+		// Llama Stack reports errors as {"detail": "..."}
+		Detail any `json:"detail"`
+	}
+	if err := json.Unmarshal(responseBody, &errorResponse); err != nil {
+		// Not every upstream error is JSON, e.g. those returned by proxies in front of Llama Stack.
+		errorResponse.Message = strings.TrimSpace(string(responseBody))
+	}
+	httpError := &HTTPError{
+		StatusCode:    statusCode,
+		ErrorResponse: errorResponse.ErrorResponse,
+	}
+	if httpError.Message == "" && errorResponse.Detail != nil {
+		httpError.Message = fmt.Sprint(errorResponse.Detail)
+	}
+	//Sometimes the code comes empty from model registry API
+	//also not all error codes are correctly implemented
+	//see https://github.com/kubeflow/model-registry/issues/95
+	if httpError.Code == "" {
+		httpError.Code = strconv.Itoa(statusCode)
+	}
+	return httpError
+}
+
 func logUpstreamReq(logger *slog.Logger, reqId string, req *http.Request) {
 	logger.Debug("Making upstream HTTP request", slog.String("request_id", reqId), slog.Any("request", helper.RequestLogValuer{Request: req}))
 }
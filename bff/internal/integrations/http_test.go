@@ -0,0 +1,77 @@
+package integrations
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHTTPClient(t *testing.T, status int, body string) HTTPClientInterface {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewHTTPClient(slog.Default(), server.URL)
+	assert.NoError(t, err)
+	return client
+}
+
+func TestHTTPClientGET(t *testing.T) {
+	client := newTestHTTPClient(t, http.StatusOK, `{"data":[]}`)
+
+	body, err := client.GET("/v1/models")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"data":[]}`, string(body))
+}
+
+func TestHTTPClientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected HTTPError
+	}{
+		{
+			name:     "error response",
+			status:   http.StatusBadRequest,
+			body:     `{"code":"invalid","message":"bad model"}`,
+			expected: HTTPError{StatusCode: http.StatusBadRequest, ErrorResponse: ErrorResponse{Code: "invalid", Message: "bad model"}},
+		},
+		{
+			name:     "Llama Stack detail",
+			status:   http.StatusNotFound,
+			body:     `{"detail":"Model 'x' not found"}`,
+			expected: HTTPError{StatusCode: http.StatusNotFound, ErrorResponse: ErrorResponse{Code: "404", Message: "Model 'x' not found"}},
+		},
+		{
+			name:     "not JSON",
+			status:   http.StatusBadGateway,
+			body:     "upstream connect error\n",
+			expected: HTTPError{StatusCode: http.StatusBadGateway, ErrorResponse: ErrorResponse{Code: "502", Message: "upstream connect error"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestHTTPClient(t, tt.status, tt.body)
+
+			for name, request := range map[string]func() ([]byte, error){
+				"GET":    func() ([]byte, error) { return client.GET("/v1/models") },
+				"POST":   func() ([]byte, error) { return client.POST("/v1/models", nil) },
+				"DELETE": func() ([]byte, error) { return client.DELETE("/v1/models/x") },
+			} {
+				_, err := request()
+
+				var httpError *HTTPError
+				assert.True(t, errors.As(err, &httpError), name)
+				assert.Equal(t, tt.expected, *httpError, name)
+			}
+		})
+	}
+}
@@ -2,6 +2,11 @@
 // replaced by a separate golang SDK at some point in the future.
 package llamastack
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 const (
 	LLMModelType       = "llm"
 	EmbeddingModelType = "embedding"
@@ -14,12 +19,24 @@ type Model struct {
 	ModelType          ModelModelType `json:"model_type"`
 	ProviderID         string         `json:"provider_id"`
 	ProviderResourceID string         `json:"provider_resource_id"`
+	// Provider specific details, e.g. context_length or embedding_dimension.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 type ModelList struct {
 	Data []Model `json:"data"`
 }
 
+// ModelRegistrationRequest represents the request body for registering a model with /v1/models
+type ModelRegistrationRequest struct {
+	ModelID string `json:"model_id"`
+	// The id of the model at the provider, defaults to ModelID.
+	ProviderModelID string         `json:"provider_model_id,omitempty"`
+	ProviderID      string         `json:"provider_id"`
+	ModelType       ModelModelType `json:"model_type"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+}
+
 type VectorDB struct {
 	EmbeddingDimension int64  `json:"embedding_dimension"`
 	EmbeddingModel     string `json:"embedding_model"`
@@ -32,8 +49,8 @@ type VectorDBList struct {
 	Data []VectorDB `json:"data"`
 }
 
-// TODO: This is designed to only hold text content for now.
-// We can add support for other document types based on go client APIs.
+// Document only holds text content, the BFF extracts the text of uploaded PDF, DOCX and HTML
+// files before inserting them.
 type Document struct {
 	DocumentID string         `json:"document_id"`
 	Content    string         `json:"content"`
@@ -43,6 +60,20 @@ type Document struct {
 	MimeType *string `json:"mime_type,omitempty"`
 }
 
+// Chunk is a piece of a document as stored in a vector database. The RAG tool requires the
+// document_id and token_count metadata.
+type Chunk struct {
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// VectorIOInsertRequest inserts chunks as they are, Llama Stack only computes their embeddings.
+// Based on Llama Stack API specification for /v1/vector-io/insert
+type VectorIOInsertRequest struct {
+	VectorDBID string  `json:"vector_db_id"`
+	Chunks     []Chunk `json:"chunks"`
+}
+
 // DocumentInsertRequest represents the request body for inserting documents
 // Based on Llama Stack API specification for /v1/tool-runtime/rag-tool/insert
 type DocumentInsertRequest struct {
@@ -50,3 +81,393 @@ type DocumentInsertRequest struct {
 	VectorDBID        string     `json:"vector_db_id"`
 	ChunkSizeInTokens *int       `json:"chunk_size_in_tokens,omitempty"`
 }
+
+// TextContent holds interleaved content reduced to its text. Llama Stack returns content either
+// as a plain string, a single content item or a list of content items; non text items are dropped.
+type TextContent string
+
+type contentItem struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (c *TextContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = TextContent(text)
+		return nil
+	}
+
+	var items []contentItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		var item contentItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		items = []contentItem{item}
+	}
+
+	var sb strings.Builder
+	for _, item := range items {
+		if item.Type == "text" {
+			sb.WriteString(item.Text)
+		}
+	}
+	*c = TextContent(sb.String())
+
+	return nil
+}
+
+type RAGQueryConfig struct {
+	MaxChunks          int `json:"max_chunks,omitempty"`
+	MaxTokensInContext int `json:"max_tokens_in_context,omitempty"`
+}
+
+// RAGQueryRequest represents the request body for querying documents
+// Based on Llama Stack API specification for /v1/tool-runtime/rag-tool/query
+type RAGQueryRequest struct {
+	Content     string          `json:"content"`
+	VectorDBIDs []string        `json:"vector_db_ids"`
+	QueryConfig *RAGQueryConfig `json:"query_config,omitempty"`
+}
+
+// RAGQueryResultMetadata lists the retrieved chunks, the slices are index aligned.
+type RAGQueryResultMetadata struct {
+	DocumentIDs []string      `json:"document_ids"`
+	Chunks      []TextContent `json:"chunks"`
+	Scores      []float64     `json:"scores"`
+	// Only returned by newer Llama Stack versions.
+	VectorDBIDs []string `json:"vector_db_ids,omitempty"`
+}
+
+type RAGQueryResult struct {
+	// The retrieved context formatted for injection into a prompt.
+	Content  TextContent            `json:"content"`
+	Metadata RAGQueryResultMetadata `json:"metadata"`
+}
+
+const (
+	SystemRole    = "system"
+	UserRole      = "user"
+	AssistantRole = "assistant"
+	ToolRole      = "tool"
+)
+
+// Message is a single chat message. As with Document, only text content is supported for now.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+
+	// Only set on assistant messages, e.g. "end_of_turn" or "end_of_message".
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+const (
+	GreedySamplingStrategy = "greedy"
+	TopPSamplingStrategy   = "top_p"
+)
+
+type SamplingStrategy struct {
+	Type        string   `json:"type"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+type SamplingParams struct {
+	Strategy          SamplingStrategy `json:"strategy"`
+	MaxTokens         *int             `json:"max_tokens,omitempty"`
+	RepetitionPenalty *float64         `json:"repetition_penalty,omitempty"`
+}
+
+// JSONSchemaResponseFormatType constrains the answer to JSON matching ResponseFormat.JSONSchema.
+const JSONSchemaResponseFormatType = "json_schema"
+
+// ResponseFormat selects the structured output mode of a chat completion.
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+// ChatCompletionRequest represents the request body for /v1/inference/chat-completion
+type ChatCompletionRequest struct {
+	ModelID        string          `json:"model_id"`
+	Messages       []Message       `json:"messages"`
+	SamplingParams *SamplingParams `json:"sampling_params,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Stream         bool            `json:"stream"`
+}
+
+type EmbeddingsRequest struct {
+	ModelID  string   `json:"model_id"`
+	Contents []string `json:"contents"`
+}
+
+type EmbeddingsResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+const (
+	StartEventType    = "start"
+	ProgressEventType = "progress"
+	CompleteEventType = "complete"
+)
+
+type ContentDelta struct {
+	// Either "text" or "tool_call".
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type ChatCompletionResponseEvent struct {
+	EventType  string       `json:"event_type"`
+	Delta      ContentDelta `json:"delta"`
+	StopReason string       `json:"stop_reason,omitempty"`
+}
+
+// Metric is reported by Llama Stack alongside responses, e.g. prompt_tokens or completion_tokens.
+type Metric struct {
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+}
+
+// StreamError is sent in place of an event when a stream fails upstream.
+type StreamError struct {
+	Message string `json:"message"`
+}
+
+// ChatCompletionResponseStreamChunk is a single server-sent event of a streamed chat completion.
+type ChatCompletionResponseStreamChunk struct {
+	Event   ChatCompletionResponseEvent `json:"event"`
+	Metrics []Metric                    `json:"metrics,omitempty"`
+}
+
+// AgentToolGroup references a tool group by name, optionally with arguments, e.g. the
+// vector_db_ids of builtin::rag/knowledge_search. Without arguments it is sent as a plain string.
+type AgentToolGroup struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func (t AgentToolGroup) MarshalJSON() ([]byte, error) {
+	if len(t.Args) == 0 {
+		return json.Marshal(t.Name)
+	}
+
+	type toolGroupWithArgs AgentToolGroup
+	return json.Marshal(toolGroupWithArgs(t))
+}
+
+func (t *AgentToolGroup) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = AgentToolGroup{Name: name}
+		return nil
+	}
+
+	type toolGroupWithArgs AgentToolGroup
+	var withArgs toolGroupWithArgs
+	if err := json.Unmarshal(data, &withArgs); err != nil {
+		return err
+	}
+	*t = AgentToolGroup(withArgs)
+
+	return nil
+}
+
+type AgentConfig struct {
+	Model                    string           `json:"model"`
+	Instructions             string           `json:"instructions"`
+	Toolgroups               []AgentToolGroup `json:"toolgroups,omitempty"`
+	InputShields             []string         `json:"input_shields,omitempty"`
+	OutputShields            []string         `json:"output_shields,omitempty"`
+	MaxInferIters            *int             `json:"max_infer_iters,omitempty"`
+	EnableSessionPersistence bool             `json:"enable_session_persistence"`
+}
+
+// AgentCreateRequest represents the request body for /v1/agents
+type AgentCreateRequest struct {
+	AgentConfig AgentConfig `json:"agent_config"`
+}
+
+type AgentCreateResponse struct {
+	AgentID string `json:"agent_id"`
+}
+
+// Agent is returned by /v1/agents/{agent_id}
+type Agent struct {
+	AgentID     string      `json:"agent_id"`
+	AgentConfig AgentConfig `json:"agent_config"`
+	CreatedAt   string      `json:"created_at,omitempty"`
+}
+
+// AgentSessionCreateRequest represents the request body for /v1/agents/{agent_id}/session
+type AgentSessionCreateRequest struct {
+	SessionName string `json:"session_name"`
+}
+
+type AgentSessionCreateResponse struct {
+	SessionID string `json:"session_id"`
+}
+
+// AgentTurnCreateRequest represents the request body for /v1/agents/{agent_id}/session/{session_id}/turn
+type AgentTurnCreateRequest struct {
+	Messages   []Message        `json:"messages"`
+	Toolgroups []AgentToolGroup `json:"toolgroups,omitempty"`
+	Stream     bool             `json:"stream"`
+}
+
+const (
+	InferenceStepType     = "inference"
+	ToolExecutionStepType = "tool_execution"
+	ShieldCallStepType    = "shield_call"
+
+	TurnStartEventType    = "turn_start"
+	TurnCompleteEventType = "turn_complete"
+	StepStartEventType    = "step_start"
+	StepProgressEventType = "step_progress"
+	StepCompleteEventType = "step_complete"
+)
+
+// AgentTurnResponseEventPayload is a union of the turn and step events of an agent turn, the
+// step specific parts are passed through untouched.
+type AgentTurnResponseEventPayload struct {
+	EventType   string          `json:"event_type"`
+	StepType    string          `json:"step_type,omitempty"`
+	StepID      string          `json:"step_id,omitempty"`
+	TurnID      string          `json:"turn_id,omitempty"`
+	Delta       json.RawMessage `json:"delta,omitempty"`
+	StepDetails json.RawMessage `json:"step_details,omitempty"`
+	Turn        *Turn           `json:"turn,omitempty"`
+}
+
+type AgentTurnResponseEvent struct {
+	Payload AgentTurnResponseEventPayload `json:"payload"`
+}
+
+// AgentTurnResponseStreamChunk is a single server-sent event of a streamed agent turn.
+type AgentTurnResponseStreamChunk struct {
+	Event AgentTurnResponseEvent `json:"event"`
+}
+
+// TurnMessage is a message as recorded in a turn, its content may be a list of content items.
+type TurnMessage struct {
+	Role       string      `json:"role"`
+	Content    TextContent `json:"content"`
+	StopReason string      `json:"stop_reason,omitempty"`
+}
+
+type Turn struct {
+	TurnID        string            `json:"turn_id"`
+	SessionID     string            `json:"session_id"`
+	InputMessages []TurnMessage     `json:"input_messages"`
+	Steps         []json.RawMessage `json:"steps"`
+	OutputMessage *TurnMessage      `json:"output_message,omitempty"`
+	StartedAt     string            `json:"started_at,omitempty"`
+	CompletedAt   string            `json:"completed_at,omitempty"`
+}
+
+type Session struct {
+	SessionID   string `json:"session_id"`
+	SessionName string `json:"session_name"`
+	Turns       []Turn `json:"turns"`
+	StartedAt   string `json:"started_at,omitempty"`
+}
+
+type MCPEndpoint struct {
+	URI string `json:"uri"`
+}
+
+type ToolGroup struct {
+	Identifier         string         `json:"identifier"`
+	ProviderID         string         `json:"provider_id"`
+	ProviderResourceID string         `json:"provider_resource_id"`
+	MCPEndpoint        *MCPEndpoint   `json:"mcp_endpoint,omitempty"`
+	Args               map[string]any `json:"args,omitempty"`
+}
+
+type ToolGroupList struct {
+	Data []ToolGroup `json:"data"`
+}
+
+// ToolGroupRegistrationRequest represents the request body for registering a tool group
+// Based on Llama Stack API specification for /v1/toolgroups
+type ToolGroupRegistrationRequest struct {
+	ToolGroupID string         `json:"toolgroup_id"`
+	ProviderID  string         `json:"provider_id"`
+	MCPEndpoint *MCPEndpoint   `json:"mcp_endpoint,omitempty"`
+	Args        map[string]any `json:"args,omitempty"`
+}
+
+type ToolParameter struct {
+	Name          string `json:"name"`
+	ParameterType string `json:"parameter_type"`
+	Description   string `json:"description"`
+	Required      bool   `json:"required"`
+	Default       any    `json:"default,omitempty"`
+}
+
+type Tool struct {
+	Identifier         string          `json:"identifier"`
+	ProviderID         string          `json:"provider_id"`
+	ProviderResourceID string          `json:"provider_resource_id"`
+	ToolGroupID        string          `json:"toolgroup_id"`
+	Description        string          `json:"description"`
+	Parameters         []ToolParameter `json:"parameters"`
+	Metadata           map[string]any  `json:"metadata,omitempty"`
+}
+
+type ToolList struct {
+	Data []Tool `json:"data"`
+}
+
+type Shield struct {
+	Identifier         string         `json:"identifier"`
+	ProviderID         string         `json:"provider_id"`
+	ProviderResourceID string         `json:"provider_resource_id"`
+	Params             map[string]any `json:"params,omitempty"`
+}
+
+type ShieldList struct {
+	Data []Shield `json:"data"`
+}
+
+const (
+	InfoViolationLevel  = "info"
+	WarnViolationLevel  = "warn"
+	ErrorViolationLevel = "error"
+)
+
+// RunShieldRequest represents the request body for /v1/safety/run-shield
+type RunShieldRequest struct {
+	ShieldID string         `json:"shield_id"`
+	Messages []Message      `json:"messages"`
+	Params   map[string]any `json:"params"`
+}
+
+type SafetyViolation struct {
+	ViolationLevel string         `json:"violation_level"`
+	UserMessage    string         `json:"user_message,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+}
+
+type RunShieldResponse struct {
+	// Nil when the messages passed the shield.
+	Violation *SafetyViolation `json:"violation"`
+}
+
+// VectorIOProviderAPI is the API of the providers backing vector databases.
+const VectorIOProviderAPI = "vector_io"
+
+// Provider is a provider configured in the Llama Stack distribution, e.g. inline::faiss for the
+// vector_io API.
+type Provider struct {
+	API          string         `json:"api"`
+	ProviderID   string         `json:"provider_id"`
+	ProviderType string         `json:"provider_type"`
+	Config       map[string]any `json:"config,omitempty"`
+}
+
+type ProviderList struct {
+	Data []Provider `json:"data"`
+}
@@ -0,0 +1,71 @@
+package integrations
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// maxSSEEventSize bounds a single server-sent event line, chat chunks are small but tool
+// call payloads can carry larger JSON documents.
+const maxSSEEventSize = 1_048_576
+
+// SSEReader reads the data payloads out of a server-sent events stream such as the ones
+// returned by the Llama Stack streaming APIs.
+type SSEReader struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+func NewSSEReader(body io.ReadCloser) *SSEReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSEEventSize)
+
+	return &SSEReader{
+		body:    body,
+		scanner: scanner,
+	}
+}
+
+// Next returns the data of the next event in the stream. Multi-line data fields are joined
+// with a newline as per the SSE specification. io.EOF is returned once the stream is drained.
+func (s *SSEReader) Next() ([]byte, error) {
+	var data []byte
+
+	for s.scanner.Scan() {
+		line := s.scanner.Bytes()
+
+		// An empty line dispatches the event collected so far.
+		if len(line) == 0 {
+			if len(data) > 0 {
+				return data, nil
+			}
+			continue
+		}
+
+		// Comments, event names and ids are not needed by any consumer so far.
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+
+		payload := bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" "))
+		if len(data) > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, payload...)
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		return data, nil
+	}
+
+	return nil, io.EOF
+}
+
+func (s *SSEReader) Close() error {
+	return s.body.Close()
+}
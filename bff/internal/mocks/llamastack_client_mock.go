@@ -1,9 +1,20 @@
 package mocks
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/repositories"
@@ -12,15 +23,72 @@ import (
 
 type LlamastackClientMock struct {
 	mock.Mock
-	registeredVectorDBs []llamastack.VectorDB
-	mutex               sync.RWMutex
+	// The default models followed by the registered ones.
+	models []llamastack.Model
+	// The default vector DBs followed by the registered ones.
+	vectorDBs []llamastack.VectorDB
+	// Inserted documents keyed by vector DB identifier, used to answer RAG queries.
+	documents map[string][]llamastack.Document
+	// Agents keyed by agent ID, sessions keyed by agent ID and session ID.
+	agents               map[string]llamastack.AgentConfig
+	agentSessions        map[string]map[string]*llamastack.Session
+	registeredToolGroups []llamastack.ToolGroup
+	mutex                sync.RWMutex
 }
 
 var _ repositories.LlamaStackClientInterface = &LlamastackClientMock{}
 
+// defaultModels are the models the mock starts out with.
+var defaultModels = []llamastack.Model{
+	{
+		Identifier:         "default-model-id-1",
+		ModelType:          llamastack.LLMModelType,
+		ProviderID:         "default-provider-id-1",
+		ProviderResourceID: "default-provider-resource-id-1",
+		Metadata:           map[string]any{"context_length": float64(MockContextLength)},
+	},
+	{
+		Identifier:         "default-model-id-2",
+		ModelType:          llamastack.EmbeddingModelType,
+		ProviderID:         "default-provider-id-2",
+		ProviderResourceID: "default-provider-resource-id-2",
+		Metadata:           map[string]any{"embedding_dimension": float64(MockEmbeddingDimension)},
+	},
+	{
+		Identifier:         "default-model-id-3",
+		ModelType:          llamastack.LLMModelType,
+		ProviderID:         "default-provider-id-1",
+		ProviderResourceID: "default-provider-resource-id-3",
+	},
+}
+
+// defaultVectorDBs are the vector DBs the mock starts out with, they have no owner as if they
+// were created without authentication.
+var defaultVectorDBs = []llamastack.VectorDB{
+	{
+		Identifier:         "default-vector-db-id-1",
+		ProviderID:         "default-provider-id-1",
+		ProviderResourceID: "default-provider-resource-id-1",
+		EmbeddingDimension: 1536,
+		EmbeddingModel:     "default-embedding-model-1",
+	},
+	{
+		Identifier:         "default-vector-db-id-2",
+		ProviderID:         "default-provider-id-2",
+		ProviderResourceID: "default-provider-resource-id-2",
+		EmbeddingDimension: 1536,
+		EmbeddingModel:     "default-embedding-model-2",
+	},
+}
+
 func NewLlamastackClientMock() (*LlamastackClientMock, error) {
 	return &LlamastackClientMock{
-		registeredVectorDBs: []llamastack.VectorDB{},
+		models:               append([]llamastack.Model{}, defaultModels...),
+		vectorDBs:            append([]llamastack.VectorDB{}, defaultVectorDBs...),
+		documents:            map[string][]llamastack.Document{},
+		agents:               map[string]llamastack.AgentConfig{},
+		agentSessions:        map[string]map[string]*llamastack.Session{},
+		registeredToolGroups: []llamastack.ToolGroup{},
 	}, nil
 }
 
@@ -28,78 +96,139 @@ func NewLlamastackClientMock() (*LlamastackClientMock, error) {
 var _ repositories.LlamaStackClientInterface = &LlamastackClientMock{}
 
 func (l *LlamastackClientMock) GetAllModels(_ integrations.HTTPClientInterface) (*llamastack.ModelList, error) {
-	data := llamastack.ModelList{
-		Data: []llamastack.Model{
-			{
-				Identifier:         "default-model-id-1",
-				ModelType:          llamastack.LLMModelType,
-				ProviderID:         "default-provider-id-1",
-				ProviderResourceID: "default-provider-resource-id-1",
-			},
-			{
-				Identifier:         "default-model-id-2",
-				ModelType:          llamastack.EmbeddingModelType,
-				ProviderID:         "default-provider-id-2",
-				ProviderResourceID: "default-provider-resource-id-2",
-			},
-		},
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return &llamastack.ModelList{Data: append([]llamastack.Model{}, l.models...)}, nil
+}
+
+func (l *LlamastackClientMock) GetModel(client integrations.HTTPClientInterface, modelID string) (*llamastack.Model, error) {
+	modelList, err := l.GetAllModels(client)
+	if err != nil {
+		return nil, err
 	}
 
-	return &data, nil
+	for _, model := range modelList.Data {
+		if model.Identifier == modelID {
+			return &model, nil
+		}
+	}
+
+	return nil, newMockNotFoundError(fmt.Sprintf("model %s not found", modelID))
+}
+
+func (l *LlamastackClientMock) RegisterModel(_ integrations.HTTPClientInterface, request llamastack.ModelRegistrationRequest) (*llamastack.Model, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, model := range l.models {
+		if model.Identifier == request.ModelID {
+			return nil, fmt.Errorf("model with identifier '%s' already exists", request.ModelID)
+		}
+	}
+
+	model := llamastack.Model{
+		Identifier:         request.ModelID,
+		ModelType:          request.ModelType,
+		ProviderID:         request.ProviderID,
+		ProviderResourceID: request.ProviderModelID,
+		Metadata:           request.Metadata,
+	}
+	if model.ProviderResourceID == "" {
+		model.ProviderResourceID = request.ModelID
+	}
+	if model.Metadata == nil {
+		model.Metadata = map[string]any{}
+	}
+	l.models = append(l.models, model)
+
+	return &model, nil
+}
+
+func (l *LlamastackClientMock) UnregisterModel(_ integrations.HTTPClientInterface, modelID string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i, model := range l.models {
+		if model.Identifier == modelID {
+			l.models = append(l.models[:i], l.models[i+1:]...)
+			return nil
+		}
+	}
+
+	return newMockNotFoundError(fmt.Sprintf("model %s not found", modelID))
 }
 
 func (l *LlamastackClientMock) GetAllVectorDBs(_ integrations.HTTPClientInterface) (*llamastack.VectorDBList, error) {
 	l.mutex.RLock()
 	defer l.mutex.RUnlock()
 
-	// Start with default vector DBs
-	data := llamastack.VectorDBList{
-		Data: []llamastack.VectorDB{
-			{
-				Identifier:         "default-vector-db-id-1",
-				ProviderID:         "default-provider-id-1",
-				ProviderResourceID: "default-provider-resource-id-1",
-				EmbeddingDimension: 1536,
-				EmbeddingModel:     "default-embedding-model-1",
-			},
-			{
-				Identifier:         "default-vector-db-id-2",
-				ProviderID:         "default-provider-id-2",
-				ProviderResourceID: "default-provider-resource-id-2",
-				EmbeddingDimension: 1536,
-				EmbeddingModel:     "default-embedding-model-2",
-			},
-		},
-	}
+	return &llamastack.VectorDBList{Data: append([]llamastack.VectorDB{}, l.vectorDBs...)}, nil
+}
 
-	// Add any registered vector DBs
-	data.Data = append(data.Data, l.registeredVectorDBs...)
+func (l *LlamastackClientMock) GetVectorDB(_ integrations.HTTPClientInterface, vectorDBID string) (*llamastack.VectorDB, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
 
-	return &data, nil
+	for _, vectorDB := range l.vectorDBs {
+		if vectorDB.Identifier == vectorDBID {
+			return &vectorDB, nil
+		}
+	}
+
+	return nil, newMockNotFoundError(fmt.Sprintf("vector database %s not found", vectorDBID))
 }
 
-func (l *LlamastackClientMock) RegisterVectorDB(_ integrations.HTTPClientInterface, vectorDB llamastack.VectorDB, embeddingModel string) error {
+// UnregisterVectorDB removes the vector DB along with the documents inserted into it.
+func (l *LlamastackClientMock) UnregisterVectorDB(_ integrations.HTTPClientInterface, vectorDBID string) error {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	// Check if vector DB with this identifier already exists
-	for _, existingDB := range l.registeredVectorDBs {
-		if existingDB.Identifier == vectorDB.Identifier {
-			return fmt.Errorf("vector database with identifier '%s' already exists", vectorDB.Identifier)
+	for i, vectorDB := range l.vectorDBs {
+		if vectorDB.Identifier == vectorDBID {
+			l.vectorDBs = append(l.vectorDBs[:i], l.vectorDBs[i+1:]...)
+			delete(l.documents, vectorDBID)
+			return nil
 		}
 	}
 
+	return newMockNotFoundError(fmt.Sprintf("vector database %s not found", vectorDBID))
+}
+
+func (l *LlamastackClientMock) RegisterVectorDB(_ integrations.HTTPClientInterface, vectorDB llamastack.VectorDB, embeddingModel string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	// Create a new vector DB entry with the provided embedding model
 	newVectorDB := llamastack.VectorDB{
 		Identifier:         vectorDB.Identifier,
-		ProviderID:         "inline::milvus", // Default provider for mock
-		ProviderResourceID: vectorDB.Identifier,
-		EmbeddingDimension: 384, // Default dimension for mock
+		ProviderID:         vectorDB.ProviderID,
+		ProviderResourceID: vectorDB.ProviderResourceID,
+		EmbeddingDimension: vectorDB.EmbeddingDimension,
 		EmbeddingModel:     embeddingModel,
 	}
+	if newVectorDB.ProviderID == "" {
+		newVectorDB.ProviderID = "inline::milvus" // Default provider for mock
+	}
+	if newVectorDB.ProviderResourceID == "" {
+		newVectorDB.ProviderResourceID = vectorDB.Identifier
+	}
+	if newVectorDB.EmbeddingDimension == 0 {
+		newVectorDB.EmbeddingDimension = MockEmbeddingDimension // Default dimension for mock
+	}
+
+	// Like Llama Stack, registering an existing vector DB again succeeds unless it differs.
+	for _, existingDB := range l.vectorDBs {
+		if existingDB.Identifier == vectorDB.Identifier {
+			if existingDB != newVectorDB {
+				return fmt.Errorf("vector database with identifier '%s' already exists", vectorDB.Identifier)
+			}
+			return nil
+		}
+	}
 
 	// Add to the registered vector DBs
-	l.registeredVectorDBs = append(l.registeredVectorDBs, newVectorDB)
+	l.vectorDBs = append(l.vectorDBs, newVectorDB)
 
 	return nil
 }
@@ -115,25 +244,13 @@ func (l *LlamastackClientMock) InsertDocuments(_ integrations.HTTPClientInterfac
 
 	// Check if vector database exists (both registered and default)
 	vectorDBExists := false
-	for _, vectorDB := range l.registeredVectorDBs {
+	for _, vectorDB := range l.vectorDBs {
 		if vectorDB.Identifier == request.VectorDBID {
 			vectorDBExists = true
 			break
 		}
 	}
 
-	// Check default vector DBs
-	// This is just in case we don't have the vector database in the registered ones
-	if !vectorDBExists {
-		defaultDBs := []string{"default-vector-db-id-1", "default-vector-db-id-2"}
-		for _, defaultDB := range defaultDBs {
-			if defaultDB == request.VectorDBID {
-				vectorDBExists = true
-				break
-			}
-		}
-	}
-
 	// If vector database doesn't exist, create it automatically
 	if !vectorDBExists {
 		fmt.Printf("Mock: Creating vector database '%s'\n", request.VectorDBID)
@@ -148,7 +265,7 @@ func (l *LlamastackClientMock) InsertDocuments(_ integrations.HTTPClientInterfac
 		}
 
 		// Add to the registered vector DBs
-		l.registeredVectorDBs = append(l.registeredVectorDBs, newVectorDB)
+		l.vectorDBs = append(l.vectorDBs, newVectorDB)
 	}
 
 	// Simulate successful document insertion
@@ -158,5 +275,476 @@ func (l *LlamastackClientMock) InsertDocuments(_ integrations.HTTPClientInterfac
 		fmt.Printf("Mock: - Document: %s\n", doc.DocumentID)
 	}
 
+	l.documents[request.VectorDBID] = append(l.documents[request.VectorDBID], request.Documents...)
+
+	return nil
+}
+
+// InsertChunks stores every chunk as a document of its own, which is how QueryDocuments treats
+// inserted documents anyway.
+func (l *LlamastackClientMock) InsertChunks(_ integrations.HTTPClientInterface, request llamastack.VectorIOInsertRequest) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !slices.ContainsFunc(l.vectorDBs, func(vectorDB llamastack.VectorDB) bool {
+		return vectorDB.Identifier == request.VectorDBID
+	}) {
+		return newMockNotFoundError(fmt.Sprintf("vector database %s not found", request.VectorDBID))
+	}
+
+	for _, chunk := range request.Chunks {
+		documentID, _ := chunk.Metadata["document_id"].(string)
+		if documentID == "" {
+			return fmt.Errorf("chunk metadata must contain a document_id")
+		}
+		l.documents[request.VectorDBID] = append(l.documents[request.VectorDBID], llamastack.Document{
+			DocumentID: documentID,
+			Content:    chunk.Content,
+			Metadata:   chunk.Metadata,
+		})
+	}
+
 	return nil
 }
+
+// QueryDocuments scores every inserted document by the share of query words it contains,
+// each document is treated as a single chunk.
+func (l *LlamastackClientMock) QueryDocuments(_ integrations.HTTPClientInterface, request llamastack.RAGQueryRequest) (*llamastack.RAGQueryResult, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	type scoredChunk struct {
+		vectorDBID string
+		document   llamastack.Document
+		score      float64
+	}
+
+	queryWords := strings.Fields(strings.ToLower(request.Content))
+	if len(queryWords) == 0 {
+		return nil, fmt.Errorf("query content is required")
+	}
+
+	var scored []scoredChunk
+	for _, vectorDBID := range request.VectorDBIDs {
+		for _, doc := range l.documents[vectorDBID] {
+			content := strings.ToLower(doc.Content)
+			matches := 0
+			for _, word := range queryWords {
+				if strings.Contains(content, word) {
+					matches++
+				}
+			}
+			if matches > 0 {
+				scored = append(scored, scoredChunk{
+					vectorDBID: vectorDBID,
+					document:   doc,
+					score:      float64(matches) / float64(len(queryWords)),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	maxChunks := 5
+	if request.QueryConfig != nil && request.QueryConfig.MaxChunks > 0 {
+		maxChunks = request.QueryConfig.MaxChunks
+	}
+	if len(scored) > maxChunks {
+		scored = scored[:maxChunks]
+	}
+
+	result := llamastack.RAGQueryResult{
+		Metadata: llamastack.RAGQueryResultMetadata{
+			DocumentIDs: []string{},
+			Chunks:      []llamastack.TextContent{},
+			Scores:      []float64{},
+			VectorDBIDs: []string{},
+		},
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "knowledge_search tool found %d chunks:\nBEGIN of knowledge_search tool results.\n", len(scored))
+	for i, chunk := range scored {
+		fmt.Fprintf(&content, "Result %d:\nDocument_id:%s\nContent: %s\n", i+1, chunk.document.DocumentID, chunk.document.Content)
+
+		result.Metadata.DocumentIDs = append(result.Metadata.DocumentIDs, chunk.document.DocumentID)
+		result.Metadata.Chunks = append(result.Metadata.Chunks, llamastack.TextContent(chunk.document.Content))
+		result.Metadata.Scores = append(result.Metadata.Scores, chunk.score)
+		result.Metadata.VectorDBIDs = append(result.Metadata.VectorDBIDs, chunk.vectorDBID)
+	}
+	content.WriteString("END of knowledge_search tool results.\n")
+	result.Content = llamastack.TextContent(content.String())
+
+	return &result, nil
+}
+
+func (l *LlamastackClientMock) StreamChatCompletion(ctx context.Context, _ integrations.HTTPClientInterface, request llamastack.ChatCompletionRequest) (repositories.ChatCompletionStream, error) {
+	if len(request.Messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	lastMessage := request.Messages[len(request.Messages)-1]
+	answer := fmt.Sprintf("This is a mock response from %s to: %s", request.ModelID, lastMessage.Content)
+	if request.ResponseFormat != nil && request.ResponseFormat.Type == llamastack.JSONSchemaResponseFormatType {
+		answer = mockStructuredAnswer(request.ResponseFormat.JSONSchema)
+	}
+
+	promptTokens := 0
+	for _, message := range request.Messages {
+		promptTokens += len(strings.Fields(message.Content))
+	}
+
+	return newMockChatCompletionStream(ctx, answer, promptTokens), nil
+}
+
+// MockContextLength is the context length reported for default-model-id-1, the other LLM
+// reports none.
+const MockContextLength = 4096
+
+// MockEmbeddingDimension matches the embedding dimension of the mock vector databases.
+const MockEmbeddingDimension = 384
+
+// Embeddings fakes embeddings by hashing the words of every text into a fixed number of
+// buckets, so the same text always yields the same vector and texts sharing words are close.
+func (l *LlamastackClientMock) Embeddings(_ integrations.HTTPClientInterface, request llamastack.EmbeddingsRequest) (*llamastack.EmbeddingsResponse, error) {
+	embeddings := make([][]float64, 0, len(request.Contents))
+
+	for _, content := range request.Contents {
+		vector := make([]float64, MockEmbeddingDimension)
+		for _, word := range strings.Fields(strings.ToLower(content)) {
+			hash := fnv.New64a()
+			_, _ = hash.Write([]byte(word))
+			sum := hash.Sum64()
+
+			// The top bit picks the sign so buckets do not only grow.
+			sign := 1.0
+			if sum>>63 == 1 {
+				sign = -1.0
+			}
+			vector[sum%MockEmbeddingDimension] += sign
+		}
+
+		var norm float64
+		for _, value := range vector {
+			norm += value * value
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for i := range vector {
+				vector[i] /= norm
+			}
+		}
+
+		embeddings = append(embeddings, vector)
+	}
+
+	return &llamastack.EmbeddingsResponse{Embeddings: embeddings}, nil
+}
+
+func (l *LlamastackClientMock) CreateAgent(_ integrations.HTTPClientInterface, config llamastack.AgentConfig) (string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	agentID := uuid.NewString()
+	l.agents[agentID] = config
+	l.agentSessions[agentID] = map[string]*llamastack.Session{}
+
+	return agentID, nil
+}
+
+func (l *LlamastackClientMock) GetAgent(_ integrations.HTTPClientInterface, agentID string) (*llamastack.Agent, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	config, ok := l.agents[agentID]
+	if !ok {
+		return nil, newMockNotFoundError(fmt.Sprintf("agent %s not found", agentID))
+	}
+
+	return &llamastack.Agent{AgentID: agentID, AgentConfig: config}, nil
+}
+
+func (l *LlamastackClientMock) CreateAgentSession(_ integrations.HTTPClientInterface, agentID string, sessionName string) (string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	sessions, ok := l.agentSessions[agentID]
+	if !ok {
+		return "", newMockNotFoundError(fmt.Sprintf("agent %s not found", agentID))
+	}
+
+	sessionID := uuid.NewString()
+	sessions[sessionID] = &llamastack.Session{
+		SessionID:   sessionID,
+		SessionName: sessionName,
+		Turns:       []llamastack.Turn{},
+		StartedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return sessionID, nil
+}
+
+// StreamAgentTurn answers like StreamChatCompletion, wrapped in the step events of an agent
+// turn. A shield call step is added when the agent has input shields.
+func (l *LlamastackClientMock) StreamAgentTurn(ctx context.Context, _ integrations.HTTPClientInterface, agentID string, sessionID string, request llamastack.AgentTurnCreateRequest) (repositories.AgentTurnStream, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	config, ok := l.agents[agentID]
+	if !ok {
+		return nil, newMockNotFoundError(fmt.Sprintf("agent %s not found", agentID))
+	}
+	session, ok := l.agentSessions[agentID][sessionID]
+	if !ok {
+		return nil, newMockNotFoundError(fmt.Sprintf("session %s not found", sessionID))
+	}
+	if len(request.Messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	turnID := uuid.NewString()
+	answer := fmt.Sprintf("This is a mock agent response from %s to: %s", config.Model, request.Messages[len(request.Messages)-1].Content)
+
+	var events []llamastack.AgentTurnResponseStreamChunk
+	var steps []json.RawMessage
+	addEvent := func(payload llamastack.AgentTurnResponseEventPayload) {
+		events = append(events, llamastack.AgentTurnResponseStreamChunk{
+			Event: llamastack.AgentTurnResponseEvent{Payload: payload},
+		})
+	}
+	addStep := func(stepType string, details map[string]any, deltas []string) {
+		stepID := uuid.NewString()
+		details["step_id"] = stepID
+		details["step_type"] = stepType
+		details["turn_id"] = turnID
+		detailsJSON, _ := json.Marshal(details)
+
+		addEvent(llamastack.AgentTurnResponseEventPayload{EventType: llamastack.StepStartEventType, StepType: stepType, StepID: stepID})
+		for _, delta := range deltas {
+			deltaJSON, _ := json.Marshal(llamastack.ContentDelta{Type: "text", Text: delta})
+			addEvent(llamastack.AgentTurnResponseEventPayload{EventType: llamastack.StepProgressEventType, StepType: stepType, StepID: stepID, Delta: deltaJSON})
+		}
+		addEvent(llamastack.AgentTurnResponseEventPayload{EventType: llamastack.StepCompleteEventType, StepType: stepType, StepID: stepID, StepDetails: detailsJSON})
+		steps = append(steps, detailsJSON)
+	}
+
+	addEvent(llamastack.AgentTurnResponseEventPayload{EventType: llamastack.TurnStartEventType, TurnID: turnID})
+	if len(config.InputShields) > 0 {
+		addStep(llamastack.ShieldCallStepType, map[string]any{"violation": nil}, nil)
+	}
+	addStep(llamastack.InferenceStepType, map[string]any{
+		"model_response": map[string]any{"role": llamastack.AssistantRole, "content": answer, "stop_reason": "end_of_turn"},
+	}, strings.SplitAfter(answer, " "))
+
+	inputMessages := make([]llamastack.TurnMessage, 0, len(request.Messages))
+	for _, message := range request.Messages {
+		inputMessages = append(inputMessages, llamastack.TurnMessage{Role: message.Role, Content: llamastack.TextContent(message.Content)})
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	turn := llamastack.Turn{
+		TurnID:        turnID,
+		SessionID:     sessionID,
+		InputMessages: inputMessages,
+		Steps:         steps,
+		OutputMessage: &llamastack.TurnMessage{Role: llamastack.AssistantRole, Content: llamastack.TextContent(answer), StopReason: "end_of_turn"},
+		StartedAt:     now,
+		CompletedAt:   now,
+	}
+	addEvent(llamastack.AgentTurnResponseEventPayload{EventType: llamastack.TurnCompleteEventType, Turn: &turn})
+
+	session.Turns = append(session.Turns, turn)
+
+	return &mockEventStream[llamastack.AgentTurnResponseStreamChunk]{ctx: ctx, events: events}, nil
+}
+
+func (l *LlamastackClientMock) GetAgentSession(_ integrations.HTTPClientInterface, agentID string, sessionID string) (*llamastack.Session, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	session, ok := l.agentSessions[agentID][sessionID]
+	if !ok {
+		return nil, newMockNotFoundError(fmt.Sprintf("session %s not found", sessionID))
+	}
+
+	sessionCopy := *session
+	sessionCopy.Turns = append([]llamastack.Turn{}, session.Turns...)
+
+	return &sessionCopy, nil
+}
+
+// newMockNotFoundError mirrors the error the REST client returns for a Llama Stack 404.
+func newMockNotFoundError(message string) error {
+	return &integrations.HTTPError{
+		StatusCode: http.StatusNotFound,
+		ErrorResponse: integrations.ErrorResponse{
+			Code:    strconv.Itoa(http.StatusNotFound),
+			Message: message,
+		},
+	}
+}
+
+var defaultToolGroups = []llamastack.ToolGroup{
+	{
+		Identifier:         "builtin::rag",
+		ProviderID:         "rag-runtime",
+		ProviderResourceID: "builtin::rag",
+	},
+	{
+		Identifier:         "builtin::websearch",
+		ProviderID:         "tavily-search",
+		ProviderResourceID: "builtin::websearch",
+	},
+}
+
+var defaultTools = []llamastack.Tool{
+	{
+		Identifier:         "knowledge_search",
+		ProviderID:         "rag-runtime",
+		ProviderResourceID: "knowledge_search",
+		ToolGroupID:        "builtin::rag",
+		Description:        "Search for information in a database.",
+		Parameters: []llamastack.ToolParameter{
+			{Name: "query", ParameterType: "string", Description: "The query to search for. Can be a natural language sentence or keywords.", Required: true},
+		},
+	},
+	{
+		Identifier:         "web_search",
+		ProviderID:         "tavily-search",
+		ProviderResourceID: "web_search",
+		ToolGroupID:        "builtin::websearch",
+		Description:        "Search the web for information",
+		Parameters: []llamastack.ToolParameter{
+			{Name: "query", ParameterType: "string", Description: "The query to search for", Required: true},
+		},
+	},
+}
+
+func (l *LlamastackClientMock) GetAllToolGroups(_ integrations.HTTPClientInterface) (*llamastack.ToolGroupList, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	data := llamastack.ToolGroupList{
+		Data: append(append([]llamastack.ToolGroup{}, defaultToolGroups...), l.registeredToolGroups...),
+	}
+
+	return &data, nil
+}
+
+// GetAllTools only knows the tools of the default tool groups, registered tool groups have none.
+func (l *LlamastackClientMock) GetAllTools(client integrations.HTTPClientInterface, toolGroupID string) (*llamastack.ToolList, error) {
+	if toolGroupID == "" {
+		return &llamastack.ToolList{Data: append([]llamastack.Tool{}, defaultTools...)}, nil
+	}
+
+	toolGroups, err := l.GetAllToolGroups(client)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, toolGroup := range toolGroups.Data {
+		if toolGroup.Identifier == toolGroupID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, newMockNotFoundError(fmt.Sprintf("tool group %s not found", toolGroupID))
+	}
+
+	data := llamastack.ToolList{Data: []llamastack.Tool{}}
+	for _, tool := range defaultTools {
+		if tool.ToolGroupID == toolGroupID {
+			data.Data = append(data.Data, tool)
+		}
+	}
+
+	return &data, nil
+}
+
+func (l *LlamastackClientMock) RegisterToolGroup(_ integrations.HTTPClientInterface, request llamastack.ToolGroupRegistrationRequest) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, toolGroup := range append(append([]llamastack.ToolGroup{}, defaultToolGroups...), l.registeredToolGroups...) {
+		if toolGroup.Identifier == request.ToolGroupID {
+			return fmt.Errorf("tool group with identifier '%s' already exists", request.ToolGroupID)
+		}
+	}
+
+	l.registeredToolGroups = append(l.registeredToolGroups, llamastack.ToolGroup{
+		Identifier:         request.ToolGroupID,
+		ProviderID:         request.ProviderID,
+		ProviderResourceID: request.ToolGroupID,
+		MCPEndpoint:        request.MCPEndpoint,
+		Args:               request.Args,
+	})
+
+	return nil
+}
+
+// MockUnsafeKeyword makes the mock shields report a violation for any message containing it.
+const MockUnsafeKeyword = "forbidden"
+
+func (l *LlamastackClientMock) GetAllShields(_ integrations.HTTPClientInterface) (*llamastack.ShieldList, error) {
+	data := llamastack.ShieldList{
+		Data: []llamastack.Shield{
+			{
+				Identifier:         "llama-guard",
+				ProviderID:         "llama-guard",
+				ProviderResourceID: "meta-llama/Llama-Guard-3-8B",
+			},
+		},
+	}
+
+	return &data, nil
+}
+
+func (l *LlamastackClientMock) RunShield(client integrations.HTTPClientInterface, request llamastack.RunShieldRequest) (*llamastack.RunShieldResponse, error) {
+	shields, err := l.GetAllShields(client)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, shield := range shields.Data {
+		if shield.Identifier == request.ShieldID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, newMockNotFoundError(fmt.Sprintf("shield %s not found", request.ShieldID))
+	}
+
+	for _, message := range request.Messages {
+		if strings.Contains(strings.ToLower(message.Content), MockUnsafeKeyword) {
+			return &llamastack.RunShieldResponse{
+				Violation: &llamastack.SafetyViolation{
+					ViolationLevel: llamastack.ErrorViolationLevel,
+					UserMessage:    "I can't answer that. Can I help with something else?",
+					Metadata:       map[string]any{"violation_type": "S1"},
+				},
+			}, nil
+		}
+	}
+
+	return &llamastack.RunShieldResponse{}, nil
+}
+
+func (l *LlamastackClientMock) GetAllProviders(_ integrations.HTTPClientInterface) (*llamastack.ProviderList, error) {
+	return &llamastack.ProviderList{
+		Data: []llamastack.Provider{
+			{API: "inference", ProviderID: "default-provider-id-1", ProviderType: "remote::vllm"},
+			{API: "inference", ProviderID: "default-provider-id-2", ProviderType: "inline::sentence-transformers"},
+			{API: llamastack.VectorIOProviderAPI, ProviderID: "faiss", ProviderType: "inline::faiss"},
+			{API: llamastack.VectorIOProviderAPI, ProviderID: "milvus", ProviderType: "inline::milvus"},
+			{API: llamastack.VectorIOProviderAPI, ProviderID: "pgvector", ProviderType: "remote::pgvector", Config: map[string]any{"password": "mock-password"}},
+			{API: "safety", ProviderID: "llama-guard", ProviderType: "inline::llama-guard"},
+		},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+// mockEventStream replays a fixed list of events, mimicking a streamed Llama Stack response.
+// Like a real response body it fails once the context of the request is cancelled.
+type mockEventStream[T any] struct {
+	ctx    context.Context
+	events []T
+	index  int
+}
+
+// newMockChatCompletionStream streams the answer word by word. Token metrics are faked from
+// the number of words so consumers relying on them get stable values.
+func newMockChatCompletionStream(ctx context.Context, answer string, promptTokens int) *mockEventStream[llamastack.ChatCompletionResponseStreamChunk] {
+	words := strings.SplitAfter(answer, " ")
+
+	chunks := []llamastack.ChatCompletionResponseStreamChunk{
+		{Event: llamastack.ChatCompletionResponseEvent{
+			EventType: llamastack.StartEventType,
+			Delta:     llamastack.ContentDelta{Type: "text"},
+		}},
+	}
+
+	for _, word := range words {
+		chunks = append(chunks, llamastack.ChatCompletionResponseStreamChunk{
+			Event: llamastack.ChatCompletionResponseEvent{
+				EventType: llamastack.ProgressEventType,
+				Delta:     llamastack.ContentDelta{Type: "text", Text: word},
+			},
+		})
+	}
+
+	chunks = append(chunks, llamastack.ChatCompletionResponseStreamChunk{
+		Event: llamastack.ChatCompletionResponseEvent{
+			EventType:  llamastack.CompleteEventType,
+			Delta:      llamastack.ContentDelta{Type: "text"},
+			StopReason: "end_of_turn",
+		},
+		Metrics: []llamastack.Metric{
+			{Metric: "prompt_tokens", Value: float64(promptTokens)},
+			{Metric: "completion_tokens", Value: float64(len(words))},
+			{Metric: "total_tokens", Value: float64(promptTokens + len(words))},
+		},
+	})
+
+	return &mockEventStream[llamastack.ChatCompletionResponseStreamChunk]{ctx: ctx, events: chunks}
+}
+
+func (m *mockEventStream[T]) Recv() (*T, error) {
+	if err := m.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if m.index >= len(m.events) {
+		return nil, io.EOF
+	}
+
+	event := m.events[m.index]
+	m.index++
+
+	return &event, nil
+}
+
+func (m *mockEventStream[T]) Close() error {
+	return nil
+}
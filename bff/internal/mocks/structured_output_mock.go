@@ -0,0 +1,114 @@
+package mocks
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+// mockStructuredAnswer builds a JSON answer from schema the way a model with structured output
+// would, every property is filled with a placeholder of its type. Keywords other than type,
+// properties, items, enum, const, the lower bounds, the first anyOf, oneOf or allOf entry and
+// local $ref are ignored, so e.g. patterns are not satisfied.
+// maxMockSchemaSize caps the lengths and item counts taken from the schema, a huge lower bound
+// would otherwise exhaust memory.
+const maxMockSchemaSize = 100
+
+func mockStructuredAnswer(schema json.RawMessage) string {
+	var root any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return "{}"
+	}
+
+	value := mockSchemaValue(root, root, 0)
+	js, err := json.Marshal(value)
+	if err != nil {
+		return "{}"
+	}
+	return string(js)
+}
+
+func mockSchemaValue(root any, node any, depth int) any {
+	schema, ok := node.(map[string]any)
+	// Stops recursive schemas from recursing forever.
+	if !ok || depth > 8 {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target := root
+		for _, token := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+			if token == "" {
+				continue
+			}
+			parent, _ := target.(map[string]any)
+			target = parent[token]
+		}
+		return mockSchemaValue(root, target, depth+1)
+	}
+	if constant, ok := schema["const"]; ok {
+		return constant
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		if subschemas, ok := schema[keyword].([]any); ok && len(subschemas) > 0 {
+			return mockSchemaValue(root, subschemas[0], depth+1)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]any); ok && len(types) > 0 {
+		schemaType, _ = types[0].(string)
+	}
+	if schemaType == "" {
+		if _, ok := schema["properties"]; ok {
+			schemaType = "object"
+		}
+	}
+
+	switch schemaType {
+	case "object":
+		properties, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		object := map[string]any{}
+		for _, name := range names {
+			object[name] = mockSchemaValue(root, properties[name], depth+1)
+		}
+		return object
+	case "array":
+		count := 1
+		if minItems, ok := schema["minItems"].(float64); ok && minItems > float64(count) {
+			count = int(min(minItems, maxMockSchemaSize))
+		}
+		items := make([]any, count)
+		for i := range items {
+			items[i] = mockSchemaValue(root, schema["items"], depth+1)
+		}
+		return items
+	case "string":
+		value := "mock"
+		if minLength, ok := schema["minLength"].(float64); ok && minLength > float64(len(value)) {
+			value += strings.Repeat("x", int(min(minLength, maxMockSchemaSize))-len(value))
+		}
+		return value
+	case "integer", "number":
+		if minimum, ok := schema["minimum"].(float64); ok {
+			return math.Ceil(minimum)
+		}
+		if minimum, ok := schema["exclusiveMinimum"].(float64); ok {
+			return math.Floor(minimum) + 1
+		}
+		return 1
+	case "boolean":
+		return true
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import "encoding/json"
+
+type Agent struct {
+	AgentID string `json:"agent_id"`
+}
+
+type AgentSession struct {
+	SessionID   string      `json:"session_id"`
+	SessionName string      `json:"session_name"`
+	StartedAt   string      `json:"started_at,omitempty"`
+	Turns       []AgentTurn `json:"turns"`
+}
+
+type AgentMessage struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+type AgentTurn struct {
+	TurnID        string         `json:"turn_id"`
+	InputMessages []AgentMessage `json:"input_messages"`
+	OutputMessage *AgentMessage  `json:"output_message,omitempty"`
+	// Inference, tool execution and shield call steps as reported by Llama Stack.
+	Steps       []json.RawMessage `json:"steps"`
+	StartedAt   string            `json:"started_at,omitempty"`
+	CompletedAt string            `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type ConversationMessage struct {
+	ID      string `json:"id"`
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// Only set on assistant messages, e.g. "end_of_turn".
+	StopReason string    `json:"stop_reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Conversation struct {
+	ID          string                `json:"id"`
+	UserID      string                `json:"user_id"`
+	Title       string                `json:"title"`
+	ModelID     string                `json:"model_id,omitempty"`
+	VectorDBIDs []string              `json:"vector_db_ids,omitempty"`
+	Messages    []ConversationMessage `json:"messages"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// ConversationSummary is a conversation without its messages, as returned when listing.
+type ConversationSummary struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	ModelID      string    `json:"model_id,omitempty"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type ConversationList struct {
+	Items []ConversationSummary `json:"items"`
+}
+
+// ConversationUpdate holds the fields of a conversation that can be changed, nil fields are
+// left untouched.
+type ConversationUpdate struct {
+	Title       *string   `json:"title,omitempty"`
+	ModelID     *string   `json:"model_id,omitempty"`
+	VectorDBIDs *[]string `json:"vector_db_ids,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+type Embedding struct {
+	Index  int       `json:"index"`
+	Vector []float64 `json:"vector"`
+}
+
+type EmbeddingsResult struct {
+	ModelID    string      `json:"model_id"`
+	Dimensions int         `json:"dimensions"`
+	Items      []Embedding `json:"items"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ListMetadata accompanies paginated lists.
+type ListMetadata struct {
+	// The number of items matching the filters of the request, across all pages.
+	TotalCount int `json:"total_count"`
+	// Passed as page_token to get the next page, empty on the last page.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
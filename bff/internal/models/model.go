@@ -1,15 +1,20 @@
 package models
 
+import "time"
+
 type Model struct {
 	Identifier string `json:"identifier"`
 	ProviderID string `json:"provider_id"`
 	// Can be either llm or embedding
 	ModelType          string `json:"model_type"`
 	ProviderResourceID string `json:"provider_resource_id"`
+	// Provider specific details as reported by Llama Stack, e.g. context_length or
+	// embedding_dimension.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
-// Note: Always create a bespoke type for list types, this creates minimal work later if implementing pagination
-// as the necessary metadata can be added at a later date without breaking the API.
+// Note: Always create a bespoke type for list types, pagination details are sent as ListMetadata
+// in the metadata of the envelope.
 
 type ModelList struct {
 	Items []Model `json:"items"`
@@ -21,7 +26,32 @@ type VectorDB struct {
 	Identifier         string `json:"identifier"`
 	ProviderID         string `json:"provider_id"`
 	ProviderResourceID string `json:"provider_resource_id"`
+	// The user who registered the vector database, empty when it was registered without
+	// authentication.
+	Owner string `json:"owner,omitempty"`
+}
+
+// VectorDBOwner records the user who registered a vector database through the BFF. Llama Stack
+// does not know the users of the BFF, ownership is tracked on this side.
+type VectorDBOwner struct {
+	VectorDBID string    `json:"vector_db_id"`
+	UserID     string    `json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
 }
+
 type VectorDBList struct {
 	Items []VectorDB `json:"items"`
 }
+
+type RAGChunk struct {
+	DocumentID string         `json:"document_id"`
+	Content    string         `json:"content"`
+	Score      float64        `json:"score"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+type RAGQueryResult struct {
+	// The retrieved context as Llama Stack would inject it into a prompt.
+	Content string     `json:"content"`
+	Chunks  []RAGChunk `json:"chunks"`
+}
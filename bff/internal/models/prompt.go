@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// PromptTemplateVersion is an immutable revision of a prompt template, every content change
+// adds a new one.
+type PromptTemplateVersion struct {
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	Variables []string  `json:"variables"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PromptTemplate is a reusable system prompt shared by all users of the BFF. Content, Variables
+// and Version mirror the latest entry of Versions.
+type PromptTemplate struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Content     string                  `json:"content"`
+	Variables   []string                `json:"variables"`
+	Version     int                     `json:"version"`
+	Versions    []PromptTemplateVersion `json:"versions"`
+	CreatedBy   string                  `json:"created_by"`
+	UpdatedBy   string                  `json:"updated_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// PromptTemplateSummary is a prompt template without its history, as returned when listing.
+type PromptTemplateSummary struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Variables   []string  `json:"variables"`
+	Version     int       `json:"version"`
+	UpdatedBy   string    `json:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PromptTemplateList struct {
+	Items []PromptTemplateSummary `json:"items"`
+}
+
+type PromptTemplateVersionList struct {
+	Items []PromptTemplateVersion `json:"items"`
+}
+
+// PromptTemplateUpdate holds the fields of a prompt template that can be changed, nil fields
+// are left untouched. A new content creates a new version.
+type PromptTemplateUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Content     *string `json:"content,omitempty"`
+}
+
+// RenderedPrompt is a prompt template version with its variables substituted.
+type RenderedPrompt struct {
+	TemplateID string `json:"template_id"`
+	Version    int    `json:"version"`
+	Content    string `json:"content"`
+}
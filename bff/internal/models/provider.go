@@ -0,0 +1,13 @@
+package models
+
+// Provider is a provider configured in the Llama Stack distribution. Its configuration is left
+// out as it may hold credentials.
+type Provider struct {
+	API          string `json:"api"`
+	ProviderID   string `json:"provider_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+type ProviderList struct {
+	Items []Provider `json:"items"`
+}
@@ -0,0 +1,20 @@
+package models
+
+type Shield struct {
+	Identifier         string `json:"identifier"`
+	ProviderID         string `json:"provider_id"`
+	ProviderResourceID string `json:"provider_resource_id"`
+}
+
+type ShieldList struct {
+	Items []Shield `json:"items"`
+}
+
+type SafetyViolation struct {
+	ShieldID string `json:"shield_id"`
+	// Either "input" or "output", depending on whether the prompt or the answer was blocked.
+	Stage    string         `json:"stage"`
+	Level    string         `json:"level"`
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
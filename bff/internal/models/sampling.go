@@ -0,0 +1,52 @@
+package models
+
+// SamplingParams holds sampling parameter values, nil fields are not set.
+type SamplingParams struct {
+	Temperature       *float64 `json:"temperature,omitempty"`
+	TopP              *float64 `json:"top_p,omitempty"`
+	MaxTokens         *int     `json:"max_tokens,omitempty"`
+	RepetitionPenalty *float64 `json:"repetition_penalty,omitempty"`
+}
+
+// ParameterRange is an inclusive range of allowed values.
+type ParameterRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type SamplingParamRanges struct {
+	Temperature       *ParameterRange `json:"temperature,omitempty"`
+	TopP              *ParameterRange `json:"top_p,omitempty"`
+	MaxTokens         *ParameterRange `json:"max_tokens,omitempty"`
+	RepetitionPenalty *ParameterRange `json:"repetition_penalty,omitempty"`
+}
+
+// Merge returns s with every parameter set in other replaced by the value of other.
+func (s SamplingParams) Merge(other SamplingParams) SamplingParams {
+	if other.Temperature != nil {
+		s.Temperature = other.Temperature
+	}
+	if other.TopP != nil {
+		s.TopP = other.TopP
+	}
+	if other.MaxTokens != nil {
+		s.MaxTokens = other.MaxTokens
+	}
+	if other.RepetitionPenalty != nil {
+		s.RepetitionPenalty = other.RepetitionPenalty
+	}
+	return s
+}
+
+// IsEmpty reports whether no parameter is set.
+func (s SamplingParams) IsEmpty() bool {
+	return s == SamplingParams{}
+}
+
+// ModelParameters holds the sampling defaults chat requests for a model are merged with and
+// the ranges user supplied values must fall in.
+type ModelParameters struct {
+	ModelID  string              `json:"model_id"`
+	Defaults SamplingParams      `json:"defaults"`
+	Ranges   SamplingParamRanges `json:"ranges"`
+}
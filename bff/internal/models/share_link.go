@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Share link audit events.
+const (
+	ShareLinkCreatedEvent  = "created"
+	ShareLinkResolvedEvent = "resolved"
+	ShareLinkChatEvent     = "chat"
+	ShareLinkRevokedEvent  = "revoked"
+	ShareLinkRejectedEvent = "rejected"
+)
+
+// ChatbotConfig is the snapshot of a chatbot a share link gives access to.
+type ChatbotConfig struct {
+	ModelID        string          `json:"model_id"`
+	SystemPrompt   string          `json:"system_prompt,omitempty"`
+	VectorDBIDs    []string        `json:"vector_db_ids,omitempty"`
+	SamplingParams *SamplingParams `json:"sampling_params,omitempty"`
+}
+
+type ShareLinkAuditEntry struct {
+	Event      string    `json:"event"`
+	Timestamp  time.Time `json:"timestamp"`
+	UserID     string    `json:"user_id,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+}
+
+type ShareLink struct {
+	ID        string        `json:"id"`
+	CreatedBy string        `json:"created_by"`
+	Config    ChatbotConfig `json:"config"`
+	CreatedAt time.Time     `json:"created_at"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	RevokedAt *time.Time    `json:"revoked_at,omitempty"`
+	// Token is derived from the link and never stored, it is only filled in for its owner.
+	Token string                `json:"token,omitempty"`
+	Audit []ShareLinkAuditEntry `json:"audit,omitempty"`
+}
+
+type ShareLinkList struct {
+	Items []ShareLink `json:"items"`
+}
+
+// SharedChatbot is what a share token resolves to for the people it was shared with, the
+// system prompt stays with the owner.
+type SharedChatbot struct {
+	ModelID        string          `json:"model_id"`
+	VectorDBIDs    []string        `json:"vector_db_ids,omitempty"`
+	SamplingParams *SamplingParams `json:"sampling_params,omitempty"`
+	ExpiresAt      time.Time       `json:"expires_at"`
+}
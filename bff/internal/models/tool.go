@@ -0,0 +1,33 @@
+package models
+
+type ToolGroup struct {
+	Identifier         string         `json:"identifier"`
+	ProviderID         string         `json:"provider_id"`
+	ProviderResourceID string         `json:"provider_resource_id"`
+	MCPEndpoint        string         `json:"mcp_endpoint,omitempty"`
+	Args               map[string]any `json:"args,omitempty"`
+}
+
+type ToolGroupList struct {
+	Items []ToolGroup `json:"items"`
+}
+
+type ToolParameter struct {
+	Name          string `json:"name"`
+	ParameterType string `json:"parameter_type"`
+	Description   string `json:"description"`
+	Required      bool   `json:"required"`
+	Default       any    `json:"default,omitempty"`
+}
+
+type Tool struct {
+	Identifier  string          `json:"identifier"`
+	ToolGroupID string          `json:"toolgroup_id"`
+	ProviderID  string          `json:"provider_id"`
+	Description string          `json:"description"`
+	Parameters  []ToolParameter `json:"parameters"`
+}
+
+type ToolList struct {
+	Items []Tool `json:"items"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// UploadPreview shows the chunks an upload would store, token counts are estimates.
+type UploadPreview struct {
+	ChunkingStrategy     string            `json:"chunking_strategy"`
+	ChunkSizeInTokens    int               `json:"chunk_size_in_tokens"`
+	ChunkOverlapInTokens int               `json:"chunk_overlap_in_tokens"`
+	TotalChunks          int               `json:"total_chunks"`
+	TotalTokens          int               `json:"total_tokens"`
+	Documents            []DocumentPreview `json:"documents"`
+}
+
+type DocumentPreview struct {
+	DocumentID string         `json:"document_id"`
+	TokenCount int            `json:"token_count"`
+	Chunks     []ChunkPreview `json:"chunks"`
+	Warnings   []string       `json:"warnings,omitempty"`
+}
+
+type ChunkPreview struct {
+	Content    string `json:"content"`
+	TokenCount int    `json:"token_count"`
+	// The metadata the chunk would be stored with, including its position in the document.
+	Metadata map[string]any `json:"metadata"`
+}
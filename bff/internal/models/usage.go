@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Usage sources, the BFF route an inference went through.
+const (
+	ChatCompletionUsageSource = "chat_completion"
+	AgentTurnUsageSource      = "agent_turn"
+	EmbeddingsUsageSource     = "embeddings"
+	ProxyUsageSource          = "proxy"
+)
+
+// UsageRecord is the token usage and latency of a single inference.
+type UsageRecord struct {
+	UserID           string    `json:"user_id"`
+	ModelID          string    `json:"model_id"`
+	Source           string    `json:"source"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	TotalTokens      int64     `json:"total_tokens"`
+	LatencyMs        int64     `json:"latency_ms"`
+	Timestamp        time.Time `json:"timestamp"`
+	// Estimated is set when Llama Stack reports no usage and the token counts are estimated
+	// from the text, as for agent turns and embeddings.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// UsageSummary aggregates the usage records of a single user or model, depending on the
+// grouping of the report.
+type UsageSummary struct {
+	UserID           string  `json:"user_id,omitempty"`
+	ModelID          string  `json:"model_id,omitempty"`
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+}
+
+type UsageReport struct {
+	GroupBy string `json:"group_by"`
+	// UserID is set when the report only covers the usage of one user.
+	UserID string         `json:"user_id,omitempty"`
+	From   *time.Time     `json:"from,omitempty"`
+	To     *time.Time     `json:"to,omitempty"`
+	Items  []UsageSummary `json:"items"`
+}
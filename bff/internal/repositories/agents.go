@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const agentsPath = "/v1/agents"
+
+// AgentTurnStream yields the events of a streamed agent turn.
+type AgentTurnStream = EventStream[llamastack.AgentTurnResponseStreamChunk]
+
+// AgentsInterface defines the interface for agent operations
+type AgentsInterface interface {
+	CreateAgent(client integrations.HTTPClientInterface, config llamastack.AgentConfig) (string, error)
+	GetAgent(client integrations.HTTPClientInterface, agentID string) (*llamastack.Agent, error)
+	CreateAgentSession(client integrations.HTTPClientInterface, agentID string, sessionName string) (string, error)
+	StreamAgentTurn(ctx context.Context, client integrations.HTTPClientInterface, agentID string, sessionID string, request llamastack.AgentTurnCreateRequest) (AgentTurnStream, error)
+	GetAgentSession(client integrations.HTTPClientInterface, agentID string, sessionID string) (*llamastack.Session, error)
+}
+
+type UIAgents struct {
+}
+
+func (a UIAgents) CreateAgent(client integrations.HTTPClientInterface, config llamastack.AgentConfig) (string, error) {
+	jsonBody, err := json.Marshal(llamastack.AgentCreateRequest{AgentConfig: config})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(agentsPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	var created llamastack.AgentCreateResponse
+	if err := json.Unmarshal(response, &created); err != nil {
+		return "", fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return created.AgentID, nil
+}
+
+func (a UIAgents) GetAgent(client integrations.HTTPClientInterface, agentID string) (*llamastack.Agent, error) {
+	response, err := client.GET(fmt.Sprintf("%s/%s", agentsPath, url.PathEscape(agentID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve agent: %w", err)
+	}
+
+	var agent llamastack.Agent
+	if err := json.Unmarshal(response, &agent); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &agent, nil
+}
+
+func (a UIAgents) CreateAgentSession(client integrations.HTTPClientInterface, agentID string, sessionName string) (string, error) {
+	jsonBody, err := json.Marshal(llamastack.AgentSessionCreateRequest{SessionName: sessionName})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(agentSessionsPath(agentID), bytes.NewReader(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create agent session: %w", err)
+	}
+
+	var created llamastack.AgentSessionCreateResponse
+	if err := json.Unmarshal(response, &created); err != nil {
+		return "", fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return created.SessionID, nil
+}
+
+func (a UIAgents) StreamAgentTurn(ctx context.Context, client integrations.HTTPClientInterface, agentID string, sessionID string, request llamastack.AgentTurnCreateRequest) (AgentTurnStream, error) {
+	request.Stream = true
+
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	body, err := client.POSTStream(ctx, agentSessionPath(agentID, sessionID)+"/turn", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start agent turn: %w", err)
+	}
+
+	return newSSEEventStream[llamastack.AgentTurnResponseStreamChunk](body), nil
+}
+
+func (a UIAgents) GetAgentSession(client integrations.HTTPClientInterface, agentID string, sessionID string) (*llamastack.Session, error) {
+	response, err := client.GET(agentSessionPath(agentID, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve agent session: %w", err)
+	}
+
+	var session llamastack.Session
+	if err := json.Unmarshal(response, &session); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &session, nil
+}
+
+func agentSessionsPath(agentID string) string {
+	return fmt.Sprintf("%s/%s/session", agentsPath, url.PathEscape(agentID))
+}
+
+func agentSessionPath(agentID string, sessionID string) string {
+	return fmt.Sprintf("%s/%s", agentSessionsPath(agentID), url.PathEscape(sessionID))
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ConversationStore persists conversations, it is not concerned with ownership.
+type ConversationStore interface {
+	Get(id string) (models.Conversation, error)
+	GetAll() ([]models.Conversation, error)
+	Save(conversation models.Conversation) error
+	Delete(id string) error
+}
+
+// ConversationRepository manages the conversations of the BFF users, every operation is scoped
+// to a single user and conversations of other users are reported as not found.
+type ConversationRepository struct {
+	store ConversationStore
+	// Serializes read-modify-write cycles against the store.
+	mutex sync.Mutex
+}
+
+func NewConversationRepository(store ConversationStore) *ConversationRepository {
+	return &ConversationRepository{store: store}
+}
+
+func (r *ConversationRepository) ListConversations(userID string) (models.ConversationList, error) {
+	conversations, err := r.store.GetAll()
+	if err != nil {
+		return models.ConversationList{}, err
+	}
+
+	items := []models.ConversationSummary{}
+	for _, conversation := range conversations {
+		if conversation.UserID != userID {
+			continue
+		}
+		items = append(items, models.ConversationSummary{
+			ID:           conversation.ID,
+			Title:        conversation.Title,
+			ModelID:      conversation.ModelID,
+			MessageCount: len(conversation.Messages),
+			CreatedAt:    conversation.CreatedAt,
+			UpdatedAt:    conversation.UpdatedAt,
+		})
+	}
+
+	// Most recently active first.
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt.After(items[j].UpdatedAt)
+	})
+
+	return models.ConversationList{Items: items}, nil
+}
+
+func (r *ConversationRepository) GetConversation(userID string, id string) (models.Conversation, error) {
+	conversation, err := r.store.Get(id)
+	if err != nil {
+		return models.Conversation{}, err
+	}
+
+	if conversation.UserID != userID {
+		return models.Conversation{}, ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+func (r *ConversationRepository) CreateConversation(userID string, conversation models.Conversation) (models.Conversation, error) {
+	now := time.Now().UTC()
+
+	conversation.ID = uuid.NewString()
+	conversation.UserID = userID
+	conversation.CreatedAt = now
+	conversation.UpdatedAt = now
+	conversation.Messages = newConversationMessages(conversation.Messages, now)
+
+	if err := r.store.Save(conversation); err != nil {
+		return models.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (r *ConversationRepository) UpdateConversation(userID string, id string, update models.ConversationUpdate) (models.Conversation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	conversation, err := r.GetConversation(userID, id)
+	if err != nil {
+		return models.Conversation{}, err
+	}
+
+	if update.Title != nil {
+		conversation.Title = *update.Title
+	}
+	if update.ModelID != nil {
+		conversation.ModelID = *update.ModelID
+	}
+	if update.VectorDBIDs != nil {
+		conversation.VectorDBIDs = *update.VectorDBIDs
+	}
+	conversation.UpdatedAt = time.Now().UTC()
+
+	if err := r.store.Save(conversation); err != nil {
+		return models.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (r *ConversationRepository) AppendMessages(userID string, id string, messages []models.ConversationMessage) (models.Conversation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	conversation, err := r.GetConversation(userID, id)
+	if err != nil {
+		return models.Conversation{}, err
+	}
+
+	now := time.Now().UTC()
+	conversation.Messages = append(conversation.Messages, newConversationMessages(messages, now)...)
+	conversation.UpdatedAt = now
+
+	if err := r.store.Save(conversation); err != nil {
+		return models.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+func (r *ConversationRepository) DeleteConversation(userID string, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, err := r.GetConversation(userID, id); err != nil {
+		return err
+	}
+
+	return r.store.Delete(id)
+}
+
+// newConversationMessages assigns ids and timestamps to messages supplied by a client.
+func newConversationMessages(messages []models.ConversationMessage, now time.Time) []models.ConversationMessage {
+	result := make([]models.ConversationMessage, 0, len(messages))
+	for _, message := range messages {
+		message.ID = uuid.NewString()
+		message.CreatedAt = now
+		result = append(result, message)
+	}
+	return result
+}
+
+// NewMemoryConversationStore keeps conversations in memory only, they are lost on restart.
+func NewMemoryConversationStore() ConversationStore {
+	return newConversationMemoryStore()
+}
+
+// NewFileConversationStore keeps conversations in memory and writes them to the JSON file at
+// path after every change, the conversations already stored there are loaded.
+func NewFileConversationStore(path string) (ConversationStore, error) {
+	return newJSONFileStore(path, "conversation", newConversationMemoryStore())
+}
+
+func newConversationMemoryStore() *memoryStore[models.Conversation] {
+	return newMemoryStore(
+		func(conversation models.Conversation) string { return conversation.ID },
+		func(conversation models.Conversation) time.Time { return conversation.CreatedAt },
+		ErrConversationNotFound,
+	)
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversationRepositoryScopesToUser(t *testing.T) {
+	repo := NewConversationRepository(NewMemoryConversationStore())
+
+	created, err := repo.CreateConversation("alice", models.Conversation{
+		Title:    "debugging",
+		Messages: []models.ConversationMessage{{Role: "user", Content: "hi"}},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.NotEmpty(t, created.Messages[0].ID)
+
+	_, err = repo.GetConversation("bob", created.ID)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	err = repo.DeleteConversation("bob", created.ID)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	bobList, err := repo.ListConversations("bob")
+	assert.NoError(t, err)
+	assert.Empty(t, bobList.Items)
+
+	aliceList, err := repo.ListConversations("alice")
+	assert.NoError(t, err)
+	assert.Len(t, aliceList.Items, 1)
+	assert.Equal(t, 1, aliceList.Items[0].MessageCount)
+}
+
+func TestFileConversationStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "conversations.json")
+
+	store, err := NewFileConversationStore(path)
+	assert.NoError(t, err)
+
+	repo := NewConversationRepository(store)
+	created, err := repo.CreateConversation("alice", models.Conversation{Title: "persisted"})
+	assert.NoError(t, err)
+
+	_, err = repo.AppendMessages("alice", created.ID, []models.ConversationMessage{
+		{Role: "user", Content: "question"},
+		{Role: "assistant", Content: "answer", StopReason: "end_of_turn"},
+	})
+	assert.NoError(t, err)
+
+	reopened, err := NewFileConversationStore(path)
+	assert.NoError(t, err)
+
+	conversation, err := NewConversationRepository(reopened).GetConversation("alice", created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "persisted", conversation.Title)
+	assert.Len(t, conversation.Messages, 2)
+	assert.Equal(t, "answer", conversation.Messages[1].Content)
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrGenerationNotFound is returned when a generation is not running or belongs to another user,
+// the two cases are not told apart so generation ids of other users cannot be probed.
+var ErrGenerationNotFound = errors.New("generation not found")
+
+// ErrGenerationCancelled is the cancellation cause of generations stopped through Cancel, it
+// tells them apart from generations whose client went away.
+var ErrGenerationCancelled = errors.New("generation cancelled")
+
+type activeGeneration struct {
+	userID string
+	cancel context.CancelCauseFunc
+}
+
+// GenerationRegistry keeps track of the inference streams the BFF is relaying so their owner can
+// stop them. Generations only live as long as the request streaming them, they are not persisted.
+type GenerationRegistry struct {
+	mutex       sync.Mutex
+	generations map[string]activeGeneration
+}
+
+func NewGenerationRegistry() *GenerationRegistry {
+	return &GenerationRegistry{
+		generations: map[string]activeGeneration{},
+	}
+}
+
+// Start registers a generation of userID under id and returns the context it has to run with,
+// the returned done func unregisters it and releases the context once the generation is over.
+func (r *GenerationRegistry) Start(ctx context.Context, id string, userID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	r.mutex.Lock()
+	r.generations[id] = activeGeneration{
+		userID: userID,
+		cancel: cancel,
+	}
+	r.mutex.Unlock()
+
+	done := func() {
+		r.mutex.Lock()
+		delete(r.generations, id)
+		r.mutex.Unlock()
+		cancel(nil)
+	}
+	return ctx, done
+}
+
+// Cancel stops the generation id of userID, its context is cancelled with ErrGenerationCancelled.
+func (r *GenerationRegistry) Cancel(userID string, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	generation, ok := r.generations[id]
+	if !ok || generation.userID != userID {
+		return ErrGenerationNotFound
+	}
+
+	generation.cancel(ErrGenerationCancelled)
+	delete(r.generations, id)
+	return nil
+}
+
+// IsGenerationCancelled reports whether ctx, or the generation context it derives from, was
+// stopped through Cancel.
+func IsGenerationCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrGenerationCancelled)
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const (
+	chatCompletionPath = "/v1/inference/chat-completion"
+	embeddingsPath     = "/v1/inference/embeddings"
+)
+
+// ChatCompletionStream yields the chunks of a streamed chat completion.
+type ChatCompletionStream = EventStream[llamastack.ChatCompletionResponseStreamChunk]
+
+// InferenceInterface defines the interface for inference operations
+type InferenceInterface interface {
+	StreamChatCompletion(ctx context.Context, client integrations.HTTPClientInterface, request llamastack.ChatCompletionRequest) (ChatCompletionStream, error)
+	Embeddings(client integrations.HTTPClientInterface, request llamastack.EmbeddingsRequest) (*llamastack.EmbeddingsResponse, error)
+}
+
+type UIInference struct {
+}
+
+func (i UIInference) StreamChatCompletion(ctx context.Context, client integrations.HTTPClientInterface, request llamastack.ChatCompletionRequest) (ChatCompletionStream, error) {
+	request.Stream = true
+
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	body, err := client.POSTStream(ctx, chatCompletionPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start chat completion: %w", err)
+	}
+
+	return newSSEEventStream[llamastack.ChatCompletionResponseStreamChunk](body), nil
+}
+
+func (i UIInference) Embeddings(client integrations.HTTPClientInterface, request llamastack.EmbeddingsRequest) (*llamastack.EmbeddingsResponse, error) {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(embeddingsPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute embeddings: %w", err)
+	}
+
+	var result llamastack.EmbeddingsResponse
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &result, nil
+}
@@ -4,12 +4,24 @@ type LlamaStackClientInterface interface {
 	ModelsInterface
 	VectorDBInterface
 	RAGToolInterface
+	InferenceInterface
+	AgentsInterface
+	ToolGroupsInterface
+	SafetyInterface
+	ProvidersInterface
+	VectorIOInterface
 }
 
 type LlamaStackClient struct {
 	UIModels
 	UIVectorDB
 	UIRAGTool
+	UIInference
+	UIAgents
+	UIToolGroups
+	UISafety
+	UIProviders
+	UIVectorIO
 }
 
 func NewLlamaStackClient() (LlamaStackClientInterface, error) {
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLlamaStackServer serves the responses keyed by request path, other paths are not found.
+func newTestLlamaStackServer(t *testing.T, status int, responses map[string]string) integrations.HTTPClientInterface {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"detail":"Not Found"}`))
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := integrations.NewHTTPClient(slog.Default(), server.URL)
+	assert.NoError(t, err)
+	return client
+}
+
+func TestGetAllModels(t *testing.T) {
+	client := newTestLlamaStackServer(t, http.StatusOK, map[string]string{
+		modelsPath: `{"data":[{"identifier":"llama3.2:3b","model_type":"llm","provider_id":"ollama"}]}`,
+	})
+
+	models, err := UIModels{}.GetAllModels(client)
+	assert.NoError(t, err)
+	assert.Len(t, models.Data, 1)
+	assert.Equal(t, "llama3.2:3b", models.Data[0].Identifier)
+}
+
+func TestGetAllVectorDBs(t *testing.T) {
+	client := newTestLlamaStackServer(t, http.StatusOK, map[string]string{
+		vectorDBsPath: `{"data":[{"identifier":"docs","embedding_model":"all-MiniLM-L6-v2","embedding_dimension":384}]}`,
+	})
+
+	vectorDBs, err := UIVectorDB{}.GetAllVectorDBs(client)
+	assert.NoError(t, err)
+	assert.Len(t, vectorDBs.Data, 1)
+	assert.EqualValues(t, 384, vectorDBs.Data[0].EmbeddingDimension)
+}
+
+// Error responses used to be decoded as lists, they are reported as HTTP errors instead.
+func TestGetAllUpstreamError(t *testing.T) {
+	client := newTestLlamaStackServer(t, http.StatusInternalServerError, map[string]string{
+		modelsPath:    `{"detail":"Internal server error"}`,
+		vectorDBsPath: `{"detail":"Internal server error"}`,
+	})
+
+	var httpError *integrations.HTTPError
+
+	_, err := UIModels{}.GetAllModels(client)
+	assert.True(t, errors.As(err, &httpError))
+	assert.Equal(t, http.StatusInternalServerError, httpError.StatusCode)
+
+	_, err = UIVectorDB{}.GetAllVectorDBs(client)
+	assert.True(t, errors.As(err, &httpError))
+	assert.Equal(t, "Internal server error", httpError.Message)
+}
@@ -1,8 +1,13 @@
 package repositories
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
@@ -13,6 +18,9 @@ const modelsPath = "/v1/models"
 // Used on the FE side to interact with the models API.
 type ModelsInterface interface {
 	GetAllModels(client integrations.HTTPClientInterface) (*llamastack.ModelList, error)
+	GetModel(client integrations.HTTPClientInterface, modelID string) (*llamastack.Model, error)
+	RegisterModel(client integrations.HTTPClientInterface, request llamastack.ModelRegistrationRequest) (*llamastack.Model, error)
+	UnregisterModel(client integrations.HTTPClientInterface, modelID string) error
 }
 
 type UIModels struct {
@@ -32,3 +40,66 @@ func (m UIModels) GetAllModels(client integrations.HTTPClientInterface) (*llamas
 
 	return &models, nil
 }
+
+func (m UIModels) GetModel(client integrations.HTTPClientInterface, modelID string) (*llamastack.Model, error) {
+	response, err := client.GET(modelPath(modelID))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve model: %w", err)
+	}
+
+	var model *llamastack.Model
+	if err := json.Unmarshal(response, &model); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	// Some Llama Stack versions answer null rather than 404 for unknown models.
+	if model == nil {
+		return nil, &integrations.HTTPError{
+			StatusCode: http.StatusNotFound,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusNotFound),
+				Message: fmt.Sprintf("model %s not found", modelID),
+			},
+		}
+	}
+
+	return model, nil
+}
+
+func (m UIModels) RegisterModel(client integrations.HTTPClientInterface, request llamastack.ModelRegistrationRequest) (*llamastack.Model, error) {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(modelsPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to register model: %w", err)
+	}
+
+	var model llamastack.Model
+	if err := json.Unmarshal(response, &model); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &model, nil
+}
+
+func (m UIModels) UnregisterModel(client integrations.HTTPClientInterface, modelID string) error {
+	_, err := client.DELETE(modelPath(modelID))
+	if err != nil {
+		return fmt.Errorf("failed to unregister model: %w", err)
+	}
+
+	return nil
+}
+
+// modelPath keeps the slashes of modelID, Llama Stack matches the rest of the path as the id.
+func modelPath(modelID string) string {
+	segments := strings.Split(modelID, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return modelsPath + "/" + strings.Join(segments, "/")
+}
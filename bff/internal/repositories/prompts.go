@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+var (
+	ErrPromptTemplateNotFound        = errors.New("prompt template not found")
+	ErrPromptTemplateVersionNotFound = errors.New("prompt template version not found")
+	ErrPromptTemplateForbidden       = errors.New("only the creator of a prompt template can change it")
+)
+
+// promptVariablePattern matches {{name}} placeholders, spaces inside the braces are allowed.
+var promptVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// PromptTemplateStore persists prompt templates together with their version history.
+type PromptTemplateStore interface {
+	Get(id string) (models.PromptTemplate, error)
+	GetAll() ([]models.PromptTemplate, error)
+	Save(template models.PromptTemplate) error
+	Delete(id string) error
+}
+
+// PromptTemplateRepository manages the prompt template library. Every user can read and render
+// every template, only their creator can change or delete them.
+type PromptTemplateRepository struct {
+	store PromptTemplateStore
+	// Serializes read-modify-write cycles against the store.
+	mutex sync.Mutex
+}
+
+func NewPromptTemplateRepository(store PromptTemplateStore) *PromptTemplateRepository {
+	return &PromptTemplateRepository{store: store}
+}
+
+func (r *PromptTemplateRepository) ListPromptTemplates() (models.PromptTemplateList, error) {
+	templates, err := r.store.GetAll()
+	if err != nil {
+		return models.PromptTemplateList{}, err
+	}
+
+	items := []models.PromptTemplateSummary{}
+	for _, template := range templates {
+		items = append(items, models.PromptTemplateSummary{
+			ID:          template.ID,
+			Name:        template.Name,
+			Description: template.Description,
+			Variables:   template.Variables,
+			Version:     template.Version,
+			UpdatedBy:   template.UpdatedBy,
+			UpdatedAt:   template.UpdatedAt,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+
+	return models.PromptTemplateList{Items: items}, nil
+}
+
+func (r *PromptTemplateRepository) GetPromptTemplate(id string) (models.PromptTemplate, error) {
+	return r.store.Get(id)
+}
+
+// GetPromptTemplateVersion returns a single version of a template, version 0 selects the
+// latest one.
+func (r *PromptTemplateRepository) GetPromptTemplateVersion(id string, version int) (models.PromptTemplateVersion, error) {
+	template, err := r.store.Get(id)
+	if err != nil {
+		return models.PromptTemplateVersion{}, err
+	}
+
+	return findPromptTemplateVersion(template, version)
+}
+
+func (r *PromptTemplateRepository) CreatePromptTemplate(userID string, name string, description string, content string) (models.PromptTemplate, error) {
+	now := time.Now().UTC()
+
+	template := models.PromptTemplate{
+		ID:          uuid.NewString(),
+		Name:        name,
+		Description: description,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+	}
+	addPromptTemplateVersion(&template, userID, content, now)
+
+	if err := r.store.Save(template); err != nil {
+		return models.PromptTemplate{}, err
+	}
+
+	return template, nil
+}
+
+func (r *PromptTemplateRepository) UpdatePromptTemplate(userID string, id string, update models.PromptTemplateUpdate) (models.PromptTemplate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	template, err := r.store.Get(id)
+	if err != nil {
+		return models.PromptTemplate{}, err
+	}
+	if template.CreatedBy != userID {
+		return models.PromptTemplate{}, ErrPromptTemplateForbidden
+	}
+
+	now := time.Now().UTC()
+
+	if update.Name != nil {
+		template.Name = *update.Name
+	}
+	if update.Description != nil {
+		template.Description = *update.Description
+	}
+	// Saving the same content again does not create an empty revision.
+	if update.Content != nil && *update.Content != template.Content {
+		addPromptTemplateVersion(&template, userID, *update.Content, now)
+	}
+	template.UpdatedBy = userID
+	template.UpdatedAt = now
+
+	if err := r.store.Save(template); err != nil {
+		return models.PromptTemplate{}, err
+	}
+
+	return template, nil
+}
+
+func (r *PromptTemplateRepository) DeletePromptTemplate(userID string, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	template, err := r.store.Get(id)
+	if err != nil {
+		return err
+	}
+	if template.CreatedBy != userID {
+		return ErrPromptTemplateForbidden
+	}
+
+	return r.store.Delete(id)
+}
+
+func addPromptTemplateVersion(template *models.PromptTemplate, userID string, content string, now time.Time) {
+	version := models.PromptTemplateVersion{
+		Version:   template.Version + 1,
+		Content:   content,
+		Variables: ExtractPromptVariables(content),
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+
+	template.Versions = append(template.Versions, version)
+	template.Content = version.Content
+	template.Variables = version.Variables
+	template.Version = version.Version
+	template.UpdatedBy = userID
+	template.UpdatedAt = now
+}
+
+func findPromptTemplateVersion(template models.PromptTemplate, version int) (models.PromptTemplateVersion, error) {
+	if version == 0 {
+		version = template.Version
+	}
+
+	for _, v := range template.Versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+
+	return models.PromptTemplateVersion{}, ErrPromptTemplateVersionNotFound
+}
+
+// ExtractPromptVariables returns the distinct placeholder names of content in order of first
+// appearance.
+func ExtractPromptVariables(content string) []string {
+	variables := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range promptVariablePattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+
+	return variables
+}
+
+// RenderPrompt substitutes the {{name}} placeholders of content with variables. Placeholders
+// without a value are left in place and returned as missing, values that are not referenced
+// are ignored.
+func RenderPrompt(content string, variables map[string]string) (string, []string) {
+	var missing []string
+	seen := map[string]bool{}
+
+	rendered := promptVariablePattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := promptVariablePattern.FindStringSubmatch(placeholder)[1]
+		value, ok := variables[name]
+		if !ok {
+			if !seen[name] {
+				seen[name] = true
+				missing = append(missing, name)
+			}
+			return placeholder
+		}
+		return value
+	})
+
+	return rendered, missing
+}
+
+// NewMemoryPromptTemplateStore keeps prompt templates in memory only, they are lost on restart.
+func NewMemoryPromptTemplateStore() PromptTemplateStore {
+	return newPromptTemplateMemoryStore()
+}
+
+// NewFilePromptTemplateStore keeps prompt templates in memory and writes them, history included,
+// to the JSON file at path after every change, the templates already stored there are loaded.
+func NewFilePromptTemplateStore(path string) (PromptTemplateStore, error) {
+	return newJSONFileStore(path, "prompt template", newPromptTemplateMemoryStore())
+}
+
+func newPromptTemplateMemoryStore() *memoryStore[models.PromptTemplate] {
+	return newMemoryStore(
+		func(template models.PromptTemplate) string { return template.ID },
+		func(template models.PromptTemplate) time.Time { return template.CreatedAt },
+		ErrPromptTemplateNotFound,
+	)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptTemplateRepositoryKeepsHistory(t *testing.T) {
+	repo := NewPromptTemplateRepository(NewMemoryPromptTemplateStore())
+
+	created, err := repo.CreatePromptTemplate("alice", "support", "", "You help {{ product }} users.")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.Version)
+	assert.Equal(t, []string{"product"}, created.Variables)
+
+	content := "You help {{product}} users in {{language}}."
+	updated, err := repo.UpdatePromptTemplate("alice", created.ID, models.PromptTemplateUpdate{Content: &content})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, []string{"product", "language"}, updated.Variables)
+	assert.Equal(t, "alice", updated.UpdatedBy)
+	assert.Len(t, updated.Versions, 2)
+
+	first, err := repo.GetPromptTemplateVersion(created.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "You help {{ product }} users.", first.Content)
+
+	_, err = repo.GetPromptTemplateVersion(created.ID, 3)
+	assert.ErrorIs(t, err, ErrPromptTemplateVersionNotFound)
+
+	assert.NoError(t, repo.DeletePromptTemplate("alice", created.ID))
+	_, err = repo.GetPromptTemplate(created.ID)
+	assert.ErrorIs(t, err, ErrPromptTemplateNotFound)
+}
+
+func TestRenderPrompt(t *testing.T) {
+	rendered, missing := RenderPrompt("Hi {{name}}, welcome to {{ team }}. Bye {{name}}.", map[string]string{
+		"name":   "Ada",
+		"unused": "ignored",
+	})
+
+	assert.Equal(t, "Hi Ada, welcome to {{ team }}. Bye Ada.", rendered)
+	assert.Equal(t, []string{"team"}, missing)
+}
+
+func TestPromptTemplateRepositoryOnlyCreatorWrites(t *testing.T) {
+	repo := NewPromptTemplateRepository(NewMemoryPromptTemplateStore())
+
+	created, err := repo.CreatePromptTemplate("alice", "support", "", "You help users.")
+	assert.NoError(t, err)
+
+	name := "hijacked"
+	_, err = repo.UpdatePromptTemplate("bob", created.ID, models.PromptTemplateUpdate{Name: &name})
+	assert.ErrorIs(t, err, ErrPromptTemplateForbidden)
+	assert.ErrorIs(t, repo.DeletePromptTemplate("bob", created.ID), ErrPromptTemplateForbidden)
+
+	template, err := repo.GetPromptTemplate(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "support", template.Name)
+	assert.Equal(t, 1, template.Version)
+}
+
+func TestFilePromptTemplateStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompts.json")
+
+	store, err := NewFilePromptTemplateStore(path)
+	assert.NoError(t, err)
+
+	created, err := NewPromptTemplateRepository(store).CreatePromptTemplate("alice", "support", "", "You help {{product}} users.")
+	assert.NoError(t, err)
+
+	reopened, err := NewFilePromptTemplateStore(path)
+	assert.NoError(t, err)
+
+	template, err := NewPromptTemplateRepository(reopened).GetPromptTemplate(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"product"}, template.Variables)
+
+	assert.NoError(t, NewPromptTemplateRepository(reopened).DeletePromptTemplate("alice", created.ID))
+
+	reopened, err = NewFilePromptTemplateStore(path)
+	assert.NoError(t, err)
+
+	_, err = reopened.Get(created.ID)
+	assert.ErrorIs(t, err, ErrPromptTemplateNotFound)
+}
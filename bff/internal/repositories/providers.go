@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const providersPath = "/v1/providers"
+
+// ProvidersInterface lists the providers of the distribution.
+type ProvidersInterface interface {
+	GetAllProviders(client integrations.HTTPClientInterface) (*llamastack.ProviderList, error)
+}
+
+type UIProviders struct {
+}
+
+func (p UIProviders) GetAllProviders(client integrations.HTTPClientInterface) (*llamastack.ProviderList, error) {
+	response, err := client.GET(providersPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve providers: %w", err)
+	}
+
+	var providers llamastack.ProviderList
+	if err := json.Unmarshal(response, &providers); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &providers, nil
+}
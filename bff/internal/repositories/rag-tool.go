@@ -9,11 +9,15 @@ import (
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
 )
 
-const insertRagToolPath = "/v1/tool-runtime/rag-tool/insert"
+const (
+	insertRagToolPath = "/v1/tool-runtime/rag-tool/insert"
+	queryRagToolPath  = "/v1/tool-runtime/rag-tool/query"
+)
 
 // RAGToolInterface defines the interface for RAG tool operations
 type RAGToolInterface interface {
 	InsertDocuments(client integrations.HTTPClientInterface, request llamastack.DocumentInsertRequest) error
+	QueryDocuments(client integrations.HTTPClientInterface, request llamastack.RAGQueryRequest) (*llamastack.RAGQueryResult, error)
 }
 
 type UIRAGTool struct {
@@ -34,3 +38,22 @@ func (r UIRAGTool) InsertDocuments(client integrations.HTTPClientInterface, requ
 
 	return nil
 }
+
+func (r UIRAGTool) QueryDocuments(client integrations.HTTPClientInterface, request llamastack.RAGQueryRequest) (*llamastack.RAGQueryResult, error) {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(queryRagToolPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+
+	var result llamastack.RAGQueryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &result, nil
+}
@@ -1,14 +1,28 @@
 package repositories
 
 // Repositories struct is a single convenient container to hold and represent all our repositories.
+// Repositories backed by BFF side storage start out in memory, NewApp swaps in persistent stores
+// when they are configured.
 type Repositories struct {
 	HealthCheck      *HealthCheckRepository
+	Conversations    *ConversationRepository
+	PromptTemplates  *PromptTemplateRepository
+	Usage            *UsageRepository
+	ShareLinks       *ShareLinkRepository
+	VectorDBOwners   *VectorDBOwnerRepository
+	Generations      *GenerationRegistry
 	LlamaStackClient LlamaStackClientInterface
 }
 
 func NewRepositories(llamaStackClient LlamaStackClientInterface) *Repositories {
 	return &Repositories{
 		HealthCheck:      NewHealthCheckRepository(),
+		Conversations:    NewConversationRepository(NewMemoryConversationStore()),
+		PromptTemplates:  NewPromptTemplateRepository(NewMemoryPromptTemplateStore()),
+		Usage:            NewUsageRepository(NewMemoryUsageStore()),
+		ShareLinks:       NewShareLinkRepository(NewMemoryShareLinkStore(), nil, 0, 0),
+		VectorDBOwners:   NewVectorDBOwnerRepository(NewMemoryVectorDBOwnerStore()),
+		Generations:      NewGenerationRegistry(),
 		LlamaStackClient: llamaStackClient,
 	}
 }
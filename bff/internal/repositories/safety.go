@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const (
+	shieldsPath   = "/v1/shields"
+	runShieldPath = "/v1/safety/run-shield"
+)
+
+// SafetyInterface defines the interface for shield operations
+type SafetyInterface interface {
+	GetAllShields(client integrations.HTTPClientInterface) (*llamastack.ShieldList, error)
+	RunShield(client integrations.HTTPClientInterface, request llamastack.RunShieldRequest) (*llamastack.RunShieldResponse, error)
+}
+
+type UISafety struct {
+}
+
+func (s UISafety) GetAllShields(client integrations.HTTPClientInterface) (*llamastack.ShieldList, error) {
+	response, err := client.GET(shieldsPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shields: %w", err)
+	}
+
+	var shields llamastack.ShieldList
+	if err := json.Unmarshal(response, &shields); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &shields, nil
+}
+
+func (s UISafety) RunShield(client integrations.HTTPClientInterface, request llamastack.RunShieldRequest) (*llamastack.RunShieldResponse, error) {
+	// Llama Stack rejects a missing params object.
+	if request.Params == nil {
+		request.Params = map[string]any{}
+	}
+
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	response, err := client.POST(runShieldPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run shield %s: %w", request.ShieldID, err)
+	}
+
+	var result llamastack.RunShieldResponse
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &result, nil
+}
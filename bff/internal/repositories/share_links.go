@@ -0,0 +1,364 @@
+package repositories
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrShareTokenInvalid = errors.New("share token is invalid")
+	ErrShareLinkExpired  = errors.New("share link has expired")
+	ErrShareLinkRevoked  = errors.New("share link has been revoked")
+	// ErrShareLinkRateLimited is returned by AllowShareLinkChat and AllowShareLinkResolve once a
+	// link used up its chats or resolves for the current minute.
+	ErrShareLinkRateLimited = errors.New("share link rate limit reached, try again later")
+)
+
+// maxShareLinkAuditEntries bounds the audit kept per link, the oldest entries are dropped first.
+const maxShareLinkAuditEntries = 1000
+
+// shareLinkRateWindow is the length of the window the uses of a link are counted in.
+const shareLinkRateWindow = time.Minute
+
+// ShareLinkStore persists share links. Links are revoked rather than deleted so their audit
+// outlives them.
+type ShareLinkStore interface {
+	Get(id string) (models.ShareLink, error)
+	GetAll() ([]models.ShareLink, error)
+	Save(link models.ShareLink) error
+}
+
+// ShareLinkRepository mints and resolves share tokens. A token carries the link id and expiry
+// signed with HMAC-SHA256, the stored link is still consulted to honour revocation.
+type ShareLinkRepository struct {
+	store  ShareLinkStore
+	secret []byte
+	// Serializes read-modify-write cycles against the store.
+	mutex sync.Mutex
+	// Anyone holding a token can chat with and resolve a link, which are limited per link.
+	chats    *shareLinkLimiter
+	resolves *shareLinkLimiter
+	// Only the first rejected use of a link in a window is audited.
+	rejections *shareLinkLimiter
+}
+
+// shareLinkLimiter counts the uses of each link per window, the counts are kept in memory only.
+type shareLinkLimiter struct {
+	// perWindow bounds the uses of a link per window, zero disables the limit.
+	perWindow int
+	windows   map[string]shareLinkWindow
+	mutex     sync.Mutex
+}
+
+// shareLinkWindow counts the uses of a link in the window starting at start.
+type shareLinkWindow struct {
+	start time.Time
+	count int
+}
+
+// shareTokenClaims is the signed part of a share token.
+type shareTokenClaims struct {
+	ShareLinkID string `json:"sid"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// NewShareLinkRepository signs tokens with secret, a random secret is generated when it is empty.
+// Each link can start chatsPerMinute chats and be resolved resolvesPerMinute times a minute, a
+// limit is disabled when it is zero.
+func NewShareLinkRepository(store ShareLinkStore, secret []byte, chatsPerMinute int, resolvesPerMinute int) *ShareLinkRepository {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		// The system random source failing leaves nothing sensible to fall back to.
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Errorf("failed to generate share link secret: %w", err))
+		}
+	}
+
+	return &ShareLinkRepository{
+		store:      store,
+		secret:     secret,
+		chats:      newShareLinkLimiter(chatsPerMinute),
+		resolves:   newShareLinkLimiter(resolvesPerMinute),
+		rejections: newShareLinkLimiter(1),
+	}
+}
+
+func (r *ShareLinkRepository) CreateShareLink(userID string, config models.ChatbotConfig, ttl time.Duration) (models.ShareLink, error) {
+	now := time.Now().UTC()
+
+	link := models.ShareLink{
+		ID:        uuid.NewString(),
+		CreatedBy: userID,
+		Config:    config,
+		CreatedAt: now,
+		// Tokens carry the expiry in seconds, keep the stored value identical.
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+	link.Audit = []models.ShareLinkAuditEntry{{Event: models.ShareLinkCreatedEvent, Timestamp: now, UserID: userID}}
+
+	if err := r.store.Save(link); err != nil {
+		return models.ShareLink{}, err
+	}
+
+	return r.withToken(link)
+}
+
+// ListShareLinks returns the links created by userID, newest first.
+func (r *ShareLinkRepository) ListShareLinks(userID string) (models.ShareLinkList, error) {
+	links, err := r.store.GetAll()
+	if err != nil {
+		return models.ShareLinkList{}, err
+	}
+
+	items := []models.ShareLink{}
+	for _, link := range links {
+		if link.CreatedBy != userID {
+			continue
+		}
+		link, err := r.withToken(link)
+		if err != nil {
+			return models.ShareLinkList{}, err
+		}
+		// The audit is served on its own.
+		link.Audit = nil
+		items = append(items, link)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	return models.ShareLinkList{Items: items}, nil
+}
+
+// GetShareLink returns a link with its audit, links of other users are reported as not found.
+func (r *ShareLinkRepository) GetShareLink(userID string, id string) (models.ShareLink, error) {
+	link, err := r.store.Get(id)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	if link.CreatedBy != userID {
+		return models.ShareLink{}, ErrShareLinkNotFound
+	}
+
+	return r.withToken(link)
+}
+
+func (r *ShareLinkRepository) RevokeShareLink(userID string, id string) (models.ShareLink, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	link, err := r.GetShareLink(userID, id)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now().UTC()
+		link.RevokedAt = &now
+		link.Audit = appendShareLinkAudit(link.Audit, models.ShareLinkAuditEntry{Event: models.ShareLinkRevokedEvent, Timestamp: now, UserID: userID})
+
+		stored := link
+		stored.Token = ""
+		if err := r.store.Save(stored); err != nil {
+			return models.ShareLink{}, err
+		}
+	}
+
+	return link, nil
+}
+
+// ResolveShareToken verifies token and returns the link it grants access to. The id of the link
+// is returned alongside ErrShareLinkExpired and ErrShareLinkRevoked so rejections can be audited.
+func (r *ShareLinkRepository) ResolveShareToken(token string) (models.ShareLink, string, error) {
+	claims, err := r.verify(token)
+	if err != nil {
+		return models.ShareLink{}, "", err
+	}
+
+	link, err := r.store.Get(claims.ShareLinkID)
+	if errors.Is(err, ErrShareLinkNotFound) {
+		return models.ShareLink{}, "", ErrShareTokenInvalid
+	}
+	if err != nil {
+		return models.ShareLink{}, "", err
+	}
+
+	if link.RevokedAt != nil {
+		return models.ShareLink{}, link.ID, ErrShareLinkRevoked
+	}
+	if time.Now().After(time.Unix(claims.ExpiresAt, 0)) {
+		return models.ShareLink{}, link.ID, ErrShareLinkExpired
+	}
+
+	link.Audit = nil
+	return link, link.ID, nil
+}
+
+// RecordShareLinkUse appends entry to the audit of the link id. Rejected uses are audited once
+// a minute per link, so a flood of them neither pushes the useful entries out nor rewrites the
+// store on every request.
+func (r *ShareLinkRepository) RecordShareLinkUse(id string, entry models.ShareLinkAuditEntry) error {
+	if entry.Event == models.ShareLinkRejectedEvent {
+		if _, ok := r.rejections.allow(id); !ok {
+			return nil
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	link, err := r.store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	link.Audit = appendShareLinkAudit(link.Audit, entry)
+
+	return r.store.Save(link)
+}
+
+// AllowShareLinkChat counts a chat against the limit of the link id. Once the limit is reached
+// it returns ErrShareLinkRateLimited together with the time left until the next window.
+func (r *ShareLinkRepository) AllowShareLinkChat(id string) (time.Duration, error) {
+	if retryAfter, ok := r.chats.allow(id); !ok {
+		return retryAfter, ErrShareLinkRateLimited
+	}
+	return 0, nil
+}
+
+// AllowShareLinkResolve counts a resolve of the link id like AllowShareLinkChat counts chats.
+func (r *ShareLinkRepository) AllowShareLinkResolve(id string) (time.Duration, error) {
+	if retryAfter, ok := r.resolves.allow(id); !ok {
+		return retryAfter, ErrShareLinkRateLimited
+	}
+	return 0, nil
+}
+
+func newShareLinkLimiter(perWindow int) *shareLinkLimiter {
+	return &shareLinkLimiter{perWindow: perWindow, windows: map[string]shareLinkWindow{}}
+}
+
+// allow counts a use of the link id, once the limit is reached it returns false together with
+// the time left until the next window.
+func (l *shareLinkLimiter) allow(id string) (time.Duration, bool) {
+	if l.perWindow <= 0 {
+		return 0, true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	window, ok := l.windows[id]
+	if !ok || now.Sub(window.start) >= shareLinkRateWindow {
+		// Drop the windows that ended so links that are no longer used are forgotten.
+		for linkID, other := range l.windows {
+			if now.Sub(other.start) >= shareLinkRateWindow {
+				delete(l.windows, linkID)
+			}
+		}
+		window = shareLinkWindow{start: now}
+	}
+
+	if window.count >= l.perWindow {
+		return window.start.Add(shareLinkRateWindow).Sub(now), false
+	}
+
+	window.count++
+	l.windows[id] = window
+	return 0, true
+}
+
+func (r *ShareLinkRepository) withToken(link models.ShareLink) (models.ShareLink, error) {
+	token, err := r.sign(shareTokenClaims{ShareLinkID: link.ID, ExpiresAt: link.ExpiresAt.Unix()})
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+	link.Token = token
+	return link, nil
+}
+
+func (r *ShareLinkRepository) sign(claims shareTokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode share token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(r.signature(encoded)), nil
+}
+
+func (r *ShareLinkRepository) verify(token string) (shareTokenClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return shareTokenClaims{}, ErrShareTokenInvalid
+	}
+
+	decodedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decodedSignature, r.signature(encoded)) {
+		return shareTokenClaims{}, ErrShareTokenInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return shareTokenClaims{}, ErrShareTokenInvalid
+	}
+
+	var claims shareTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ShareLinkID == "" {
+		return shareTokenClaims{}, ErrShareTokenInvalid
+	}
+
+	return claims, nil
+}
+
+func (r *ShareLinkRepository) signature(payload string) []byte {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func appendShareLinkAudit(audit []models.ShareLinkAuditEntry, entry models.ShareLinkAuditEntry) []models.ShareLinkAuditEntry {
+	audit = append(audit, entry)
+	if len(audit) > maxShareLinkAuditEntries {
+		audit = audit[len(audit)-maxShareLinkAuditEntries:]
+	}
+	return audit
+}
+
+// NewMemoryShareLinkStore keeps share links in memory only, they are lost on restart.
+func NewMemoryShareLinkStore() ShareLinkStore {
+	return newShareLinkMemoryStore()
+}
+
+// NewFileShareLinkStore keeps share links in memory and writes them, audit included, to the
+// JSON file at path after every change, the links already stored there are loaded.
+func NewFileShareLinkStore(path string) (ShareLinkStore, error) {
+	return newJSONFileStore(path, "share link", newShareLinkMemoryStore())
+}
+
+func newShareLinkMemoryStore() *memoryStore[models.ShareLink] {
+	return newMemoryStore(
+		func(link models.ShareLink) string { return link.ID },
+		func(link models.ShareLink) time.Time { return link.CreatedAt },
+		ErrShareLinkNotFound,
+	)
+}
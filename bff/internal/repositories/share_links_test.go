@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileShareLinkStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "share-links.json")
+	secret := []byte("share-link-secret")
+
+	store, err := NewFileShareLinkStore(path)
+	assert.NoError(t, err)
+
+	link, err := NewShareLinkRepository(store, secret, 0, 0).CreateShareLink("alice", models.ChatbotConfig{ModelID: "llama3.2:3b"}, time.Hour)
+	assert.NoError(t, err)
+
+	// A restarted BFF sharing the secret keeps honouring the token.
+	reopened, err := NewFileShareLinkStore(path)
+	assert.NoError(t, err)
+	repository := NewShareLinkRepository(reopened, secret, 0, 0)
+
+	resolved, _, err := repository.ResolveShareToken(link.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "llama3.2:3b", resolved.Config.ModelID)
+
+	_, err = repository.RevokeShareLink("alice", link.ID)
+	assert.NoError(t, err)
+
+	reopened, err = NewFileShareLinkStore(path)
+	assert.NoError(t, err)
+
+	_, linkID, err := NewShareLinkRepository(reopened, secret, 0, 0).ResolveShareToken(link.Token)
+	assert.ErrorIs(t, err, ErrShareLinkRevoked)
+	assert.Equal(t, link.ID, linkID)
+}
+
+func TestAllowShareLinkChat(t *testing.T) {
+	repository := NewShareLinkRepository(NewMemoryShareLinkStore(), nil, 2, 0)
+
+	for range 2 {
+		_, err := repository.AllowShareLinkChat("link-1")
+		assert.NoError(t, err)
+	}
+
+	retryAfter, err := repository.AllowShareLinkChat("link-1")
+	assert.ErrorIs(t, err, ErrShareLinkRateLimited)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, retryAfter, time.Minute)
+
+	// Every link has its own limit.
+	_, err = repository.AllowShareLinkChat("link-2")
+	assert.NoError(t, err)
+
+	// An elapsed window starts over.
+	repository.chats.windows["link-1"] = shareLinkWindow{start: time.Now().Add(-time.Minute), count: 2}
+	_, err = repository.AllowShareLinkChat("link-1")
+	assert.NoError(t, err)
+
+	unlimited := NewShareLinkRepository(NewMemoryShareLinkStore(), nil, 0, 0)
+	for range 100 {
+		_, err := unlimited.AllowShareLinkChat("link-1")
+		assert.NoError(t, err)
+	}
+}
+
+func TestRecordShareLinkUseAuditsRejectionsOncePerWindow(t *testing.T) {
+	repository := NewShareLinkRepository(NewMemoryShareLinkStore(), nil, 0, 0)
+
+	link, err := repository.CreateShareLink("alice", models.ChatbotConfig{ModelID: "llama3.2:3b"}, time.Hour)
+	assert.NoError(t, err)
+
+	for range 10 {
+		assert.NoError(t, repository.RecordShareLinkUse(link.ID, models.ShareLinkAuditEntry{Event: models.ShareLinkRejectedEvent}))
+		assert.NoError(t, repository.RecordShareLinkUse(link.ID, models.ShareLinkAuditEntry{Event: models.ShareLinkChatEvent}))
+	}
+
+	link, err = repository.GetShareLink("alice", link.ID)
+	assert.NoError(t, err)
+
+	counts := map[string]int{}
+	for _, entry := range link.Audit {
+		counts[entry.Event]++
+	}
+	assert.Equal(t, map[string]int{
+		models.ShareLinkCreatedEvent:  1,
+		models.ShareLinkRejectedEvent: 1,
+		models.ShareLinkChatEvent:     10,
+	}, counts)
+}
+
+func TestAllowShareLinkResolve(t *testing.T) {
+	repository := NewShareLinkRepository(NewMemoryShareLinkStore(), nil, 0, 1)
+
+	_, err := repository.AllowShareLinkResolve("link-1")
+	assert.NoError(t, err)
+
+	retryAfter, err := repository.AllowShareLinkResolve("link-1")
+	assert.ErrorIs(t, err, ErrShareLinkRateLimited)
+	assert.Greater(t, retryAfter, time.Duration(0))
+
+	// Chats are counted separately.
+	_, err = repository.AllowShareLinkChat("link-1")
+	assert.NoError(t, err)
+}
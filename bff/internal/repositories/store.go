@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps records by id in memory only, they are lost on restart. It backs the
+// conversation, prompt template and share link stores.
+type memoryStore[T any] struct {
+	records map[string]T
+	// id and createdAt read the fields of T the store relies on.
+	id        func(T) string
+	createdAt func(T) time.Time
+	// notFound is returned for unknown ids.
+	notFound error
+	mutex    sync.RWMutex
+}
+
+func newMemoryStore[T any](id func(T) string, createdAt func(T) time.Time, notFound error) *memoryStore[T] {
+	return &memoryStore[T]{
+		records:   map[string]T{},
+		id:        id,
+		createdAt: createdAt,
+		notFound:  notFound,
+	}
+}
+
+func (s *memoryStore[T]) Get(id string) (T, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		var zero T
+		return zero, s.notFound
+	}
+
+	return record, nil
+}
+
+// GetAll returns the records oldest first.
+func (s *memoryStore[T]) GetAll() ([]T, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	records := make([]T, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	// Map iteration order is random, keep the result stable.
+	sort.Slice(records, func(i, j int) bool {
+		return s.createdAt(records[i]).Before(s.createdAt(records[j]))
+	})
+
+	return records, nil
+}
+
+func (s *memoryStore[T]) Save(record T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.records[s.id(record)] = record
+
+	return nil
+}
+
+func (s *memoryStore[T]) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return s.notFound
+	}
+	delete(s.records, id)
+
+	return nil
+}
+
+// jsonFileStore keeps records in memory and writes all of them to a single JSON file after
+// every change, which is plenty for the amount of data a playground holds.
+type jsonFileStore[T any] struct {
+	path string
+	// name describes the records in errors, e.g. "conversation".
+	name   string
+	memory *memoryStore[T]
+	// Serializes writes so the file always reflects the latest state.
+	mutex sync.Mutex
+}
+
+// newJSONFileStore loads the records stored at path into memory, the file and its directory are
+// created on the first write if they do not exist yet.
+func newJSONFileStore[T any](path string, name string, memory *memoryStore[T]) (*jsonFileStore[T], error) {
+	store := &jsonFileStore[T]{
+		path:   path,
+		name:   name,
+		memory: memory,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s store: %w", name, err)
+	}
+
+	var records []T
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode %s store %s: %w", name, path, err)
+	}
+
+	for _, record := range records {
+		if err := store.memory.Save(record); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+func (s *jsonFileStore[T]) Get(id string) (T, error) {
+	return s.memory.Get(id)
+}
+
+func (s *jsonFileStore[T]) GetAll() ([]T, error) {
+	return s.memory.GetAll()
+}
+
+func (s *jsonFileStore[T]) Save(record T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.memory.Save(record); err != nil {
+		return err
+	}
+
+	return s.persist()
+}
+
+func (s *jsonFileStore[T]) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.memory.Delete(id); err != nil {
+		return err
+	}
+
+	return s.persist()
+}
+
+// persist writes to a temporary file first and renames it, so a crash never leaves a
+// truncated store behind.
+func (s *jsonFileStore[T]) persist() error {
+	records, err := s.memory.GetAll()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(records, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s store: %w", s.name, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create %s store directory: %w", s.name, err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s store: %w", s.name, err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace %s store: %w", s.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+// EventStream yields the events of a streamed Llama Stack response, Recv returns io.EOF once
+// the stream is finished.
+type EventStream[T any] interface {
+	Recv() (*T, error)
+	Close() error
+}
+
+type sseEventStream[T any] struct {
+	reader *integrations.SSEReader
+}
+
+func newSSEEventStream[T any](body io.ReadCloser) *sseEventStream[T] {
+	return &sseEventStream[T]{reader: integrations.NewSSEReader(body)}
+}
+
+func (s *sseEventStream[T]) Recv() (*T, error) {
+	data, err := s.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	// Llama Stack reports failures that happen mid-stream as an event holding only an error.
+	var failure struct {
+		Error *llamastack.StreamError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &failure); err == nil && failure.Error != nil {
+		return nil, fmt.Errorf("stream failed upstream: %s", failure.Error.Message)
+	}
+
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error decoding stream event: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (s *sseEventStream[T]) Close() error {
+	return s.reader.Close()
+}
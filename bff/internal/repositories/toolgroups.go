@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const (
+	toolGroupsPath = "/v1/toolgroups"
+	toolsPath      = "/v1/tools"
+)
+
+// Used on the FE side to discover the tools offered by the distribution.
+type ToolGroupsInterface interface {
+	GetAllToolGroups(client integrations.HTTPClientInterface) (*llamastack.ToolGroupList, error)
+	// GetAllTools lists the tools of a single tool group, or of all of them when toolGroupID is empty.
+	GetAllTools(client integrations.HTTPClientInterface, toolGroupID string) (*llamastack.ToolList, error)
+	RegisterToolGroup(client integrations.HTTPClientInterface, request llamastack.ToolGroupRegistrationRequest) error
+}
+
+type UIToolGroups struct {
+}
+
+func (t UIToolGroups) GetAllToolGroups(client integrations.HTTPClientInterface) (*llamastack.ToolGroupList, error) {
+	response, err := client.GET(toolGroupsPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tool groups: %w", err)
+	}
+
+	var toolGroups llamastack.ToolGroupList
+	if err := json.Unmarshal(response, &toolGroups); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &toolGroups, nil
+}
+
+func (t UIToolGroups) GetAllTools(client integrations.HTTPClientInterface, toolGroupID string) (*llamastack.ToolList, error) {
+	path := toolsPath
+	if toolGroupID != "" {
+		path += "?" + url.Values{"toolgroup_id": []string{toolGroupID}}.Encode()
+	}
+
+	response, err := client.GET(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tools: %w", err)
+	}
+
+	var tools llamastack.ToolList
+	if err := json.Unmarshal(response, &tools); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	return &tools, nil
+}
+
+func (t UIToolGroups) RegisterToolGroup(client integrations.HTTPClientInterface, request llamastack.ToolGroupRegistrationRequest) error {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	_, err = client.POST(toolGroupsPath, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to register tool group: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllToolsFilter(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, toolsPath, r.URL.Path)
+		queries = append(queries, r.URL.Query().Get("toolgroup_id"))
+		_, _ = w.Write([]byte(`{"data":[{"identifier":"knowledge_search","toolgroup_id":"builtin::rag"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := integrations.NewHTTPClient(slog.Default(), server.URL)
+	assert.NoError(t, err)
+
+	tools, err := UIToolGroups{}.GetAllTools(client, "builtin::rag")
+	assert.NoError(t, err)
+	assert.Equal(t, "knowledge_search", tools.Data[0].Identifier)
+
+	_, err = UIToolGroups{}.GetAllTools(client, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"builtin::rag", ""}, queries)
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+const (
+	UsageGroupByUser  = "user"
+	UsageGroupByModel = "model"
+)
+
+// defaultMaxUsageRecords bounds the memory held by MemoryUsageStore, the oldest records are
+// dropped first.
+const defaultMaxUsageRecords = 100_000
+
+// UsageStore persists usage records. List returns the records in [from, to), a zero time
+// leaves that end open.
+type UsageStore interface {
+	Record(record models.UsageRecord) error
+	List(from time.Time, to time.Time) ([]models.UsageRecord, error)
+}
+
+// UsageRepository records the token usage of inferences going through the BFF and aggregates
+// it for reporting.
+type UsageRepository struct {
+	store UsageStore
+}
+
+func NewUsageRepository(store UsageStore) *UsageRepository {
+	return &UsageRepository{store: store}
+}
+
+func (r *UsageRepository) RecordUsage(record models.UsageRecord) error {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now().UTC()
+	}
+	if record.TotalTokens == 0 {
+		record.TotalTokens = record.PromptTokens + record.CompletionTokens
+	}
+
+	return r.store.Record(record)
+}
+
+// GetUsageReport aggregates the usage recorded in [from, to) per user or per model. Only the
+// usage of userID is included unless it is empty.
+func (r *UsageRepository) GetUsageReport(from time.Time, to time.Time, groupBy string, userID string) (models.UsageReport, error) {
+	if groupBy != UsageGroupByUser && groupBy != UsageGroupByModel {
+		return models.UsageReport{}, fmt.Errorf("unsupported usage grouping %q", groupBy)
+	}
+
+	records, err := r.store.List(from, to)
+	if err != nil {
+		return models.UsageReport{}, err
+	}
+
+	summaries := map[string]*models.UsageSummary{}
+	latencies := map[string]int64{}
+	for _, record := range records {
+		if userID != "" && record.UserID != userID {
+			continue
+		}
+
+		key := record.UserID
+		if groupBy == UsageGroupByModel {
+			key = record.ModelID
+		}
+
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &models.UsageSummary{}
+			if groupBy == UsageGroupByModel {
+				summary.ModelID = key
+			} else {
+				summary.UserID = key
+			}
+			summaries[key] = summary
+		}
+
+		summary.Requests++
+		summary.PromptTokens += record.PromptTokens
+		summary.CompletionTokens += record.CompletionTokens
+		summary.TotalTokens += record.TotalTokens
+		latencies[key] += record.LatencyMs
+	}
+
+	items := []models.UsageSummary{}
+	for key, summary := range summaries {
+		summary.AverageLatencyMs = float64(latencies[key]) / float64(summary.Requests)
+		items = append(items, *summary)
+	}
+
+	// Heaviest consumers first.
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TotalTokens != items[j].TotalTokens {
+			return items[i].TotalTokens > items[j].TotalTokens
+		}
+		return items[i].UserID+items[i].ModelID < items[j].UserID+items[j].ModelID
+	})
+
+	report := models.UsageReport{
+		GroupBy: groupBy,
+		UserID:  userID,
+		Items:   items,
+	}
+	if !from.IsZero() {
+		report.From = &from
+	}
+	if !to.IsZero() {
+		report.To = &to
+	}
+
+	return report, nil
+}
+
+// MemoryUsageStore keeps the most recent usage records in memory only, they are lost on restart.
+type MemoryUsageStore struct {
+	records    []models.UsageRecord
+	maxRecords int
+	mutex      sync.RWMutex
+}
+
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{maxRecords: defaultMaxUsageRecords}
+}
+
+func (s *MemoryUsageStore) Record(record models.UsageRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.records = append(s.records, record)
+	if len(s.records) > s.maxRecords {
+		s.records = s.records[len(s.records)-s.maxRecords:]
+	}
+
+	return nil
+}
+
+func (s *MemoryUsageStore) List(from time.Time, to time.Time) ([]models.UsageRecord, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	records := []models.UsageRecord{}
+	for _, record := range s.records {
+		if !from.IsZero() && record.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !record.Timestamp.Before(to) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
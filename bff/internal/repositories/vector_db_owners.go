@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/models"
+)
+
+var (
+	ErrVectorDBOwnerNotFound = errors.New("vector database owner not found")
+	ErrVectorDBOwnerExists   = errors.New("vector database already has another owner")
+)
+
+// VectorDBOwnerStore persists the owners of vector databases by vector database id.
+type VectorDBOwnerStore interface {
+	Get(id string) (models.VectorDBOwner, error)
+	GetAll() ([]models.VectorDBOwner, error)
+	Save(owner models.VectorDBOwner) error
+	Delete(id string) error
+}
+
+// VectorDBOwnerRepository tracks which user registered each vector database. Vector databases
+// registered without authentication, or outside of the BFF, have no owner.
+type VectorDBOwnerRepository struct {
+	store VectorDBOwnerStore
+	// Serializes SetOwner so two users cannot both claim a vector database.
+	mutex sync.Mutex
+}
+
+func NewVectorDBOwnerRepository(store VectorDBOwnerStore) *VectorDBOwnerRepository {
+	return &VectorDBOwnerRepository{store: store}
+}
+
+// SetOwner records userID as the owner of vectorDBID. It never replaces another owner, which
+// gets ErrVectorDBOwnerExists, the owner has to be deleted first.
+func (r *VectorDBOwnerRepository) SetOwner(vectorDBID string, userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	owner, err := r.store.Get(vectorDBID)
+	if err == nil {
+		if owner.UserID != userID {
+			return ErrVectorDBOwnerExists
+		}
+		return nil
+	}
+	if !errors.Is(err, ErrVectorDBOwnerNotFound) {
+		return err
+	}
+
+	return r.store.Save(models.VectorDBOwner{
+		VectorDBID: vectorDBID,
+		UserID:     userID,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
+
+// GetOwner returns the owner of vectorDBID, empty when it has none.
+func (r *VectorDBOwnerRepository) GetOwner(vectorDBID string) (string, error) {
+	owner, err := r.store.Get(vectorDBID)
+	if errors.Is(err, ErrVectorDBOwnerNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return owner.UserID, nil
+}
+
+// GetOwners returns the owners of all vector databases that have one by vector database id.
+func (r *VectorDBOwnerRepository) GetOwners() (map[string]string, error) {
+	owners, err := r.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(owners))
+	for _, owner := range owners {
+		result[owner.VectorDBID] = owner.UserID
+	}
+	return result, nil
+}
+
+// DeleteOwner forgets the owner of vectorDBID, it is not an error when it has none.
+func (r *VectorDBOwnerRepository) DeleteOwner(vectorDBID string) error {
+	if err := r.store.Delete(vectorDBID); err != nil && !errors.Is(err, ErrVectorDBOwnerNotFound) {
+		return err
+	}
+	return nil
+}
+
+// NewMemoryVectorDBOwnerStore keeps vector database owners in memory only, they are lost on
+// restart.
+func NewMemoryVectorDBOwnerStore() VectorDBOwnerStore {
+	return newVectorDBOwnerMemoryStore()
+}
+
+// NewFileVectorDBOwnerStore keeps vector database owners in memory and writes them to the JSON
+// file at path after every change, the owners already stored there are loaded.
+func NewFileVectorDBOwnerStore(path string) (VectorDBOwnerStore, error) {
+	return newJSONFileStore(path, "vector database owner", newVectorDBOwnerMemoryStore())
+}
+
+func newVectorDBOwnerMemoryStore() *memoryStore[models.VectorDBOwner] {
+	return newMemoryStore(
+		func(owner models.VectorDBOwner) string { return owner.VectorDBID },
+		func(owner models.VectorDBOwner) time.Time { return owner.CreatedAt },
+		ErrVectorDBOwnerNotFound,
+	)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOwnerKeepsExistingOwner(t *testing.T) {
+	repository := NewVectorDBOwnerRepository(NewMemoryVectorDBOwnerStore())
+
+	assert.NoError(t, repository.SetOwner("alice-docs", "alice"))
+	// Registering again is idempotent for the owner.
+	assert.NoError(t, repository.SetOwner("alice-docs", "alice"))
+	assert.ErrorIs(t, repository.SetOwner("alice-docs", "bob"), ErrVectorDBOwnerExists)
+
+	owner, err := repository.GetOwner("alice-docs")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+
+	// Once the vector database is unregistered its id is free again.
+	assert.NoError(t, repository.DeleteOwner("alice-docs"))
+	assert.NoError(t, repository.SetOwner("alice-docs", "bob"))
+
+	owner, err = repository.GetOwner("alice-docs")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", owner)
+}
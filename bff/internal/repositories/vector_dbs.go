@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
 	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
@@ -14,7 +17,9 @@ const vectorDBsPath = "/v1/vector-dbs"
 // Used on the FE side to interact with the vectorDB API.
 type VectorDBInterface interface {
 	GetAllVectorDBs(client integrations.HTTPClientInterface) (*llamastack.VectorDBList, error)
+	GetVectorDB(client integrations.HTTPClientInterface, vectorDBID string) (*llamastack.VectorDB, error)
 	RegisterVectorDB(client integrations.HTTPClientInterface, vectorDB llamastack.VectorDB, embeddingModel string) error
+	UnregisterVectorDB(client integrations.HTTPClientInterface, vectorDBID string) error
 }
 
 type UIVectorDB struct {
@@ -39,13 +44,22 @@ func (m UIVectorDB) GetAllVectorDBs(client integrations.HTTPClientInterface) (*l
 type VectorDBRegistrationRequest struct {
 	VectorDBID     string `json:"vector_db_id"`
 	EmbeddingModel string `json:"embedding_model"`
+	// Llama Stack picks defaults for the fields below when they are left out.
+	EmbeddingDimension int64  `json:"embedding_dimension,omitempty"`
+	ProviderID         string `json:"provider_id,omitempty"`
+	ProviderVectorDBID string `json:"provider_vector_db_id,omitempty"`
 }
 
+// RegisterVectorDB registers vectorDB, its provider, embedding dimension and provider resource
+// id are sent when set.
 func (m UIVectorDB) RegisterVectorDB(client integrations.HTTPClientInterface, vectorDB llamastack.VectorDB, embeddingModel string) error {
 	// Create the request body with the required parameters
 	requestBody := VectorDBRegistrationRequest{
-		VectorDBID:     vectorDB.Identifier,
-		EmbeddingModel: embeddingModel,
+		VectorDBID:         vectorDB.Identifier,
+		EmbeddingModel:     embeddingModel,
+		EmbeddingDimension: vectorDB.EmbeddingDimension,
+		ProviderID:         vectorDB.ProviderID,
+		ProviderVectorDBID: vectorDB.ProviderResourceID,
 	}
 
 	// Marshal the request body to JSON
@@ -68,3 +82,38 @@ func (m UIVectorDB) RegisterVectorDB(client integrations.HTTPClientInterface, ve
 
 	return nil
 }
+
+func (m UIVectorDB) GetVectorDB(client integrations.HTTPClientInterface, vectorDBID string) (*llamastack.VectorDB, error) {
+	response, err := client.GET(vectorDBsPath + "/" + url.PathEscape(vectorDBID))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve vector database: %w", err)
+	}
+
+	var vectorDB *llamastack.VectorDB
+	if err := json.Unmarshal(response, &vectorDB); err != nil {
+		return nil, fmt.Errorf("error decoding response data: %w", err)
+	}
+
+	// Some Llama Stack versions answer null rather than 404 for unknown vector databases.
+	if vectorDB == nil {
+		return nil, &integrations.HTTPError{
+			StatusCode: http.StatusNotFound,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusNotFound),
+				Message: fmt.Sprintf("vector database %s not found", vectorDBID),
+			},
+		}
+	}
+
+	return vectorDB, nil
+}
+
+func (m UIVectorDB) UnregisterVectorDB(client integrations.HTTPClientInterface, vectorDBID string) error {
+	_, err := client.DELETE(vectorDBsPath + "/" + url.PathEscape(vectorDBID))
+	if err != nil {
+		return fmt.Errorf("failed to unregister vector database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations"
+	"github.com/opendatahub-io/llama-stack-modular-ui/internal/integrations/llamastack"
+)
+
+const insertVectorIOPath = "/v1/vector-io/insert"
+
+// VectorIOInterface inserts chunks the BFF cut itself, bypassing the chunking of the RAG tool.
+type VectorIOInterface interface {
+	InsertChunks(client integrations.HTTPClientInterface, request llamastack.VectorIOInsertRequest) error
+}
+
+type UIVectorIO struct {
+}
+
+func (v UIVectorIO) InsertChunks(client integrations.HTTPClientInterface, request llamastack.VectorIOInsertRequest) error {
+	jsonBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	if _, err := client.POST(insertVectorIOPath, bytes.NewReader(jsonBody)); err != nil {
+		return fmt.Errorf("failed to insert chunks: %w", err)
+	}
+
+	return nil
+}